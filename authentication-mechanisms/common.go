@@ -0,0 +1,13 @@
+package authenticationMechanisms
+
+import (
+	"errors"
+)
+
+var (
+	// ErrInvalidCredentials should be returned by the verifiers
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+	ErrUnexpectedChallenge  = errors.New("unexpected challenge")
+	ErrExchangeIsCompleted  = errors.New("exchange is completed")
+	ErrInvalidMessageFormat = errors.New("invalid message format")
+)
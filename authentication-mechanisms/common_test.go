@@ -0,0 +1,37 @@
+package authenticationMechanisms
+
+import (
+	"context"
+
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+// runTestExchange passes the messages between the exchanges directly,
+// as `TCPClient.Authenticate()` and the connection handler do
+func runTestExchange(
+	clientExchange tcpServer.ClientAuthenticationExchange,
+	serverExchange tcpServer.ServerAuthenticationExchange,
+) (tcpServer.Principal, error) {
+	var challenge []byte
+	for {
+		data, err := clientExchange.Step(challenge)
+		if err != nil {
+			return tcpServer.Principal{}, err
+		}
+
+		result, err := serverExchange.Step(context.Background(), data)
+		if err != nil {
+			return tcpServer.Principal{}, err
+		}
+
+		if principal, isPresent := result.Principal.Get(); isPresent {
+			if err := clientExchange.Complete(result.Data); err != nil {
+				return tcpServer.Principal{}, err
+			}
+
+			return principal, nil
+		}
+
+		challenge = result.Data
+	}
+}
@@ -0,0 +1,122 @@
+package authenticationMechanisms
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/samber/mo"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+const (
+	PlainMechanismName = "PLAIN"
+)
+
+type PlainCredentials struct {
+	// AuthorizationID is the identity to act as; it's optional
+	AuthorizationID string
+	Username        string
+	Password        string
+}
+
+type PlainCredentialsVerifier func(
+	ctx context.Context,
+	credentials PlainCredentials,
+) (tcpServer.Principal, error)
+
+// PlainServerMechanism implements the PLAIN mechanism (RFC 4616);
+// it transfers the password as is, so it should be used
+// only over the encrypted connections
+type PlainServerMechanism struct {
+	verifier PlainCredentialsVerifier
+}
+
+func NewPlainServerMechanism(
+	verifier PlainCredentialsVerifier,
+) PlainServerMechanism {
+	return PlainServerMechanism{
+		verifier: verifier,
+	}
+}
+
+func (mechanism PlainServerMechanism) Name() string {
+	return PlainMechanismName
+}
+
+func (mechanism PlainServerMechanism) NewExchange() tcpServer.ServerAuthenticationExchange { //nolint:lll
+	return plainServerExchange(mechanism)
+}
+
+type plainServerExchange struct {
+	verifier PlainCredentialsVerifier
+}
+
+func (exchange plainServerExchange) Step(
+	ctx context.Context,
+	data []byte,
+) (tcpServer.AuthenticationStepResult, error) {
+	parts := bytes.Split(data, []byte{0})
+	if len(parts) != 3 || len(parts[1]) == 0 {
+		return tcpServer.AuthenticationStepResult{}, ErrInvalidMessageFormat
+	}
+
+	principal, err := exchange.verifier(ctx, PlainCredentials{
+		AuthorizationID: string(parts[0]),
+		Username:        string(parts[1]),
+		Password:        string(parts[2]),
+	})
+	if err != nil {
+		return tcpServer.AuthenticationStepResult{}, fmt.Errorf(
+			"unable to verify the credentials: %w",
+			err,
+		)
+	}
+
+	return tcpServer.AuthenticationStepResult{
+		Principal: mo.Some(principal),
+	}, nil
+}
+
+type PlainClientMechanism struct {
+	credentials PlainCredentials
+}
+
+func NewPlainClientMechanism(
+	credentials PlainCredentials,
+) PlainClientMechanism {
+	return PlainClientMechanism{
+		credentials: credentials,
+	}
+}
+
+func (mechanism PlainClientMechanism) Name() string {
+	return PlainMechanismName
+}
+
+func (mechanism PlainClientMechanism) NewExchange() tcpServer.ClientAuthenticationExchange { //nolint:lll
+	return plainClientExchange(mechanism)
+}
+
+type plainClientExchange struct {
+	credentials PlainCredentials
+}
+
+func (exchange plainClientExchange) Step(challenge []byte) ([]byte, error) {
+	if challenge != nil {
+		return nil, ErrUnexpectedChallenge
+	}
+
+	return bytes.Join(
+		[][]byte{
+			[]byte(exchange.credentials.AuthorizationID),
+			[]byte(exchange.credentials.Username),
+			[]byte(exchange.credentials.Password),
+		},
+		[]byte{0},
+	), nil
+}
+
+func (exchange plainClientExchange) Complete(data []byte) error {
+	return nil
+}
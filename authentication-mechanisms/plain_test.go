@@ -0,0 +1,88 @@
+package authenticationMechanisms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestPlainMechanism_interface(test *testing.T) {
+	assert.Implements(
+		test,
+		(*tcpServer.ServerAuthenticationMechanism)(nil),
+		PlainServerMechanism{},
+	)
+	assert.Implements(
+		test,
+		(*tcpServer.ClientAuthenticationMechanism)(nil),
+		PlainClientMechanism{},
+	)
+}
+
+func TestPlainMechanism(test *testing.T) {
+	serverMechanism := NewPlainServerMechanism(func(
+		ctx context.Context,
+		credentials PlainCredentials,
+	) (tcpServer.Principal, error) {
+		if credentials.Username != "user" || credentials.Password != "password" {
+			return tcpServer.Principal{}, ErrInvalidCredentials
+		}
+
+		return tcpServer.Principal{Name: credentials.Username}, nil
+	})
+
+	for _, data := range []struct {
+		name          string
+		credentials   PlainCredentials
+		wantPrincipal tcpServer.Principal
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			credentials: PlainCredentials{
+				AuthorizationID: "another",
+				Username:        "user",
+				Password:        "password",
+			},
+			wantPrincipal: tcpServer.Principal{Name: "user"},
+			wantErr:       assert.NoError,
+		},
+		{
+			name: "error/with the wrong password",
+			credentials: PlainCredentials{
+				Username: "user",
+				Password: "wrong",
+			},
+			wantPrincipal: tcpServer.Principal{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidCredentials)
+			},
+		},
+		{
+			name: "error/without the username",
+			credentials: PlainCredentials{
+				Password: "password",
+			},
+			wantPrincipal: tcpServer.Principal{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidMessageFormat)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			clientMechanism := NewPlainClientMechanism(data.credentials)
+			assert.Equal(test, PlainMechanismName, clientMechanism.Name())
+			assert.Equal(test, PlainMechanismName, serverMechanism.Name())
+
+			got, err := runTestExchange(
+				clientMechanism.NewExchange(),
+				serverMechanism.NewExchange(),
+			)
+
+			assert.Equal(test, data.wantPrincipal, got)
+			data.wantErr(test, err)
+		})
+	}
+}
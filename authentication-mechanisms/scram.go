@@ -0,0 +1,485 @@
+package authenticationMechanisms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/samber/mo"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+const (
+	SCRAMSHA256MechanismName   = "SCRAM-SHA-256"
+	DefaultSCRAMIterationCount = 4096
+	// MaxSCRAMIterationCount limits the work the server can make the client do
+	MaxSCRAMIterationCount = 1_000_000
+
+	scramNonceSize    = 18
+	scramFakeSaltSize = 16
+	// the channel binding isn't supported
+	scramGS2Header = "n,,"
+)
+
+var (
+	ErrServerSignatureIsInvalid = errors.New("server signature is invalid")
+	ErrNonceIsMismatched        = errors.New("nonce is mismatched")
+	ErrIterationCountIsTooLarge = errors.New("iteration count is too large")
+)
+
+// SCRAMCredentials are stored by the server instead of the password
+type SCRAMCredentials struct {
+	Salt           []byte
+	IterationCount int
+	StoredKey      []byte
+	ServerKey      []byte
+	Principal      tcpServer.Principal
+}
+
+// NewSCRAMCredentials derives the credentials from the password;
+// the password is used as is, without the SASLprep normalization
+func NewSCRAMCredentials(
+	password string,
+	salt []byte,
+	iterationCount int,
+) SCRAMCredentials {
+	saltedPassword := saltPassword(password, salt, iterationCount)
+	return SCRAMCredentials{
+		Salt:           salt,
+		IterationCount: iterationCount,
+		StoredKey:      hashSum(computeHMAC(saltedPassword, "Client Key")),
+		ServerKey:      computeHMAC(saltedPassword, "Server Key"),
+	}
+}
+
+type SCRAMCredentialsLookup func(
+	ctx context.Context,
+	username string,
+) (SCRAMCredentials, error)
+
+// SCRAMSHA256ServerMechanism implements the SCRAM-SHA-256 mechanism
+// (RFC 5802, RFC 7677) without the channel binding;
+// the lookup errors are reported only at the final step and the unknown users
+// get the fake salt, so the users can't be enumerated (RFC 5802, section 5.1)
+type SCRAMSHA256ServerMechanism struct {
+	lookup      SCRAMCredentialsLookup
+	fakeSaltKey []byte
+}
+
+func NewSCRAMSHA256ServerMechanism(
+	lookup SCRAMCredentialsLookup,
+) SCRAMSHA256ServerMechanism {
+	// the fake salts are stable for the mechanism, but unpredictable
+	fakeSaltKey := make([]byte, sha256.Size)
+	rand.Read(fakeSaltKey) //nolint:errcheck
+
+	return SCRAMSHA256ServerMechanism{
+		lookup:      lookup,
+		fakeSaltKey: fakeSaltKey,
+	}
+}
+
+func (mechanism SCRAMSHA256ServerMechanism) Name() string {
+	return SCRAMSHA256MechanismName
+}
+
+func (mechanism SCRAMSHA256ServerMechanism) NewExchange() tcpServer.ServerAuthenticationExchange { //nolint:lll
+	return &scramServerExchange{
+		lookup:      mechanism.lookup,
+		fakeSaltKey: mechanism.fakeSaltKey,
+	}
+}
+
+type scramServerExchange struct {
+	lookup          SCRAMCredentialsLookup
+	fakeSaltKey     []byte
+	lookupErr       error
+	credentials     mo.Option[SCRAMCredentials]
+	clientFirstBare string
+	serverFirst     string
+	nonce           string
+	isCompleted     bool
+}
+
+func (exchange *scramServerExchange) Step(
+	ctx context.Context,
+	data []byte,
+) (tcpServer.AuthenticationStepResult, error) {
+	if exchange.isCompleted {
+		return tcpServer.AuthenticationStepResult{}, ErrExchangeIsCompleted
+	}
+
+	credentials, isPresent := exchange.credentials.Get()
+	if !isPresent {
+		return exchange.handleClientFirst(ctx, string(data))
+	}
+
+	return exchange.handleClientFinal(credentials, string(data))
+}
+
+func (exchange *scramServerExchange) handleClientFirst(
+	ctx context.Context,
+	clientFirst string,
+) (tcpServer.AuthenticationStepResult, error) {
+	clientFirstBare, isFound := strings.CutPrefix(clientFirst, scramGS2Header)
+	if !isFound {
+		return tcpServer.AuthenticationStepResult{}, fmt.Errorf(
+			"%w: unsupported GS2 header",
+			ErrInvalidMessageFormat,
+		)
+	}
+
+	attributes, err := parseSCRAMAttributes(clientFirstBare)
+	if err != nil {
+		return tcpServer.AuthenticationStepResult{}, err
+	}
+
+	username, clientNonce := attributes['n'], attributes['r']
+	if username == "" || clientNonce == "" {
+		return tcpServer.AuthenticationStepResult{}, ErrInvalidMessageFormat
+	}
+
+	credentials, err := exchange.lookup(ctx, unescapeSCRAMUsername(username))
+	if err != nil {
+		exchange.lookupErr = err
+		credentials = exchange.makeFakeCredentials(username)
+	}
+
+	serverNonce, err := makeSCRAMNonce()
+	if err != nil {
+		return tcpServer.AuthenticationStepResult{}, err
+	}
+
+	exchange.credentials = mo.Some(credentials)
+	exchange.clientFirstBare = clientFirstBare
+	exchange.nonce = clientNonce + serverNonce
+	exchange.serverFirst = fmt.Sprintf(
+		"r=%s,s=%s,i=%d",
+		exchange.nonce,
+		base64.StdEncoding.EncodeToString(credentials.Salt),
+		credentials.IterationCount,
+	)
+
+	return tcpServer.AuthenticationStepResult{
+		Data: []byte(exchange.serverFirst),
+	}, nil
+}
+
+func (exchange *scramServerExchange) handleClientFinal(
+	credentials SCRAMCredentials,
+	clientFinal string,
+) (tcpServer.AuthenticationStepResult, error) {
+	proofIndex := strings.LastIndex(clientFinal, ",p=")
+	if proofIndex == -1 {
+		return tcpServer.AuthenticationStepResult{}, ErrInvalidMessageFormat
+	}
+
+	clientFinalWithoutProof := clientFinal[:proofIndex]
+	attributes, err := parseSCRAMAttributes(clientFinalWithoutProof)
+	if err != nil {
+		return tcpServer.AuthenticationStepResult{}, err
+	}
+
+	channelBinding := base64.StdEncoding.EncodeToString([]byte(scramGS2Header))
+	if attributes['c'] != channelBinding {
+		return tcpServer.AuthenticationStepResult{}, fmt.Errorf(
+			"%w: invalid channel binding",
+			ErrInvalidMessageFormat,
+		)
+	}
+	if attributes['r'] != exchange.nonce {
+		return tcpServer.AuthenticationStepResult{}, ErrNonceIsMismatched
+	}
+
+	proof, err := base64.StdEncoding.DecodeString(
+		clientFinal[proofIndex+len(",p="):],
+	)
+	if err != nil {
+		return tcpServer.AuthenticationStepResult{}, fmt.Errorf(
+			"%w: unable to decode the proof: %w",
+			ErrInvalidMessageFormat,
+			err,
+		)
+	}
+
+	authMessage := exchange.clientFirstBare + "," +
+		exchange.serverFirst + "," +
+		clientFinalWithoutProof
+	invalidCredentialsErr := exchange.makeInvalidCredentialsErr()
+	clientSignature := computeHMAC(credentials.StoredKey, authMessage)
+	if len(proof) != len(clientSignature) {
+		return tcpServer.AuthenticationStepResult{}, invalidCredentialsErr
+	}
+
+	// the unknown user fails only here, after the same computations
+	clientKey := xorBytes(proof, clientSignature)
+	if !hmac.Equal(hashSum(clientKey), credentials.StoredKey) ||
+		exchange.lookupErr != nil {
+		return tcpServer.AuthenticationStepResult{}, invalidCredentialsErr
+	}
+
+	exchange.isCompleted = true
+
+	serverSignature := computeHMAC(credentials.ServerKey, authMessage)
+	return tcpServer.AuthenticationStepResult{
+		Data:      []byte("v=" + base64.StdEncoding.EncodeToString(serverSignature)),
+		Principal: mo.Some(credentials.Principal),
+	}, nil
+}
+
+// makeFakeCredentials derives the salt from the username,
+// so the repeated attempts for the same unknown user get the same one
+func (exchange *scramServerExchange) makeFakeCredentials(
+	username string,
+) SCRAMCredentials {
+	salt := computeHMAC(exchange.fakeSaltKey, username)[:scramFakeSaltSize]
+	return SCRAMCredentials{
+		Salt:           salt,
+		IterationCount: DefaultSCRAMIterationCount,
+		StoredKey:      computeHMAC(exchange.fakeSaltKey, "Stored Key"),
+		ServerKey:      computeHMAC(exchange.fakeSaltKey, "Server Key"),
+	}
+}
+
+func (exchange *scramServerExchange) makeInvalidCredentialsErr() error {
+	if exchange.lookupErr != nil {
+		return fmt.Errorf(
+			"%w: unable to look up the credentials: %w",
+			ErrInvalidCredentials,
+			exchange.lookupErr,
+		)
+	}
+
+	return ErrInvalidCredentials
+}
+
+type SCRAMSHA256ClientMechanism struct {
+	username string
+	password string
+}
+
+func NewSCRAMSHA256ClientMechanism(
+	username string,
+	password string,
+) SCRAMSHA256ClientMechanism {
+	return SCRAMSHA256ClientMechanism{
+		username: username,
+		password: password,
+	}
+}
+
+func (mechanism SCRAMSHA256ClientMechanism) Name() string {
+	return SCRAMSHA256MechanismName
+}
+
+func (mechanism SCRAMSHA256ClientMechanism) NewExchange() tcpServer.ClientAuthenticationExchange { //nolint:lll
+	return &scramClientExchange{
+		username: mechanism.username,
+		password: mechanism.password,
+	}
+}
+
+type scramClientExchange struct {
+	username                string
+	password                string
+	clientNonce             string
+	clientFirstBare         string
+	expectedServerSignature mo.Option[[]byte]
+}
+
+func (exchange *scramClientExchange) Step(challenge []byte) ([]byte, error) {
+	if challenge == nil {
+		return exchange.makeClientFirst()
+	}
+	if exchange.clientFirstBare == "" ||
+		exchange.expectedServerSignature.IsPresent() {
+		return nil, ErrUnexpectedChallenge
+	}
+
+	return exchange.makeClientFinal(string(challenge))
+}
+
+func (exchange *scramClientExchange) Complete(data []byte) error {
+	expectedServerSignature, isPresent :=
+		exchange.expectedServerSignature.Get()
+	if !isPresent {
+		return ErrServerSignatureIsInvalid
+	}
+
+	attributes, err := parseSCRAMAttributes(string(data))
+	if err != nil {
+		return err
+	}
+
+	serverSignature, err := base64.StdEncoding.DecodeString(attributes['v'])
+	if err != nil {
+		return fmt.Errorf(
+			"%w: unable to decode the server signature: %w",
+			ErrInvalidMessageFormat,
+			err,
+		)
+	}
+	if !hmac.Equal(serverSignature, expectedServerSignature) {
+		return ErrServerSignatureIsInvalid
+	}
+
+	return nil
+}
+
+func (exchange *scramClientExchange) makeClientFirst() ([]byte, error) {
+	clientNonce, err := makeSCRAMNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	exchange.clientNonce = clientNonce
+	exchange.clientFirstBare = fmt.Sprintf(
+		"n=%s,r=%s",
+		escapeSCRAMUsername(exchange.username),
+		clientNonce,
+	)
+
+	return []byte(scramGS2Header + exchange.clientFirstBare), nil
+}
+
+func (exchange *scramClientExchange) makeClientFinal(
+	serverFirst string,
+) ([]byte, error) {
+	attributes, err := parseSCRAMAttributes(serverFirst)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := attributes['r']
+	if !strings.HasPrefix(nonce, exchange.clientNonce) ||
+		len(nonce) == len(exchange.clientNonce) {
+		return nil, ErrNonceIsMismatched
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(attributes['s'])
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: unable to decode the salt: %w",
+			ErrInvalidMessageFormat,
+			err,
+		)
+	}
+
+	iterationCount, err := strconv.Atoi(attributes['i'])
+	if err != nil || iterationCount < 1 {
+		return nil, fmt.Errorf(
+			"%w: invalid iteration count",
+			ErrInvalidMessageFormat,
+		)
+	}
+	if iterationCount > MaxSCRAMIterationCount {
+		return nil, fmt.Errorf(
+			"%w: %d > %d",
+			ErrIterationCountIsTooLarge,
+			iterationCount,
+			MaxSCRAMIterationCount,
+		)
+	}
+
+	saltedPassword := saltPassword(exchange.password, salt, iterationCount)
+	clientKey := computeHMAC(saltedPassword, "Client Key")
+	clientFinalWithoutProof := fmt.Sprintf(
+		"c=%s,r=%s",
+		base64.StdEncoding.EncodeToString([]byte(scramGS2Header)),
+		nonce,
+	)
+	authMessage := exchange.clientFirstBare + "," +
+		serverFirst + "," +
+		clientFinalWithoutProof
+	clientSignature := computeHMAC(hashSum(clientKey), authMessage)
+	exchange.expectedServerSignature = mo.Some(computeHMAC(
+		computeHMAC(saltedPassword, "Server Key"),
+		authMessage,
+	))
+
+	proof := xorBytes(clientKey, clientSignature)
+	return []byte(
+		clientFinalWithoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof),
+	), nil
+}
+
+func parseSCRAMAttributes(message string) (map[byte]string, error) {
+	attributes := make(map[byte]string)
+	for _, attribute := range strings.Split(message, ",") {
+		if len(attribute) < 2 || attribute[1] != '=' {
+			return nil, fmt.Errorf(
+				"%w: invalid attribute %q",
+				ErrInvalidMessageFormat,
+				attribute,
+			)
+		}
+
+		attributes[attribute[0]] = attribute[2:]
+	}
+
+	return attributes, nil
+}
+
+func escapeSCRAMUsername(username string) string {
+	return strings.NewReplacer("=", "=3D", ",", "=2C").Replace(username)
+}
+
+func unescapeSCRAMUsername(username string) string {
+	return strings.NewReplacer("=2C", ",", "=3D", "=").Replace(username)
+}
+
+func makeSCRAMNonce() (string, error) {
+	nonce := make([]byte, scramNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("unable to generate the nonce: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(nonce), nil
+}
+
+// saltPassword is the Hi() function of RFC 5802,
+// i.e. PBKDF2-HMAC-SHA256 with the single-block output
+func saltPassword(password string, salt []byte, iterationCount int) []byte {
+	mac := hmac.New(sha256.New, []byte(password))
+	mac.Write(salt)               //nolint:errcheck
+	mac.Write([]byte{0, 0, 0, 1}) //nolint:errcheck
+
+	block := mac.Sum(nil)
+	result := block
+	for range iterationCount - 1 {
+		mac.Reset()
+		mac.Write(block) //nolint:errcheck
+		block = mac.Sum(nil)
+
+		result = xorBytes(result, block)
+	}
+
+	return result
+}
+
+func computeHMAC(key []byte, message string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(message)) //nolint:errcheck
+
+	return mac.Sum(nil)
+}
+
+func hashSum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+func xorBytes(data []byte, another []byte) []byte {
+	result := make([]byte, len(data))
+	for index := range data {
+		result[index] = data[index] ^ another[index]
+	}
+
+	return result
+}
@@ -0,0 +1,228 @@
+package authenticationMechanisms
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestSCRAMSHA256Mechanism_interface(test *testing.T) {
+	assert.Implements(
+		test,
+		(*tcpServer.ServerAuthenticationMechanism)(nil),
+		SCRAMSHA256ServerMechanism{},
+	)
+	assert.Implements(
+		test,
+		(*tcpServer.ClientAuthenticationMechanism)(nil),
+		SCRAMSHA256ClientMechanism{},
+	)
+}
+
+func TestSCRAMSHA256Mechanism(test *testing.T) {
+	errUnknownUser := errors.New("unknown user")
+	serverMechanism := NewSCRAMSHA256ServerMechanism(func(
+		ctx context.Context,
+		username string,
+	) (SCRAMCredentials, error) {
+		if username != "user" && username != "us=er,name" {
+			return SCRAMCredentials{}, errUnknownUser
+		}
+
+		credentials := NewSCRAMCredentials(
+			"password",
+			[]byte("salt"),
+			DefaultSCRAMIterationCount,
+		)
+		credentials.Principal = tcpServer.Principal{Name: username}
+
+		return credentials, nil
+	})
+
+	type args struct {
+		username string
+		password string
+	}
+
+	for _, data := range []struct {
+		name          string
+		args          args
+		wantPrincipal tcpServer.Principal
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				username: "user",
+				password: "password",
+			},
+			wantPrincipal: tcpServer.Principal{Name: "user"},
+			wantErr:       assert.NoError,
+		},
+		{
+			name: "success/with the escaped username",
+			args: args{
+				username: "us=er,name",
+				password: "password",
+			},
+			wantPrincipal: tcpServer.Principal{Name: "us=er,name"},
+			wantErr:       assert.NoError,
+		},
+		{
+			name: "error/with the wrong password",
+			args: args{
+				username: "user",
+				password: "wrong",
+			},
+			wantPrincipal: tcpServer.Principal{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidCredentials)
+			},
+		},
+		{
+			name: "error/with the unknown user",
+			args: args{
+				username: "unknown",
+				password: "password",
+			},
+			wantPrincipal: tcpServer.Principal{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidCredentials) &&
+					assert.ErrorIs(test, err, errUnknownUser)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			clientMechanism :=
+				NewSCRAMSHA256ClientMechanism(data.args.username, data.args.password)
+			assert.Equal(test, SCRAMSHA256MechanismName, clientMechanism.Name())
+			assert.Equal(test, SCRAMSHA256MechanismName, serverMechanism.Name())
+
+			got, err := runTestExchange(
+				clientMechanism.NewExchange(),
+				serverMechanism.NewExchange(),
+			)
+
+			assert.Equal(test, data.wantPrincipal, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestSCRAMServerExchange_withUnknownUser(test *testing.T) {
+	serverMechanism := NewSCRAMSHA256ServerMechanism(func(
+		ctx context.Context,
+		username string,
+	) (SCRAMCredentials, error) {
+		return SCRAMCredentials{}, errors.New("unknown user")
+	})
+
+	getSalt := func(username string) string {
+		result, err := serverMechanism.NewExchange().Step(
+			context.Background(),
+			[]byte("n,,n="+username+",r=nonce"),
+		)
+		require.NoError(test, err)
+
+		attributes, err := parseSCRAMAttributes(string(result.Data))
+		require.NoError(test, err)
+		assert.Equal(test, "4096", attributes['i'])
+
+		return attributes['s']
+	}
+
+	// the fake salt is indistinguishable from the stored one
+	assert.Equal(test, getSalt("unknown"), getSalt("unknown"))
+	assert.NotEqual(test, getSalt("unknown"), getSalt("another"))
+}
+
+func TestSCRAMClientExchange_withInvalidIterationCount(test *testing.T) {
+	for _, data := range []struct {
+		name           string
+		iterationCount string
+		wantErr        assert.ErrorAssertionFunc
+	}{
+		{
+			name:           "error/with the zero iteration count",
+			iterationCount: "0",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidMessageFormat)
+			},
+		},
+		{
+			name:           "error/with the too large iteration count",
+			iterationCount: "1000000000",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrIterationCountIsTooLarge)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			exchange := &scramClientExchange{
+				username:        "user",
+				password:        "pencil",
+				clientNonce:     "nonce",
+				clientFirstBare: "n=user,r=nonce",
+			}
+
+			_, err := exchange.Step(
+				[]byte("r=nonce%server,s=c2FsdA==,i=" + data.iterationCount),
+			)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+// the test vector is taken from RFC 7677, section 3
+func TestSCRAMClientExchange_withTestVector(test *testing.T) {
+	for _, data := range []struct {
+		name            string
+		serverSignature string
+		wantErr         assert.ErrorAssertionFunc
+	}{
+		{
+			name:            "success",
+			serverSignature: "6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4=",
+			wantErr:         assert.NoError,
+		},
+		{
+			name: "error/with the wrong server signature",
+			serverSignature: base64.StdEncoding.EncodeToString(
+				[]byte("wrong server signature"),
+			),
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrServerSignatureIsInvalid)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			exchange := &scramClientExchange{
+				username:        "user",
+				password:        "pencil",
+				clientNonce:     "rOprNGfwEbeRWgbNEkqO",
+				clientFirstBare: "n=user,r=rOprNGfwEbeRWgbNEkqO",
+			}
+
+			clientFinal, err := exchange.Step([]byte(
+				"r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0," +
+					"s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096",
+			))
+			require.NoError(test, err)
+
+			assert.Equal(
+				test,
+				"c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,"+
+					"p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=",
+				string(clientFinal),
+			)
+
+			err = exchange.Complete([]byte("v=" + data.serverSignature))
+			data.wantErr(test, err)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package authenticationMechanisms
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/samber/mo"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+const (
+	TokenMechanismName = "TOKEN"
+)
+
+type TokenVerifier func(
+	ctx context.Context,
+	token string,
+) (tcpServer.Principal, error)
+
+// TokenServerMechanism accepts the bearer tokens (e.g. API keys or JWTs)
+type TokenServerMechanism struct {
+	verifier TokenVerifier
+}
+
+func NewTokenServerMechanism(verifier TokenVerifier) TokenServerMechanism {
+	return TokenServerMechanism{
+		verifier: verifier,
+	}
+}
+
+func (mechanism TokenServerMechanism) Name() string {
+	return TokenMechanismName
+}
+
+func (mechanism TokenServerMechanism) NewExchange() tcpServer.ServerAuthenticationExchange { //nolint:lll
+	return tokenServerExchange(mechanism)
+}
+
+type tokenServerExchange struct {
+	verifier TokenVerifier
+}
+
+func (exchange tokenServerExchange) Step(
+	ctx context.Context,
+	data []byte,
+) (tcpServer.AuthenticationStepResult, error) {
+	if len(data) == 0 {
+		return tcpServer.AuthenticationStepResult{}, ErrInvalidMessageFormat
+	}
+
+	principal, err := exchange.verifier(ctx, string(data))
+	if err != nil {
+		return tcpServer.AuthenticationStepResult{}, fmt.Errorf(
+			"unable to verify the token: %w",
+			err,
+		)
+	}
+
+	return tcpServer.AuthenticationStepResult{
+		Principal: mo.Some(principal),
+	}, nil
+}
+
+type TokenClientMechanism struct {
+	token string
+}
+
+func NewTokenClientMechanism(token string) TokenClientMechanism {
+	return TokenClientMechanism{
+		token: token,
+	}
+}
+
+func (mechanism TokenClientMechanism) Name() string {
+	return TokenMechanismName
+}
+
+func (mechanism TokenClientMechanism) NewExchange() tcpServer.ClientAuthenticationExchange { //nolint:lll
+	return tokenClientExchange(mechanism)
+}
+
+type tokenClientExchange struct {
+	token string
+}
+
+func (exchange tokenClientExchange) Step(challenge []byte) ([]byte, error) {
+	if challenge != nil {
+		return nil, ErrUnexpectedChallenge
+	}
+
+	return []byte(exchange.token), nil
+}
+
+func (exchange tokenClientExchange) Complete(data []byte) error {
+	return nil
+}
@@ -0,0 +1,79 @@
+package authenticationMechanisms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestTokenMechanism_interface(test *testing.T) {
+	assert.Implements(
+		test,
+		(*tcpServer.ServerAuthenticationMechanism)(nil),
+		TokenServerMechanism{},
+	)
+	assert.Implements(
+		test,
+		(*tcpServer.ClientAuthenticationMechanism)(nil),
+		TokenClientMechanism{},
+	)
+}
+
+func TestTokenMechanism(test *testing.T) {
+	serverMechanism := NewTokenServerMechanism(func(
+		ctx context.Context,
+		token string,
+	) (tcpServer.Principal, error) {
+		if token != "token" {
+			return tcpServer.Principal{}, ErrInvalidCredentials
+		}
+
+		return tcpServer.Principal{Name: "service"}, nil
+	})
+
+	for _, data := range []struct {
+		name          string
+		token         string
+		wantPrincipal tcpServer.Principal
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "success",
+			token:         "token",
+			wantPrincipal: tcpServer.Principal{Name: "service"},
+			wantErr:       assert.NoError,
+		},
+		{
+			name:          "error/with the wrong token",
+			token:         "wrong",
+			wantPrincipal: tcpServer.Principal{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidCredentials)
+			},
+		},
+		{
+			name:          "error/with the empty token",
+			token:         "",
+			wantPrincipal: tcpServer.Principal{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidMessageFormat)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			clientMechanism := NewTokenClientMechanism(data.token)
+			assert.Equal(test, TokenMechanismName, clientMechanism.Name())
+			assert.Equal(test, TokenMechanismName, serverMechanism.Name())
+
+			got, err := runTestExchange(
+				clientMechanism.NewExchange(),
+				serverMechanism.NewExchange(),
+			)
+
+			assert.Equal(test, data.wantPrincipal, got)
+			data.wantErr(test, err)
+		})
+	}
+}
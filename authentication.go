@@ -0,0 +1,318 @@
+package tcpServer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/samber/mo"
+)
+
+const (
+	DefaultMaxAuthenticationAttempts = 3
+)
+
+var (
+	ErrAuthenticationIsRequired      = errors.New("authentication is required")
+	ErrAuthenticationIsFailed        = errors.New("authentication is failed")
+	ErrAlreadyAuthenticated          = errors.New("already authenticated")
+	ErrTooManyAuthenticationAttempts = errors.New(
+		"too many authentication attempts",
+	)
+	ErrUnknownAuthenticationMechanism = errors.New(
+		"unknown authentication mechanism",
+	)
+	ErrUnexpectedAuthenticationResponse = errors.New(
+		"unexpected authentication response",
+	)
+)
+
+// Principal is the authenticated identity of the peer
+type Principal struct {
+	Name   string
+	Roles  []string
+	Scopes []string
+}
+
+type principalContextKey struct{}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, isPresent := ctx.Value(principalContextKey{}).(Principal)
+	return principal, isPresent
+}
+
+func ContextWithPrincipal(
+	ctx context.Context,
+	principal Principal,
+) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// AuthenticationMessage is sent by the client; the mechanism is specified
+// only in the first message of the exchange
+type AuthenticationMessage struct {
+	Mechanism string
+	Data      []byte
+}
+
+type AuthenticationStatus string
+
+const (
+	ChallengeAuthenticationStatus AuthenticationStatus = "challenge"
+	SucceededAuthenticationStatus AuthenticationStatus = "succeeded"
+	FailedAuthenticationStatus    AuthenticationStatus = "failed"
+)
+
+// AuthenticationReply is sent by the server; the data of the succeeded reply
+// is the additional data of the mechanism (e.g. the SCRAM server signature),
+// and the one of the failed reply is the reason
+type AuthenticationReply struct {
+	Status AuthenticationStatus
+	Data   []byte
+}
+
+type AuthenticationProtocol[Req Request, Resp Response] interface {
+	NewAuthenticationRequest(message AuthenticationMessage) (Req, error)
+	AuthenticationRequest(request Req) mo.Option[AuthenticationMessage]
+	NewAuthenticationResponse(reply AuthenticationReply) (Resp, error)
+	AuthenticationReply(response Resp) mo.Option[AuthenticationReply]
+}
+
+// AuthenticationStepResult contains the challenge for the client,
+// or the principal with the additional data once the exchange is completed
+type AuthenticationStepResult struct {
+	Data      []byte
+	Principal mo.Option[Principal]
+}
+
+type ServerAuthenticationExchange interface {
+	Step(ctx context.Context, data []byte) (AuthenticationStepResult, error)
+}
+
+type ServerAuthenticationMechanism interface {
+	Name() string
+	NewExchange() ServerAuthenticationExchange
+}
+
+type ClientAuthenticationExchange interface {
+	// Step is called with the nil challenge for the initial response
+	Step(challenge []byte) ([]byte, error)
+	// Complete verifies the additional data of the succeeded reply
+	Complete(data []byte) error
+}
+
+type ClientAuthenticationMechanism interface {
+	Name() string
+	NewExchange() ClientAuthenticationExchange
+}
+
+// AuthenticationOptions makes the connection handler reject the requests
+// (except the pings) with the failed reply until the client completes
+// the exchange; the connection is closed after too many failed attempts
+type AuthenticationOptions[Req Request, Resp Response] struct {
+	Protocol    AuthenticationProtocol[Req, Resp]
+	Mechanisms  []ServerAuthenticationMechanism
+	MaxAttempts mo.Option[int]
+}
+
+type ClientAuthenticationOptions[Req Request, Resp Response] struct {
+	Protocol  AuthenticationProtocol[Req, Resp]
+	Mechanism ClientAuthenticationMechanism
+}
+
+// Authenticate performs the exchange via the regular requests,
+// so it should be called before any other ones
+func (client TCPClient[Req, Resp]) Authenticate(
+	options ClientAuthenticationOptions[Req, Resp],
+) error {
+	exchange := options.Mechanism.NewExchange()
+	data, err := exchange.Step(nil)
+	if err != nil {
+		return fmt.Errorf("unable to make the initial response: %w", err)
+	}
+
+	message := AuthenticationMessage{
+		Mechanism: options.Mechanism.Name(),
+		Data:      data,
+	}
+	for {
+		request, err := options.Protocol.NewAuthenticationRequest(message)
+		if err != nil {
+			return fmt.Errorf(
+				"unable to construct the authentication request: %w",
+				err,
+			)
+		}
+
+		response, err := client.SendRequest(request)
+		if err != nil {
+			return fmt.Errorf("unable to send the authentication request: %w", err)
+		}
+
+		reply, isPresent := options.Protocol.AuthenticationReply(response).Get()
+		if !isPresent {
+			return ErrUnexpectedAuthenticationResponse
+		}
+
+		switch reply.Status {
+		case ChallengeAuthenticationStatus:
+			data, err := exchange.Step(reply.Data)
+			if err != nil {
+				return fmt.Errorf("unable to respond to the challenge: %w", err)
+			}
+
+			message = AuthenticationMessage{Data: data}
+
+		case SucceededAuthenticationStatus:
+			if err := exchange.Complete(reply.Data); err != nil {
+				return fmt.Errorf("unable to complete the exchange: %w", err)
+			}
+
+			return nil
+
+		default:
+			return fmt.Errorf("%w: %s", ErrAuthenticationIsFailed, reply.Data)
+		}
+	}
+}
+
+type serverAuthentication struct {
+	exchange    mo.Option[ServerAuthenticationExchange]
+	failedCount int
+}
+
+// authenticateRequest returns true if the request is consumed
+// by the authentication
+func (handler DefaultConnectionHandler[Req, Resp]) authenticateRequest(
+	ctx context.Context,
+	session *Session[Req, Resp],
+	options AuthenticationOptions[Req, Resp],
+	request Req,
+) (bool, error) {
+	_, isAuthenticated := session.Principal()
+	message, isAuthenticationRequest :=
+		options.Protocol.AuthenticationRequest(request).Get()
+	if !isAuthenticationRequest {
+		if isAuthenticated {
+			return false, nil
+		}
+
+		if heartbeat, isPresent := handler.options.Heartbeat.Get(); isPresent &&
+			heartbeat.Protocol.IsPingRequest(request) {
+			return false, nil
+		}
+
+		return true, handler.writeAuthenticationReply(
+			session,
+			options,
+			FailedAuthenticationStatus,
+			[]byte(ErrAuthenticationIsRequired.Error()),
+		)
+	}
+	if isAuthenticated {
+		return true, handler.writeAuthenticationReply(
+			session,
+			options,
+			FailedAuthenticationStatus,
+			[]byte(ErrAlreadyAuthenticated.Error()),
+		)
+	}
+
+	result, err := session.authentication.step(ctx, options.Mechanisms, message)
+	if err != nil {
+		session.authentication = serverAuthentication{
+			failedCount: session.authentication.failedCount + 1,
+		}
+
+		if err := handler.writeAuthenticationReply(
+			session,
+			options,
+			FailedAuthenticationStatus,
+			[]byte(ErrAuthenticationIsFailed.Error()),
+		); err != nil {
+			return true, err
+		}
+
+		maxAttempts := options.MaxAttempts.OrElse(DefaultMaxAuthenticationAttempts)
+		if session.authentication.failedCount >= maxAttempts {
+			return true, ErrTooManyAuthenticationAttempts
+		}
+
+		return true, nil
+	}
+
+	principal, isPresent := result.Principal.Get()
+	if !isPresent {
+		return true, handler.writeAuthenticationReply(
+			session,
+			options,
+			ChallengeAuthenticationStatus,
+			result.Data,
+		)
+	}
+
+	session.authentication = serverAuthentication{}
+	session.principal.Store(&principal)
+
+	return true, handler.writeAuthenticationReply(
+		session,
+		options,
+		SucceededAuthenticationStatus,
+		result.Data,
+	)
+}
+
+func (handler DefaultConnectionHandler[Req, Resp]) writeAuthenticationReply(
+	session *Session[Req, Resp],
+	options AuthenticationOptions[Req, Resp],
+	status AuthenticationStatus,
+	data []byte,
+) error {
+	response, err := options.Protocol.NewAuthenticationResponse(
+		AuthenticationReply{Status: status, Data: data},
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"unable to construct the authentication response: %w",
+			err,
+		)
+	}
+
+	return session.writeResponse(response)
+}
+
+func (authentication *serverAuthentication) step(
+	ctx context.Context,
+	mechanisms []ServerAuthenticationMechanism,
+	message AuthenticationMessage,
+) (AuthenticationStepResult, error) {
+	if message.Mechanism != "" {
+		mechanism, err :=
+			findAuthenticationMechanism(mechanisms, message.Mechanism)
+		if err != nil {
+			return AuthenticationStepResult{}, err
+		}
+
+		authentication.exchange = mo.Some(mechanism.NewExchange())
+	}
+
+	exchange, isPresent := authentication.exchange.Get()
+	if !isPresent {
+		return AuthenticationStepResult{}, errors.New("exchange isn't started")
+	}
+
+	return exchange.Step(ctx, message.Data)
+}
+
+func findAuthenticationMechanism(
+	mechanisms []ServerAuthenticationMechanism,
+	name string,
+) (ServerAuthenticationMechanism, error) {
+	for _, mechanism := range mechanisms {
+		if mechanism.Name() == name {
+			return mechanism, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownAuthenticationMechanism, name)
+}
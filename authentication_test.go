@@ -0,0 +1,286 @@
+package tcpServer_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestPrincipalFromContext(test *testing.T) {
+	principal := tcpServer.Principal{Name: "user", Roles: []string{"admin"}}
+	ctx := tcpServer.ContextWithPrincipal(context.Background(), principal)
+
+	got, isPresent := tcpServer.PrincipalFromContext(ctx)
+
+	assert.True(test, isPresent)
+	assert.Equal(test, principal, got)
+}
+
+func TestAuthentication(test *testing.T) {
+	for _, data := range []struct {
+		name            string
+		clientMechanism mo.Option[tcpServer.ClientAuthenticationMechanism]
+		wantAuthErr     assert.ErrorAssertionFunc
+		wantResponse    string
+		wantHandlingErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			clientMechanism: mo.Some[tcpServer.ClientAuthenticationMechanism](
+				testClientAuthenticationMechanism{name: "TEST", answer: "secret"},
+			),
+			wantAuthErr:     assert.NoError,
+			wantResponse:    "user:request",
+			wantHandlingErr: assert.NoError,
+		},
+		{
+			name:            "error/without the authentication",
+			clientMechanism: mo.None[tcpServer.ClientAuthenticationMechanism](),
+			wantAuthErr:     assert.NoError,
+			wantResponse:    "failed:" + tcpServer.ErrAuthenticationIsRequired.Error(),
+			wantHandlingErr: assert.NoError,
+		},
+		{
+			name: "error/with the wrong answer",
+			clientMechanism: mo.Some[tcpServer.ClientAuthenticationMechanism](
+				testClientAuthenticationMechanism{name: "TEST", answer: "wrong"},
+			),
+			wantAuthErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(test, err, tcpServer.ErrAuthenticationIsFailed)
+			},
+			wantResponse: "",
+			wantHandlingErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(
+					test,
+					err,
+					tcpServer.ErrTooManyAuthenticationAttempts,
+				)
+			},
+		},
+		{
+			name: "error/with the unknown mechanism",
+			clientMechanism: mo.Some[tcpServer.ClientAuthenticationMechanism](
+				testClientAuthenticationMechanism{name: "UNKNOWN", answer: "secret"},
+			),
+			wantAuthErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(test, err, tcpServer.ErrAuthenticationIsFailed)
+			},
+			wantResponse: "",
+			wantHandlingErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(
+					test,
+					err,
+					tcpServer.ErrTooManyAuthenticationAttempts,
+				)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			serverConnection, clientConnection := net.Pipe()
+			defer clientConnection.Close()
+
+			handler := tcpServer.NewDefaultConnectionHandler(
+				tcpServer.DefaultConnectionHandlerOptions[string, string]{
+					Authentication: mo.Some(
+						tcpServer.AuthenticationOptions[string, string]{
+							Protocol: testAuthenticationProtocol{},
+							Mechanisms: []tcpServer.ServerAuthenticationMechanism{
+								testServerAuthenticationMechanism{},
+							},
+							MaxAttempts: mo.Some(1),
+						},
+					),
+					ServerProtocol: testLineProtocol{},
+					RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+						ctx context.Context,
+						request string,
+					) (string, error) {
+						principal, isPresent := tcpServer.PrincipalFromContext(ctx)
+						if !isPresent {
+							return "", errors.New("principal is missed")
+						}
+
+						return principal.Name + ":" + request, nil
+					}),
+				},
+			)
+
+			handlingErr := make(chan error, 1)
+			go func() {
+				defer serverConnection.Close()
+
+				handlingErr <- handler.HandleConnection(
+					context.Background(),
+					serverConnection,
+				)
+			}()
+
+			client := tcpServer.NewTCPClientFromConnection(
+				clientConnection,
+				tcpServer.TCPClientOptions[string, string]{
+					ClientProtocol: testLineProtocol{},
+				},
+			)
+
+			var authErr error
+			if mechanism, isPresent := data.clientMechanism.Get(); isPresent {
+				authErr = client.Authenticate(
+					tcpServer.ClientAuthenticationOptions[string, string]{
+						Protocol:  testAuthenticationProtocol{},
+						Mechanism: mechanism,
+					},
+				)
+			}
+			data.wantAuthErr(test, authErr)
+
+			var gotResponse string
+			if authErr == nil {
+				var err error
+				gotResponse, err = client.SendRequest("request")
+				require.NoError(test, err)
+			}
+			require.NoError(test, client.Close())
+
+			assert.Equal(test, data.wantResponse, gotResponse)
+			data.wantHandlingErr(test, <-handlingErr)
+		})
+	}
+}
+
+type testAuthenticationProtocol struct{}
+
+func (testAuthenticationProtocol) NewAuthenticationRequest(
+	message tcpServer.AuthenticationMessage,
+) (string, error) {
+	return "auth:" + message.Mechanism + ":" + string(message.Data), nil
+}
+
+func (testAuthenticationProtocol) AuthenticationRequest(
+	request string,
+) mo.Option[tcpServer.AuthenticationMessage] {
+	message, isFound := strings.CutPrefix(request, "auth:")
+	if !isFound {
+		return mo.None[tcpServer.AuthenticationMessage]()
+	}
+
+	mechanism, data, _ := strings.Cut(message, ":")
+	return mo.Some(tcpServer.AuthenticationMessage{
+		Mechanism: mechanism,
+		Data:      []byte(data),
+	})
+}
+
+func (testAuthenticationProtocol) NewAuthenticationResponse(
+	reply tcpServer.AuthenticationReply,
+) (string, error) {
+	return string(reply.Status) + ":" + string(reply.Data), nil
+}
+
+func (testAuthenticationProtocol) AuthenticationReply(
+	response string,
+) mo.Option[tcpServer.AuthenticationReply] {
+	status, data, isFound := strings.Cut(response, ":")
+	if !isFound {
+		return mo.None[tcpServer.AuthenticationReply]()
+	}
+
+	return mo.Some(tcpServer.AuthenticationReply{
+		Status: tcpServer.AuthenticationStatus(status),
+		Data:   []byte(data),
+	})
+}
+
+// testServerAuthenticationMechanism expects the "hello" message,
+// challenges the client with the "who" one and expects the "secret" answer
+type testServerAuthenticationMechanism struct{}
+
+func (testServerAuthenticationMechanism) Name() string {
+	return "TEST"
+}
+
+func (testServerAuthenticationMechanism) NewExchange() tcpServer.ServerAuthenticationExchange { //nolint:lll
+	return &testServerAuthenticationExchange{}
+}
+
+type testServerAuthenticationExchange struct {
+	isChallenged bool
+}
+
+func (exchange *testServerAuthenticationExchange) Step(
+	ctx context.Context,
+	data []byte,
+) (tcpServer.AuthenticationStepResult, error) {
+	if !exchange.isChallenged {
+		if string(data) != "hello" {
+			return tcpServer.AuthenticationStepResult{}, errors.New("no greeting")
+		}
+
+		exchange.isChallenged = true
+		return tcpServer.AuthenticationStepResult{Data: []byte("who")}, nil
+	}
+
+	if string(data) != "secret" {
+		return tcpServer.AuthenticationStepResult{}, errors.New("wrong answer")
+	}
+
+	return tcpServer.AuthenticationStepResult{
+		Data:      []byte("welcome"),
+		Principal: mo.Some(tcpServer.Principal{Name: "user"}),
+	}, nil
+}
+
+type testClientAuthenticationMechanism struct {
+	name   string
+	answer string
+}
+
+func (mechanism testClientAuthenticationMechanism) Name() string {
+	return mechanism.name
+}
+
+func (mechanism testClientAuthenticationMechanism) NewExchange() tcpServer.ClientAuthenticationExchange { //nolint:lll
+	return mechanism
+}
+
+func (mechanism testClientAuthenticationMechanism) Step(
+	challenge []byte,
+) ([]byte, error) {
+	if challenge == nil {
+		return []byte("hello"), nil
+	}
+
+	return []byte(mechanism.answer), nil
+}
+
+func (mechanism testClientAuthenticationMechanism) Complete(
+	data []byte,
+) error {
+	if string(data) != "welcome" {
+		return errors.New("no welcome")
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package balancingClient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/samber/mo"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+const (
+	DefaultDialTimeout            = 5 * time.Second
+	DefaultMaxIdleClientCount     = 2
+	DefaultMaxConsecutiveFailures = 3
+	DefaultEjectionDuration       = 10 * time.Second
+)
+
+type Probe func(ctx context.Context, address string) error
+
+type HealthCheckOptions struct {
+	MaxConsecutiveFailures int
+	EjectionDuration       time.Duration
+	Probe                  mo.Option[Probe]
+}
+
+type ClientOptions[Req tcpServer.Request, Resp tcpServer.Response] struct {
+	Resolver           Resolver
+	Strategy           Strategy[Req, Resp]
+	ClientOptions      tcpServer.TCPClientOptions[Req, Resp]
+	DialTimeout        mo.Option[time.Duration]
+	MaxIdleClientCount mo.Option[int]
+	HealthCheck        HealthCheckOptions
+}
+
+type Client[Req tcpServer.Request, Resp tcpServer.Response] struct {
+	options ClientOptions[Req, Resp]
+
+	endpointsLock sync.RWMutex
+	endpoints     []*Endpoint[Req, Resp]
+}
+
+func NewClient[Req tcpServer.Request, Resp tcpServer.Response](
+	ctx context.Context,
+	options ClientOptions[Req, Resp],
+) (*Client[Req, Resp], error) {
+	if options.HealthCheck.MaxConsecutiveFailures <= 0 {
+		options.HealthCheck.MaxConsecutiveFailures = DefaultMaxConsecutiveFailures
+	}
+	if options.HealthCheck.EjectionDuration <= 0 {
+		options.HealthCheck.EjectionDuration = DefaultEjectionDuration
+	}
+
+	client := &Client[Req, Resp]{
+		options: options,
+	}
+	if err := client.Refresh(ctx); err != nil {
+		return nil, fmt.Errorf("unable to refresh the endpoints: %w", err)
+	}
+
+	return client, nil
+}
+
+func (client *Client[Req, Resp]) Endpoints() []*Endpoint[Req, Resp] {
+	client.endpointsLock.RLock()
+	defer client.endpointsLock.RUnlock()
+
+	return client.endpoints
+}
+
+// Refresh re-resolves the addresses, keeping the state of the endpoints
+// whose addresses remain and closing the ones that disappeared
+func (client *Client[Req, Resp]) Refresh(ctx context.Context) error {
+	addresses, err := client.options.Resolver.ResolveAddresses(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to resolve the addresses: %w", err)
+	}
+	if len(addresses) == 0 {
+		return errors.New("no addresses are resolved")
+	}
+
+	client.endpointsLock.Lock()
+	defer client.endpointsLock.Unlock()
+
+	existingEndpoints := make(map[string]*Endpoint[Req, Resp])
+	for _, endpoint := range client.endpoints {
+		existingEndpoints[endpoint.Address()] = endpoint
+	}
+
+	dialTimeout := client.options.DialTimeout.OrElse(DefaultDialTimeout)
+	maxIdleClientCount :=
+		client.options.MaxIdleClientCount.OrElse(DefaultMaxIdleClientCount)
+
+	endpoints := make([]*Endpoint[Req, Resp], 0, len(addresses))
+	for _, address := range addresses {
+		if endpoint, isPresent := existingEndpoints[address]; isPresent {
+			delete(existingEndpoints, address)
+			endpoints = append(endpoints, endpoint)
+
+			continue
+		}
+
+		endpoints = append(endpoints, newEndpoint(endpointOptions[Req, Resp]{
+			address:            address,
+			clientOptions:      client.options.ClientOptions,
+			dialTimeout:        dialTimeout,
+			maxIdleClientCount: maxIdleClientCount,
+			healthCheck:        client.options.HealthCheck,
+		}))
+	}
+
+	var errs []error
+	for _, endpoint := range existingEndpoints {
+		if err := endpoint.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	client.endpoints = endpoints
+
+	if len(errs) > 0 {
+		return fmt.Errorf(
+			"unable to close the removed endpoints: %w",
+			errors.Join(errs...),
+		)
+	}
+
+	return nil
+}
+
+func (client *Client[Req, Resp]) SendRequest(request Req) (Resp, error) {
+	var zeroResponse Resp
+
+	var availableEndpoints []*Endpoint[Req, Resp]
+	for _, endpoint := range client.Endpoints() {
+		if endpoint.isAvailable() {
+			availableEndpoints = append(availableEndpoints, endpoint)
+		}
+	}
+	if len(availableEndpoints) == 0 {
+		return zeroResponse, ErrNoAvailableEndpoints
+	}
+
+	endpoint :=
+		client.options.Strategy.SelectEndpoint(request, availableEndpoints)
+	response, err := endpoint.sendRequest(request)
+	if err != nil {
+		return zeroResponse, fmt.Errorf(
+			"unable to send the request to endpoint %q: %w",
+			endpoint.Address(),
+			err,
+		)
+	}
+
+	return response, nil
+}
+
+func (client *Client[Req, Resp]) Close() error {
+	client.endpointsLock.Lock()
+	defer client.endpointsLock.Unlock()
+
+	var errs []error
+	for _, endpoint := range client.endpoints {
+		if err := endpoint.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	client.endpoints = nil
+
+	if len(errs) > 0 {
+		return fmt.Errorf("unable to close the endpoints: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func DialProbe(ctx context.Context, address string) error {
+	var dialer net.Dialer
+	connection, err := dialer.DialContext(ctx, tcpServer.TCPServerNetwork, address)
+	if err != nil {
+		return fmt.Errorf("unable to connect to address %q: %w", address, err)
+	}
+
+	if err := connection.Close(); err != nil {
+		return fmt.Errorf("unable to close the connection: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package balancingClient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestClient_SendRequest(test *testing.T) {
+	test.Run("success/round robin", func(test *testing.T) {
+		addresses := []string{
+			runTestServer(test, "first"),
+			runTestServer(test, "second"),
+		}
+
+		client, err := NewClient(context.Background(), ClientOptions[string, string]{
+			Resolver:      StaticResolver(addresses),
+			Strategy:      NewRoundRobinStrategy[string, string](),
+			ClientOptions: newTestClientOptions(),
+		})
+		require.NoError(test, err)
+		defer client.Close()
+
+		var gotResponses []string
+		for range 4 {
+			response, err := client.SendRequest("request")
+			require.NoError(test, err)
+
+			gotResponses = append(gotResponses, response)
+		}
+
+		assert.Equal(
+			test,
+			[]string{
+				"first:request",
+				"second:request",
+				"first:request",
+				"second:request",
+			},
+			gotResponses,
+		)
+	})
+
+	test.Run("success/ejection and probing", func(test *testing.T) {
+		var isProbeSuccessful atomic.Bool
+		addresses := []string{
+			"127.0.0.1:1", // nothing should listen on this port
+			runTestServer(test, "second"),
+		}
+
+		client, err := NewClient(context.Background(), ClientOptions[string, string]{
+			Resolver:      StaticResolver(addresses),
+			Strategy:      NewRoundRobinStrategy[string, string](),
+			ClientOptions: newTestClientOptions(),
+			HealthCheck: HealthCheckOptions{
+				MaxConsecutiveFailures: 1,
+				EjectionDuration:       time.Millisecond,
+				Probe: mo.Some[Probe](func(ctx context.Context, address string) error {
+					if !isProbeSuccessful.Load() {
+						return errors.New("probe failed")
+					}
+
+					return nil
+				}),
+			},
+		})
+		require.NoError(test, err)
+		defer client.Close()
+
+		_, err = client.SendRequest("request")
+		require.Error(test, err)
+		require.True(test, client.Endpoints()[0].IsEjected())
+
+		for range 4 {
+			response, err := client.SendRequest("request")
+			require.NoError(test, err)
+
+			assert.Equal(test, "second:request", response)
+		}
+
+		isProbeSuccessful.Store(true)
+		assert.Eventually(
+			test,
+			func() bool {
+				client.SendRequest("request") //nolint:errcheck
+				return !client.Endpoints()[0].IsEjected()
+			},
+			time.Second,
+			time.Millisecond,
+		)
+	})
+
+	test.Run("error/no available endpoints", func(test *testing.T) {
+		client, err := NewClient(context.Background(), ClientOptions[string, string]{
+			Resolver:      StaticResolver{"127.0.0.1:1"},
+			Strategy:      NewRoundRobinStrategy[string, string](),
+			ClientOptions: newTestClientOptions(),
+			HealthCheck: HealthCheckOptions{
+				MaxConsecutiveFailures: 1,
+				EjectionDuration:       time.Hour,
+			},
+		})
+		require.NoError(test, err)
+		defer client.Close()
+
+		_, err = client.SendRequest("request")
+		require.Error(test, err)
+
+		_, err = client.SendRequest("request")
+		assert.ErrorIs(test, err, ErrNoAvailableEndpoints)
+	})
+}
+
+func TestClient_Refresh(test *testing.T) {
+	addresses := []string{"127.0.0.1:8080", "127.0.0.1:8081"}
+	client, err := NewClient(context.Background(), ClientOptions[string, string]{
+		Resolver: ResolverFunc(func(ctx context.Context) ([]string, error) {
+			return addresses, nil
+		}),
+		Strategy:      NewRoundRobinStrategy[string, string](),
+		ClientOptions: newTestClientOptions(),
+	})
+	require.NoError(test, err)
+	defer client.Close()
+
+	endpoints := client.Endpoints()
+	require.Len(test, endpoints, 2)
+
+	addresses = []string{"127.0.0.1:8081", "127.0.0.1:8082"}
+	err = client.Refresh(context.Background())
+	require.NoError(test, err)
+
+	gotEndpoints := client.Endpoints()
+	require.Len(test, gotEndpoints, 2)
+	assert.Same(test, endpoints[1], gotEndpoints[0])
+	assert.Equal(test, "127.0.0.1:8082", gotEndpoints[1].Address())
+}
+
+type testLineProtocol struct{}
+
+func (testLineProtocol) InitialScannerBufferSize() int {
+	return 4 * 1024
+}
+
+func (testLineProtocol) MaxTokenSize() int {
+	return 64 * 1024
+}
+
+func (testLineProtocol) ExtractToken(
+	data []byte,
+	isLatestData bool,
+) (offsetToNextToken int, token []byte, err error) {
+	return bufio.ScanLines(data, isLatestData)
+}
+
+func (testLineProtocol) MarshalRequest(request string) ([]byte, error) {
+	return []byte(request + "\n"), nil
+}
+
+func (testLineProtocol) ParseResponse(data []byte) (string, error) {
+	return string(data), nil
+}
+
+func newTestClientOptions() tcpServer.TCPClientOptions[string, string] {
+	return tcpServer.TCPClientOptions[string, string]{
+		ReadTimeout:    mo.Some(time.Second),
+		WriteTimeout:   mo.Some(time.Second),
+		ClientProtocol: testLineProtocol{},
+	}
+}
+
+func runTestServer(test *testing.T, name string) string {
+	listener, err := net.Listen(tcpServer.TCPServerNetwork, "127.0.0.1:")
+	require.NoError(test, err)
+	test.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			connection, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer connection.Close()
+
+				scanner := bufio.NewScanner(connection)
+				for scanner.Scan() {
+					response := bytes.Join(
+						[][]byte{[]byte(name), scanner.Bytes()},
+						[]byte(":"),
+					)
+					if _, err := connection.Write(append(response, '\n')); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
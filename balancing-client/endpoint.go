@@ -0,0 +1,234 @@
+package balancingClient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+type endpointOptions[Req tcpServer.Request, Resp tcpServer.Response] struct {
+	address            string
+	clientOptions      tcpServer.TCPClientOptions[Req, Resp]
+	dialTimeout        time.Duration
+	maxIdleClientCount int
+	healthCheck        HealthCheckOptions
+}
+
+type Endpoint[Req tcpServer.Request, Resp tcpServer.Response] struct {
+	options endpointOptions[Req, Resp]
+
+	inFlightRequestCount atomic.Int64
+
+	idleClientsLock sync.Mutex
+	idleClients     []tcpServer.TCPClient[Req, Resp]
+	isClosed        bool
+
+	healthLock          sync.Mutex
+	consecutiveFailures int
+	ejectionTime        time.Time
+	isEjected           bool
+	isProbing           bool
+}
+
+func newEndpoint[Req tcpServer.Request, Resp tcpServer.Response](
+	options endpointOptions[Req, Resp],
+) *Endpoint[Req, Resp] {
+	return &Endpoint[Req, Resp]{
+		options: options,
+	}
+}
+
+func (endpoint *Endpoint[Req, Resp]) Address() string {
+	return endpoint.options.address
+}
+
+func (endpoint *Endpoint[Req, Resp]) InFlightRequestCount() int {
+	return int(endpoint.inFlightRequestCount.Load())
+}
+
+func (endpoint *Endpoint[Req, Resp]) IsEjected() bool {
+	endpoint.healthLock.Lock()
+	defer endpoint.healthLock.Unlock()
+
+	return endpoint.isEjected
+}
+
+func (endpoint *Endpoint[Req, Resp]) sendRequest(request Req) (Resp, error) {
+	endpoint.inFlightRequestCount.Add(1)
+	defer endpoint.inFlightRequestCount.Add(-1)
+
+	var zeroResponse Resp
+
+	client, err := endpoint.acquireClient()
+	if err != nil {
+		endpoint.reportFailure()
+		return zeroResponse, fmt.Errorf("unable to acquire the client: %w", err)
+	}
+
+	response, err := client.SendRequest(request)
+	if err != nil {
+		client.Close() //nolint:errcheck
+
+		endpoint.reportFailure()
+		return zeroResponse, fmt.Errorf("unable to send the request: %w", err)
+	}
+
+	endpoint.releaseClient(client)
+	endpoint.reportSuccess()
+
+	return response, nil
+}
+
+func (endpoint *Endpoint[Req, Resp]) acquireClient() (
+	tcpServer.TCPClient[Req, Resp],
+	error,
+) {
+	endpoint.idleClientsLock.Lock()
+	if idleClientCount := len(endpoint.idleClients); idleClientCount != 0 {
+		client := endpoint.idleClients[idleClientCount-1]
+		endpoint.idleClients = endpoint.idleClients[:idleClientCount-1]
+		endpoint.idleClientsLock.Unlock()
+
+		return client, nil
+	}
+	endpoint.idleClientsLock.Unlock()
+
+	ctx, ctxCancel := context.WithTimeout(
+		context.Background(),
+		endpoint.options.dialTimeout,
+	)
+	defer ctxCancel()
+
+	client, err := tcpServer.NewTCPClient(
+		ctx,
+		endpoint.options.address,
+		endpoint.options.clientOptions,
+	)
+	if err != nil {
+		return tcpServer.TCPClient[Req, Resp]{}, fmt.Errorf(
+			"unable to create the client: %w",
+			err,
+		)
+	}
+
+	return client, nil
+}
+
+func (endpoint *Endpoint[Req, Resp]) releaseClient(
+	client tcpServer.TCPClient[Req, Resp],
+) {
+	endpoint.idleClientsLock.Lock()
+	defer endpoint.idleClientsLock.Unlock()
+
+	if endpoint.isClosed ||
+		len(endpoint.idleClients) >= endpoint.options.maxIdleClientCount {
+		client.Close() //nolint:errcheck
+		return
+	}
+
+	endpoint.idleClients = append(endpoint.idleClients, client)
+}
+
+func (endpoint *Endpoint[Req, Resp]) reportSuccess() {
+	endpoint.healthLock.Lock()
+	defer endpoint.healthLock.Unlock()
+
+	endpoint.consecutiveFailures = 0
+}
+
+func (endpoint *Endpoint[Req, Resp]) reportFailure() {
+	endpoint.healthLock.Lock()
+	defer endpoint.healthLock.Unlock()
+
+	endpoint.consecutiveFailures++
+	if endpoint.isEjected ||
+		endpoint.consecutiveFailures <
+			endpoint.options.healthCheck.MaxConsecutiveFailures {
+		return
+	}
+
+	endpoint.isEjected = true
+	endpoint.ejectionTime = time.Now()
+
+	// the connections may be broken, so don't reuse them
+	endpoint.closeIdleClients() //nolint:errcheck
+}
+
+// isAvailable also starts the probe of the ejected endpoint
+// if its ejection duration has expired
+func (endpoint *Endpoint[Req, Resp]) isAvailable() bool {
+	endpoint.healthLock.Lock()
+	defer endpoint.healthLock.Unlock()
+
+	if !endpoint.isEjected {
+		return true
+	}
+
+	ejectionDuration := endpoint.options.healthCheck.EjectionDuration
+	if !endpoint.isProbing &&
+		time.Since(endpoint.ejectionTime) >= ejectionDuration {
+		endpoint.isProbing = true
+		go endpoint.probe()
+	}
+
+	return false
+}
+
+func (endpoint *Endpoint[Req, Resp]) probe() {
+	ctx, ctxCancel := context.WithTimeout(
+		context.Background(),
+		endpoint.options.dialTimeout,
+	)
+	defer ctxCancel()
+
+	err := endpoint.options.healthCheck.Probe.
+		OrElse(DialProbe)(ctx, endpoint.options.address)
+
+	endpoint.healthLock.Lock()
+	defer endpoint.healthLock.Unlock()
+
+	endpoint.isProbing = false
+	if err != nil {
+		endpoint.ejectionTime = time.Now()
+		return
+	}
+
+	endpoint.isEjected = false
+	endpoint.consecutiveFailures = 0
+}
+
+func (endpoint *Endpoint[Req, Resp]) closeIdleClients() error {
+	endpoint.idleClientsLock.Lock()
+	defer endpoint.idleClientsLock.Unlock()
+
+	var errs []error
+	for _, client := range endpoint.idleClients {
+		if err := client.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	endpoint.idleClients = nil
+
+	return errors.Join(errs...)
+}
+
+func (endpoint *Endpoint[Req, Resp]) close() error {
+	endpoint.idleClientsLock.Lock()
+	endpoint.isClosed = true
+	endpoint.idleClientsLock.Unlock()
+
+	if err := endpoint.closeIdleClients(); err != nil {
+		return fmt.Errorf(
+			"unable to close the idle clients of endpoint %q: %w",
+			endpoint.options.address,
+			err,
+		)
+	}
+
+	return nil
+}
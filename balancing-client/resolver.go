@@ -0,0 +1,78 @@
+package balancingClient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+type Resolver interface {
+	ResolveAddresses(ctx context.Context) ([]string, error)
+}
+
+type ResolverFunc func(ctx context.Context) ([]string, error)
+
+func (f ResolverFunc) ResolveAddresses(ctx context.Context) ([]string, error) {
+	return f(ctx)
+}
+
+type StaticResolver []string
+
+func (resolver StaticResolver) ResolveAddresses(
+	ctx context.Context,
+) ([]string, error) {
+	return slices.Clone(resolver), nil
+}
+
+type FileResolverOptions struct {
+	Path string
+}
+
+// FileResolver reads addresses from a file, one address per line;
+// empty lines and lines starting with `#` are ignored
+type FileResolver struct {
+	options FileResolverOptions
+}
+
+func NewFileResolver(options FileResolverOptions) FileResolver {
+	return FileResolver{
+		options: options,
+	}
+}
+
+func (resolver FileResolver) ResolveAddresses(
+	ctx context.Context,
+) ([]string, error) {
+	content, err := os.ReadFile(resolver.options.Path)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"unable to read the file %q: %w",
+			resolver.options.Path,
+			err,
+		)
+	}
+
+	var addresses []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		addresses = append(addresses, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf(
+			"unable to scan the file %q: %w",
+			resolver.options.Path,
+			err,
+		)
+	}
+
+	return addresses, nil
+}
@@ -0,0 +1,84 @@
+package balancingClient
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticResolver_ResolveAddresses(test *testing.T) {
+	for _, data := range []struct {
+		name     string
+		resolver StaticResolver
+		want     []string
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "success/without addresses",
+			resolver: nil,
+			want:     nil,
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "success/with addresses",
+			resolver: StaticResolver{"127.0.0.1:8080", "127.0.0.1:8081"},
+			want:     []string{"127.0.0.1:8080", "127.0.0.1:8081"},
+			wantErr:  assert.NoError,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := data.resolver.ResolveAddresses(context.Background())
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestFileResolver_ResolveAddresses(test *testing.T) {
+	for _, data := range []struct {
+		name        string
+		fileContent []byte
+		want        []string
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name:        "success/empty file",
+			fileContent: []byte(""),
+			want:        nil,
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "success/with addresses",
+			fileContent: []byte(
+				"127.0.0.1:8080\n\n  127.0.0.1:8081  \n# 127.0.0.1:8082\n127.0.0.1:8083",
+			),
+			want:    []string{"127.0.0.1:8080", "127.0.0.1:8081", "127.0.0.1:8083"},
+			wantErr: assert.NoError,
+		},
+		{
+			name:        "error/unable to read the file",
+			fileContent: nil,
+			want:        nil,
+			wantErr:     assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			path := filepath.Join(test.TempDir(), "addresses.txt")
+			if data.fileContent != nil {
+				err := os.WriteFile(path, data.fileContent, 0o600)
+				require.NoError(test, err)
+			}
+
+			resolver := NewFileResolver(FileResolverOptions{Path: path})
+			got, err := resolver.ResolveAddresses(context.Background())
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
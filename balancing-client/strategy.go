@@ -0,0 +1,201 @@
+package balancingClient
+
+import (
+	"errors"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+const (
+	DefaultVirtualNodeCount = 100
+)
+
+var (
+	ErrNoAvailableEndpoints = errors.New("no available endpoints")
+)
+
+type Strategy[Req tcpServer.Request, Resp tcpServer.Response] interface {
+	// endpoints contain only the available ones and are never empty
+	SelectEndpoint(
+		request Req,
+		endpoints []*Endpoint[Req, Resp],
+	) *Endpoint[Req, Resp]
+}
+
+type RoundRobinStrategy[Req tcpServer.Request, Resp tcpServer.Response] struct {
+	counter atomic.Uint64
+}
+
+func NewRoundRobinStrategy[
+	Req tcpServer.Request,
+	Resp tcpServer.Response,
+]() *RoundRobinStrategy[Req, Resp] {
+	return &RoundRobinStrategy[Req, Resp]{}
+}
+
+func (strategy *RoundRobinStrategy[Req, Resp]) SelectEndpoint(
+	request Req,
+	endpoints []*Endpoint[Req, Resp],
+) *Endpoint[Req, Resp] {
+	index := (strategy.counter.Add(1) - 1) % uint64(len(endpoints))
+	return endpoints[index]
+}
+
+type LeastInFlightStrategy[
+	Req tcpServer.Request,
+	Resp tcpServer.Response,
+] struct {
+	counter atomic.Uint64
+}
+
+func NewLeastInFlightStrategy[
+	Req tcpServer.Request,
+	Resp tcpServer.Response,
+]() *LeastInFlightStrategy[Req, Resp] {
+	return &LeastInFlightStrategy[Req, Resp]{}
+}
+
+func (strategy *LeastInFlightStrategy[Req, Resp]) SelectEndpoint(
+	request Req,
+	endpoints []*Endpoint[Req, Resp],
+) *Endpoint[Req, Resp] {
+	// rotate the starting point to spread the ties evenly
+	offset := int((strategy.counter.Add(1) - 1) % uint64(len(endpoints)))
+
+	var selectedEndpoint *Endpoint[Req, Resp]
+	for index := range endpoints {
+		endpoint := endpoints[(offset+index)%len(endpoints)]
+		if selectedEndpoint == nil ||
+			endpoint.InFlightRequestCount() <
+				selectedEndpoint.InFlightRequestCount() {
+			selectedEndpoint = endpoint
+		}
+	}
+
+	return selectedEndpoint
+}
+
+type ConsistentHashingStrategyOptions[Req tcpServer.Request] struct {
+	KeyExtractor     func(request Req) string
+	VirtualNodeCount int
+}
+
+type ConsistentHashingStrategy[
+	Req tcpServer.Request,
+	Resp tcpServer.Response,
+] struct {
+	options ConsistentHashingStrategyOptions[Req]
+
+	ringLock      sync.Mutex
+	ringAddresses string
+	ring          []ringNode
+}
+
+type ringNode struct {
+	hash          uint32
+	endpointIndex int
+}
+
+func NewConsistentHashingStrategy[
+	Req tcpServer.Request,
+	Resp tcpServer.Response,
+](
+	options ConsistentHashingStrategyOptions[Req],
+) *ConsistentHashingStrategy[Req, Resp] {
+	if options.VirtualNodeCount <= 0 {
+		options.VirtualNodeCount = DefaultVirtualNodeCount
+	}
+
+	return &ConsistentHashingStrategy[Req, Resp]{
+		options: options,
+	}
+}
+
+func (strategy *ConsistentHashingStrategy[Req, Resp]) SelectEndpoint(
+	request Req,
+	endpoints []*Endpoint[Req, Resp],
+) *Endpoint[Req, Resp] {
+	ring := strategy.getRing(endpoints)
+
+	hash := hashString(strategy.options.KeyExtractor(request))
+	index, _ := slices.BinarySearchFunc(
+		ring,
+		hash,
+		func(node ringNode, hash uint32) int {
+			switch {
+			case node.hash < hash:
+				return -1
+			case node.hash > hash:
+				return 1
+			default:
+				return 0
+			}
+		},
+	)
+	if index == len(ring) {
+		index = 0 // wrap around the ring
+	}
+
+	return endpoints[ring[index].endpointIndex]
+}
+
+// getRing rebuilds the ring only when the endpoint set changes;
+// because the node hashes depend only on the addresses, the keys
+// of the remaining endpoints stay in place after the rebuilding
+func (strategy *ConsistentHashingStrategy[Req, Resp]) getRing(
+	endpoints []*Endpoint[Req, Resp],
+) []ringNode {
+	addresses := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		addresses = append(addresses, endpoint.Address())
+	}
+	joinedAddresses := strings.Join(addresses, "\n")
+
+	strategy.ringLock.Lock()
+	defer strategy.ringLock.Unlock()
+
+	if strategy.ring != nil && strategy.ringAddresses == joinedAddresses {
+		return strategy.ring
+	}
+
+	ring := make([]ringNode, 0, len(endpoints)*strategy.options.VirtualNodeCount)
+	for endpointIndex, address := range addresses {
+		for nodeIndex := range strategy.options.VirtualNodeCount {
+			ring = append(ring, ringNode{
+				hash:          hashString(address + "#" + strconv.Itoa(nodeIndex)),
+				endpointIndex: endpointIndex,
+			})
+		}
+	}
+	slices.SortFunc(ring, func(a ringNode, b ringNode) int {
+		switch {
+		case a.hash < b.hash:
+			return -1
+		case a.hash > b.hash:
+			return 1
+		default:
+			return strings.Compare(
+				addresses[a.endpointIndex],
+				addresses[b.endpointIndex],
+			)
+		}
+	})
+
+	strategy.ringAddresses = joinedAddresses
+	strategy.ring = ring
+
+	return ring
+}
+
+func hashString(value string) uint32 {
+	hash := fnv.New32a()
+	hash.Write([]byte(value)) //nolint:errcheck
+
+	return hash.Sum32()
+}
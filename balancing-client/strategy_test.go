@@ -0,0 +1,117 @@
+package balancingClient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundRobinStrategy_SelectEndpoint(test *testing.T) {
+	type request string
+	type response string
+
+	endpoints := newTestEndpoints[request, response](0, 0, 0)
+	strategy := NewRoundRobinStrategy[request, response]()
+
+	var gotAddresses []string
+	for range 2 * len(endpoints) {
+		endpoint := strategy.SelectEndpoint("request", endpoints)
+		gotAddresses = append(gotAddresses, endpoint.Address())
+	}
+
+	assert.Equal(
+		test,
+		[]string{"#0", "#1", "#2", "#0", "#1", "#2"},
+		gotAddresses,
+	)
+}
+
+func TestLeastInFlightStrategy_SelectEndpoint(test *testing.T) {
+	type request string
+	type response string
+
+	for _, data := range []struct {
+		name                  string
+		inFlightRequestCounts []int
+		want                  []string
+	}{
+		{
+			name:                  "success/single minimum",
+			inFlightRequestCounts: []int{3, 1, 2},
+			want:                  []string{"#1", "#1", "#1"},
+		},
+		{
+			name:                  "success/several minimums",
+			inFlightRequestCounts: []int{1, 5, 1},
+			want:                  []string{"#0", "#2", "#2"},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			endpoints :=
+				newTestEndpoints[request, response](data.inFlightRequestCounts...)
+			strategy := NewLeastInFlightStrategy[request, response]()
+
+			var gotAddresses []string
+			for range len(data.want) {
+				endpoint := strategy.SelectEndpoint("request", endpoints)
+				gotAddresses = append(gotAddresses, endpoint.Address())
+			}
+
+			assert.Equal(test, data.want, gotAddresses)
+		})
+	}
+}
+
+func TestConsistentHashingStrategy_SelectEndpoint(test *testing.T) {
+	type request string
+	type response string
+
+	strategy := NewConsistentHashingStrategy[request, response](
+		ConsistentHashingStrategyOptions[request]{
+			KeyExtractor: func(request request) string { return string(request) },
+		},
+	)
+
+	keys := []request{"one", "two", "three", "four", "five", "six", "seven"}
+
+	endpoints := newTestEndpoints[request, response](0, 0, 0)
+	selectedAddresses := make(map[request]string)
+	for _, key := range keys {
+		selectedAddresses[key] = strategy.SelectEndpoint(key, endpoints).Address()
+
+		// the selection is stable
+		assert.Equal(
+			test,
+			selectedAddresses[key],
+			strategy.SelectEndpoint(key, endpoints).Address(),
+		)
+	}
+
+	// the keys of the remaining endpoints stay in place
+	remainingEndpoints :=
+		[]*Endpoint[request, response]{endpoints[0], endpoints[2]}
+	for _, key := range keys {
+		gotAddress := strategy.SelectEndpoint(key, remainingEndpoints).Address()
+		if selectedAddresses[key] != endpoints[1].Address() {
+			assert.Equal(test, selectedAddresses[key], gotAddress)
+		} else {
+			assert.NotEqual(test, endpoints[1].Address(), gotAddress)
+		}
+	}
+}
+
+func newTestEndpoints[Req any, Resp any](
+	inFlightRequestCounts ...int,
+) []*Endpoint[Req, Resp] {
+	endpoints := make([]*Endpoint[Req, Resp], 0, len(inFlightRequestCounts))
+	for index, inFlightRequestCount := range inFlightRequestCounts {
+		endpoint := newEndpoint(endpointOptions[Req, Resp]{
+			address: "#" + string(rune('0'+index)),
+		})
+		endpoint.inFlightRequestCount.Store(int64(inFlightRequestCount))
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints
+}
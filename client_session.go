@@ -0,0 +1,149 @@
+package tcpServer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/samber/mo"
+)
+
+type PushHandler[Resp Response] func(message Resp)
+
+// CallHandler replies to the call of the server;
+// the reply is marked via the session protocol automatically
+type CallHandler[Req Request, Resp Response] func(message Resp) Req
+
+type ClientSessionOptions[Req Request, Resp Response] struct {
+	Protocol    SessionProtocol[Req, Resp]
+	PushHandler mo.Option[PushHandler[Resp]]
+	CallHandler mo.Option[CallHandler[Req, Resp]]
+}
+
+type tcpClientReading[Resp Response] struct {
+	responses chan Resp
+	done      chan struct{}
+	err       error
+}
+
+func newTCPClientReading[Resp Response]() *tcpClientReading[Resp] {
+	return &tcpClientReading[Resp]{
+		responses: make(chan Resp),
+		done:      make(chan struct{}),
+	}
+}
+
+func (client TCPClient[Req, Resp]) runReading() {
+	reading := client.reading.MustGet()
+	defer close(reading.done)
+
+	for {
+		response, err := client.scanResponse()
+		if err != nil {
+			reading.err = err
+			return
+		}
+
+		if heartbeat, isPresent := client.options.Heartbeat.Get(); isPresent &&
+			heartbeat.Protocol.IsPongResponse(response) {
+			select {
+			case client.pongs <- struct{}{}:
+			default: // the heartbeat hasn't taken the previous pong yet
+			}
+
+			continue
+		}
+
+		if session, isPresent := client.options.Session.Get(); isPresent {
+			if client.handleSessionMessage(session, response) {
+				continue
+			}
+		}
+
+		select {
+		case reading.responses <- response:
+		case <-client.closed:
+			reading.err = errors.New("client is closed")
+			return
+		}
+	}
+}
+
+// handleSessionMessage returns false if the message is a regular response
+func (client TCPClient[Req, Resp]) handleSessionMessage(
+	session ClientSessionOptions[Req, Resp],
+	message Resp,
+) bool {
+	if callID, isPresent := session.Protocol.CallID(message).Get(); isPresent {
+		if callHandler, isPresent := session.CallHandler.Get(); isPresent {
+			// the call handler may take a while,
+			// so it shouldn't delay the next messages
+			go client.replyToCall(session.Protocol, callHandler, callID, message)
+		}
+
+		return true
+	}
+
+	if session.Protocol.IsPush(message) {
+		if pushHandler, isPresent := session.PushHandler.Get(); isPresent {
+			pushHandler(message)
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func (client TCPClient[Req, Resp]) replyToCall(
+	protocol SessionProtocol[Req, Resp],
+	callHandler CallHandler[Req, Resp],
+	callID uint64,
+	message Resp,
+) {
+	reply, err := protocol.MarkAsCallReply(callHandler(message), callID)
+	if err == nil {
+		err = client.writeRequest(reply)
+	}
+	if err != nil {
+		// the server would wait for the reply in vain
+		client.Close() //nolint:errcheck
+	}
+}
+
+func (client TCPClient[Req, Resp]) waitForResponse(
+	reading *tcpClientReading[Resp],
+	readTimeout mo.Option[time.Duration],
+) (Resp, error) {
+	var zeroResponse Resp
+
+	var timeout <-chan time.Time
+	if readTimeout, isPresent := readTimeout.Get(); isPresent {
+		timer := time.NewTimer(readTimeout)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	select {
+	case response := <-reading.responses:
+		return response, nil
+
+	case <-reading.done:
+		return zeroResponse, fmt.Errorf(
+			"unable to read the response: %w",
+			reading.err,
+		)
+
+	case <-timeout:
+		// the late response would be taken for the next request,
+		// so the client can't be used anymore
+		client.Close() //nolint:errcheck
+
+		return zeroResponse, fmt.Errorf(
+			"unable to read the response: %w",
+			os.ErrDeadlineExceeded,
+		)
+	}
+}
@@ -0,0 +1,143 @@
+package tcpServer
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+
+	"github.com/samber/mo"
+)
+
+const (
+	GzipCodecName    = "gzip"
+	DeflateCodecName = "deflate"
+	ZlibCodecName    = "zlib"
+)
+
+// CompressionCodec allows to plug in the third-party compression formats
+// (e.g. zstd); its name is used to mark the compressed data
+// and in the handshake
+type CompressionCodec interface {
+	Name() string
+	NewWriter(writer io.Writer) (io.WriteCloser, error)
+	NewReader(reader io.Reader) (io.ReadCloser, error)
+}
+
+type CompressionCodecOptions struct {
+	Level mo.Option[int]
+}
+
+type GzipCodec struct {
+	options CompressionCodecOptions
+}
+
+func NewGzipCodec(options CompressionCodecOptions) GzipCodec {
+	return GzipCodec{
+		options: options,
+	}
+}
+
+func (codec GzipCodec) Name() string {
+	return GzipCodecName
+}
+
+func (codec GzipCodec) NewWriter(writer io.Writer) (io.WriteCloser, error) {
+	compressingWriter, err := gzip.NewWriterLevel(
+		writer,
+		codec.options.Level.OrElse(gzip.DefaultCompression),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct the gzip writer: %w", err)
+	}
+
+	return compressingWriter, nil
+}
+
+func (codec GzipCodec) NewReader(reader io.Reader) (io.ReadCloser, error) {
+	decompressingReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct the gzip reader: %w", err)
+	}
+
+	return decompressingReader, nil
+}
+
+type DeflateCodec struct {
+	options CompressionCodecOptions
+}
+
+func NewDeflateCodec(options CompressionCodecOptions) DeflateCodec {
+	return DeflateCodec{
+		options: options,
+	}
+}
+
+func (codec DeflateCodec) Name() string {
+	return DeflateCodecName
+}
+
+func (codec DeflateCodec) NewWriter(writer io.Writer) (io.WriteCloser, error) {
+	compressingWriter, err := flate.NewWriter(
+		writer,
+		codec.options.Level.OrElse(flate.DefaultCompression),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct the deflate writer: %w", err)
+	}
+
+	return compressingWriter, nil
+}
+
+func (codec DeflateCodec) NewReader(reader io.Reader) (io.ReadCloser, error) {
+	return flate.NewReader(reader), nil
+}
+
+type ZlibCodec struct {
+	options CompressionCodecOptions
+}
+
+func NewZlibCodec(options CompressionCodecOptions) ZlibCodec {
+	return ZlibCodec{
+		options: options,
+	}
+}
+
+func (codec ZlibCodec) Name() string {
+	return ZlibCodecName
+}
+
+func (codec ZlibCodec) NewWriter(writer io.Writer) (io.WriteCloser, error) {
+	compressingWriter, err := zlib.NewWriterLevel(
+		writer,
+		codec.options.Level.OrElse(zlib.DefaultCompression),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct the zlib writer: %w", err)
+	}
+
+	return compressingWriter, nil
+}
+
+func (codec ZlibCodec) NewReader(reader io.Reader) (io.ReadCloser, error) {
+	decompressingReader, err := zlib.NewReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct the zlib reader: %w", err)
+	}
+
+	return decompressingReader, nil
+}
+
+func FindCompressionCodec(
+	codecs []CompressionCodec,
+	name string,
+) (CompressionCodec, bool) {
+	for _, codec := range codecs {
+		if codec.Name() == name {
+			return codec, true
+		}
+	}
+
+	return nil, false
+}
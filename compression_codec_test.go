@@ -0,0 +1,139 @@
+package tcpServer_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestCompressionCodec_interface(test *testing.T) {
+	for _, codec := range []tcpServer.CompressionCodec{
+		tcpServer.GzipCodec{},
+		tcpServer.DeflateCodec{},
+		tcpServer.ZlibCodec{},
+	} {
+		assert.Implements(test, (*tcpServer.CompressionCodec)(nil), codec)
+	}
+}
+
+func TestCompressionCodec(test *testing.T) {
+	for _, data := range []struct {
+		name     string
+		codec    tcpServer.CompressionCodec
+		wantName string
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/gzip",
+			codec: tcpServer.NewGzipCodec(tcpServer.CompressionCodecOptions{
+				Level: mo.Some(9),
+			}),
+			wantName: tcpServer.GzipCodecName,
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "success/deflate",
+			codec:    tcpServer.NewDeflateCodec(tcpServer.CompressionCodecOptions{}),
+			wantName: tcpServer.DeflateCodecName,
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "success/zlib",
+			codec:    tcpServer.NewZlibCodec(tcpServer.CompressionCodecOptions{}),
+			wantName: tcpServer.ZlibCodecName,
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "error/gzip with the invalid level",
+			codec: tcpServer.NewGzipCodec(tcpServer.CompressionCodecOptions{
+				Level: mo.Some(100),
+			}),
+			wantName: tcpServer.GzipCodecName,
+			wantErr:  assert.Error,
+		},
+		{
+			name: "error/deflate with the invalid level",
+			codec: tcpServer.NewDeflateCodec(tcpServer.CompressionCodecOptions{
+				Level: mo.Some(100),
+			}),
+			wantName: tcpServer.DeflateCodecName,
+			wantErr:  assert.Error,
+		},
+		{
+			name: "error/zlib with the invalid level",
+			codec: tcpServer.NewZlibCodec(tcpServer.CompressionCodecOptions{
+				Level: mo.Some(100),
+			}),
+			wantName: tcpServer.ZlibCodecName,
+			wantErr:  assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			assert.Equal(test, data.wantName, data.codec.Name())
+
+			var compressedData bytes.Buffer
+			writer, err := data.codec.NewWriter(&compressedData)
+			data.wantErr(test, err)
+			if err != nil {
+				return
+			}
+
+			originalData := strings.Repeat("data", 100)
+			_, err = writer.Write([]byte(originalData))
+			require.NoError(test, err)
+			require.NoError(test, writer.Close())
+
+			assert.Less(test, compressedData.Len(), len(originalData))
+
+			reader, err := data.codec.NewReader(&compressedData)
+			require.NoError(test, err)
+			defer reader.Close()
+
+			decompressedData, err := io.ReadAll(reader)
+			require.NoError(test, err)
+
+			assert.Equal(test, originalData, string(decompressedData))
+		})
+	}
+}
+
+func TestFindCompressionCodec(test *testing.T) {
+	codecs := []tcpServer.CompressionCodec{
+		tcpServer.NewGzipCodec(tcpServer.CompressionCodecOptions{}),
+		tcpServer.NewDeflateCodec(tcpServer.CompressionCodecOptions{}),
+	}
+
+	for _, data := range []struct {
+		name        string
+		codecName   string
+		want        tcpServer.CompressionCodec
+		wantIsFound bool
+	}{
+		{
+			name:        "success",
+			codecName:   tcpServer.DeflateCodecName,
+			want:        codecs[1],
+			wantIsFound: true,
+		},
+		{
+			name:        "error",
+			codecName:   "unknown",
+			want:        nil,
+			wantIsFound: false,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, gotIsFound :=
+				tcpServer.FindCompressionCodec(codecs, data.codecName)
+
+			assert.Equal(test, data.want, got)
+			assert.Equal(test, data.wantIsFound, gotIsFound)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"os"
 	"slices"
 	"time"
 
@@ -20,11 +21,22 @@ type ServerProtocol[Req Request, Resp Response] interface {
 }
 
 type DefaultConnectionHandlerOptions[Req Request, Resp Response] struct {
-	ReadTimeout     mo.Option[time.Duration]
-	WriteTimeout    mo.Option[time.Duration]
-	HandlingTimeout mo.Option[time.Duration]
-	ServerProtocol  ServerProtocol[Req, Resp]
-	RequestHandler  RequestHandler[Req, Resp]
+	// ReadTimeout limits both the waiting for a request and its reading;
+	// use IdleTimeout and RequestReadTimeout to limit them separately
+	ReadTimeout           mo.Option[time.Duration]
+	IdleTimeout           mo.Option[time.Duration]
+	RequestReadTimeout    mo.Option[time.Duration]
+	WriteTimeout          mo.Option[time.Duration]
+	HandlingTimeout       mo.Option[time.Duration]
+	MaxConnectionLifetime mo.Option[time.Duration]
+	Heartbeat             mo.Option[HeartbeatOptions[Req, Resp]]
+	Streaming             mo.Option[StreamingOptions[Req, Resp]]
+	SessionProtocol       mo.Option[SessionProtocol[Req, Resp]]
+	Handshake             mo.Option[ServerHandshakeOptions[Req, Resp]]
+	Authentication        mo.Option[AuthenticationOptions[Req, Resp]]
+	TLSUpgrade            mo.Option[TLSUpgradeOptions[Req, Resp]]
+	ServerProtocol        ServerProtocol[Req, Resp]
+	RequestHandler        RequestHandler[Req, Resp]
 }
 
 type DefaultConnectionHandler[Req Request, Resp Response] struct {
@@ -44,19 +56,98 @@ func (handler DefaultConnectionHandler[Req, Resp]) HandleRequest(
 	connection net.Conn,
 	scanner *bufio.Scanner,
 ) error {
-	if readTimeout, isPresent := handler.options.ReadTimeout.Get(); isPresent {
+	// the peer is expected to send the pings while it is idle,
+	// so the heartbeat timeout limits the waiting for the next request
+	readTimeout := handler.options.ReadTimeout
+	isHeartbeatTimeout := false
+	if heartbeat, isPresent := handler.options.Heartbeat.Get(); isPresent {
+		heartbeatTimeout := heartbeat.Timeout()
+		if currentReadTimeout, isPresent := readTimeout.Get(); !isPresent ||
+			heartbeatTimeout < currentReadTimeout {
+			readTimeout = mo.Some(heartbeatTimeout)
+			isHeartbeatTimeout = true
+		}
+	}
+
+	session, isPresent := SessionFromContext[Req, Resp](ctx)
+	if !isPresent {
+		session = newSession(handler, connection, scanner)
+		ctx = contextWithSession(ctx, session)
+	}
+
+	request, err := session.readRequest(readTimeout)
+	if err != nil {
+		if isHeartbeatTimeout && errors.Is(err, os.ErrDeadlineExceeded) {
+			err = errors.Join(err, ErrHeartbeatTimeout)
+		}
+
+		return err
+	}
+
+	if tlsUpgrade, isPresent := handler.options.TLSUpgrade.Get(); isPresent &&
+		tlsUpgrade.Protocol.IsUpgradeRequest(request) {
+		return handler.upgradeToTLS(ctx, session, tlsUpgrade)
+	}
+
+	if authentication, isPresent :=
+		handler.options.Authentication.Get(); isPresent {
+		isConsumed, err :=
+			handler.authenticateRequest(ctx, session, authentication, request)
+		if err != nil || isConsumed {
+			return err
+		}
+	}
+	if principal, isPresent := session.Principal(); isPresent {
+		ctx = ContextWithPrincipal(ctx, principal)
+	}
+
+	stream := defaultServerStream[Req, Resp]{
+		session: session,
+	}
+	if streaming, isPresent := handler.options.Streaming.Get(); isPresent &&
+		streaming.Protocol.IsStreamingRequest(request) {
+		return handler.handleStreamingRequest(ctx, stream, streaming, request)
+	}
+
+	response, handlingErr := handler.handleRequest(ctx, stream, request)
+	if handlingErr != nil && !errors.Is(handlingErr, ErrHandlingStopIsRequired) {
+		return fmt.Errorf("unable to handle the request: %w", handlingErr)
+	}
+
+	if err := session.writeResponse(response); err != nil {
+		return err
+	}
+
+	if errors.Is(handlingErr, ErrHandlingStopIsRequired) {
+		return fmt.Errorf(
+			"request handler requested to stop handling: %w",
+			handlingErr,
+		)
+	}
+
+	return nil
+}
+
+func (handler DefaultConnectionHandler[Req, Resp]) readRequest(
+	connection net.Conn,
+	scanner *bufio.Scanner,
+	readTimeout mo.Option[time.Duration],
+) (Req, error) {
+	var zeroRequest Req
+
+	if readTimeout, isPresent := readTimeout.Get(); isPresent {
 		readDeadline := time.Now().Add(readTimeout)
 		if err := connection.SetReadDeadline(readDeadline); err != nil {
-			return fmt.Errorf("unable to set the read deadline: %w", err)
+			return zeroRequest, fmt.Errorf("unable to set the read deadline: %w", err)
 		}
 	}
 
 	if isPossibleToContinue := scanner.Scan(); !isPossibleToContinue {
 		if err := scanner.Err(); err != nil {
-			return fmt.Errorf("unable to read the request: %w", err)
+			return zeroRequest, fmt.Errorf("unable to read the request: %w", err)
 		}
 
-		return errors.Join(
+		return zeroRequest, errors.Join(
 			errors.New("scanner has no more tokens"),
 			ErrHandlingStopIsRequired,
 		)
@@ -66,31 +157,16 @@ func (handler DefaultConnectionHandler[Req, Resp]) HandleRequest(
 		slices.Clone(scanner.Bytes()),
 	)
 	if err != nil {
-		return fmt.Errorf("unable to parse the request: %w", err)
-	}
-
-	// ignore the parent context cancellation, because even in this case
-	// we need to finish handling the request
-	handlingCtx := context.WithoutCancel(ctx)
-
-	if handlingTimeout, isPresent :=
-		handler.options.HandlingTimeout.Get(); isPresent {
-		var handlingCtxCancel func()
-		handlingCtx, handlingCtxCancel = context.WithTimeout(
-			handlingCtx,
-			handlingTimeout,
-		)
-		defer handlingCtxCancel()
+		return zeroRequest, fmt.Errorf("unable to parse the request: %w", err)
 	}
 
-	response, handlingErr := handler.options.RequestHandler.HandleRequest(
-		handlingCtx,
-		request,
-	)
-	if handlingErr != nil && !errors.Is(handlingErr, ErrHandlingStopIsRequired) {
-		return fmt.Errorf("unable to handle the request: %w", err)
-	}
+	return request, nil
+}
 
+func (handler DefaultConnectionHandler[Req, Resp]) writeResponse(
+	connection net.Conn,
+	response Resp,
+) error {
 	marshalledResponse, err := handler.options.ServerProtocol.MarshalResponse(
 		response,
 	)
@@ -109,6 +185,52 @@ func (handler DefaultConnectionHandler[Req, Resp]) HandleRequest(
 		return fmt.Errorf("unable to write the response: %w", err)
 	}
 
+	return nil
+}
+
+func (handler DefaultConnectionHandler[Req, Resp]) handleRequest(
+	ctx context.Context,
+	stream ServerStream[Req, Resp],
+	request Req,
+) (Resp, error) {
+	if heartbeat, isPresent := handler.options.Heartbeat.Get(); isPresent &&
+		heartbeat.Protocol.IsPingRequest(request) {
+		return heartbeat.Protocol.NewPongResponse()
+	}
+
+	handlingCtx, handlingCtxCancel := handler.makeHandlingCtx(ctx, stream)
+	defer handlingCtxCancel()
+
+	return handler.options.RequestHandler.HandleRequest(handlingCtx, request)
+}
+
+func (handler DefaultConnectionHandler[Req, Resp]) handleStreamingRequest(
+	ctx context.Context,
+	stream defaultServerStream[Req, Resp],
+	streaming StreamingOptions[Req, Resp],
+	request Req,
+) error {
+	handlingCtx, handlingCtxCancel := handler.makeHandlingCtx(ctx, stream)
+	defer handlingCtxCancel()
+
+	handlingErr := streaming.RequestHandler.HandleStreamingRequest(
+		handlingCtx,
+		request,
+		ResponseSenderFunc[Resp](stream.WriteResponse),
+	)
+	if handlingErr != nil && !errors.Is(handlingErr, ErrHandlingStopIsRequired) {
+		return fmt.Errorf("unable to handle the streaming request: %w", handlingErr)
+	}
+
+	endOfStreamResponse, err := streaming.Protocol.NewEndOfStreamResponse()
+	if err != nil {
+		return fmt.Errorf("unable to construct the end-of-stream response: %w", err)
+	}
+
+	if err := stream.WriteResponse(endOfStreamResponse); err != nil {
+		return err
+	}
+
 	if errors.Is(handlingErr, ErrHandlingStopIsRequired) {
 		return fmt.Errorf(
 			"request handler requested to stop handling: %w",
@@ -119,14 +241,66 @@ func (handler DefaultConnectionHandler[Req, Resp]) HandleRequest(
 	return nil
 }
 
+func (handler DefaultConnectionHandler[Req, Resp]) makeHandlingCtx(
+	ctx context.Context,
+	stream ServerStream[Req, Resp],
+) (context.Context, context.CancelFunc) {
+	// ignore the parent context cancellation, because even in this case
+	// we need to finish handling the request
+	handlingCtx := context.WithoutCancel(ContextWithServerStream(ctx, stream))
+
+	if handlingTimeout, isPresent :=
+		handler.options.HandlingTimeout.Get(); isPresent {
+		return context.WithTimeout(handlingCtx, handlingTimeout)
+	}
+
+	return handlingCtx, func() {}
+}
+
 func (handler DefaultConnectionHandler[Req, Resp]) HandleConnection(
 	ctx context.Context,
 	connection net.Conn,
 ) error {
-	scanner := InitializeScanner(InitializeScannerParams[Req, Resp]{
-		Reader:       connection,
-		BaseProtocol: handler.options.ServerProtocol,
-	})
+	var lifetimeDeadline mo.Option[time.Time]
+	if maxConnectionLifetime, isPresent :=
+		handler.options.MaxConnectionLifetime.Get(); isPresent {
+		lifetimeDeadline = mo.Some(time.Now().Add(maxConnectionLifetime))
+	}
+
+	if handshake, isPresent := handler.options.Handshake.Get(); isPresent {
+		result, serverProtocol, handshakenConnection, err :=
+			performServerHandshake(connection, handshake)
+		if err != nil {
+			return fmt.Errorf("unable to perform the handshake: %w", err)
+		}
+
+		handler.options.ServerProtocol = serverProtocol
+		if !result.Streaming {
+			handler.options.Streaming = mo.None[StreamingOptions[Req, Resp]]()
+		}
+
+		connection = handshakenConnection
+		ctx = ContextWithHandshakeResult(ctx, result)
+	}
+
+	session := newSession(handler, connection, nil)
+	defer session.markAsClosed()
+
+	if handler.options.IdleTimeout.IsPresent() ||
+		handler.options.RequestReadTimeout.IsPresent() ||
+		lifetimeDeadline.IsPresent() {
+		session.timeoutOptions = mo.Some(timeoutConnectionOptions{
+			idleTimeout:        handler.options.IdleTimeout,
+			requestReadTimeout: handler.options.RequestReadTimeout,
+			lifetimeDeadline:   lifetimeDeadline,
+		})
+	}
+	session.setHijackableConnection(
+		connection,
+		handler.options.ServerProtocol.ExtractToken,
+	)
+
+	ctx = contextWithSession(ctx, session)
 	for {
 		select {
 		case <-ctx.Done():
@@ -135,14 +309,44 @@ func (handler DefaultConnectionHandler[Req, Resp]) HandleConnection(
 		default:
 		}
 
-		if err := handler.HandleRequest(ctx, connection, scanner); err != nil {
-			if errors.Is(err, ErrHandlingStopIsRequired) {
-				break
-			}
+		if lifetimeDeadline, isPresent := lifetimeDeadline.Get(); isPresent &&
+			!time.Now().Before(lifetimeDeadline) {
+			return ErrConnectionLifetimeExceeded
+		}
 
+		err := handler.HandleRequest(ctx, session.connection, session.scanner)
+		if err != nil && !errors.Is(err, ErrHandlingStopIsRequired) {
 			return fmt.Errorf("unable to handle the request: %w", err)
 		}
+
+		isHijacked, hijackingErr := session.hijack(ctx)
+		if hijackingErr != nil {
+			return fmt.Errorf("unable to hijack the connection: %w", hijackingErr)
+		}
+		if isHijacked {
+			return ErrConnectionIsHijacked
+		}
+
+		if err != nil {
+			break
+		}
 	}
 
 	return nil
 }
+
+type defaultServerStream[Req Request, Resp Response] struct {
+	session *Session[Req, Resp]
+}
+
+func (stream defaultServerStream[Req, Resp]) ReadRequest() (Req, error) {
+	return stream.session.readRequest(
+		stream.session.handler.options.ReadTimeout,
+	)
+}
+
+func (stream defaultServerStream[Req, Resp]) WriteResponse(
+	response Resp,
+) error {
+	return stream.session.writeResponse(response)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/samber/mo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	tcpServer "github.com/thewizardplusplus/go-tcp-server"
 	tcpServerExternalMocks "github.com/thewizardplusplus/go-tcp-server/mocks/external/github.com/thewizardplusplus/go-tcp-server"
 	tcpServerMocks "github.com/thewizardplusplus/go-tcp-server/mocks/github.com/thewizardplusplus/go-tcp-server"
@@ -352,7 +353,9 @@ func TestDefaultConnectionHandler_HandleRequest(test *testing.T) {
 				},
 				scanner: bufio.NewScanner(strings.NewReader("request")),
 			},
-			wantErr: assert.Error,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
 		},
 		{
 			name: "error/unable to marshal the response",
@@ -987,3 +990,78 @@ func TestDefaultConnectionHandler_HandleConnection(test *testing.T) {
 		})
 	}
 }
+
+func TestDefaultConnectionHandler_HandleConnection_withLifetime(
+	test *testing.T,
+) {
+	serverConnection, clientConnection := net.Pipe()
+	defer serverConnection.Close()
+	defer clientConnection.Close()
+
+	serverProtocolMock :=
+		tcpServerExternalMocks.NewMockServerProtocol[string, string](test)
+	serverProtocolMock.EXPECT().InitialScannerBufferSize().Return(4096)
+	serverProtocolMock.EXPECT().MaxTokenSize().Return(bufio.MaxScanTokenSize)
+	serverProtocolMock.EXPECT().
+		ExtractToken(mock.Anything, true).
+		Return(0, nil, nil).
+		Maybe()
+
+	handler := tcpServer.NewDefaultConnectionHandler(
+		tcpServer.DefaultConnectionHandlerOptions[string, string]{
+			MaxConnectionLifetime: mo.Some(10 * time.Millisecond),
+			ServerProtocol:        serverProtocolMock,
+			RequestHandler: tcpServerExternalMocks.
+				NewMockRequestHandler[string, string](test),
+		},
+	)
+	err := handler.HandleConnection(context.Background(), serverConnection)
+
+	assert.ErrorIs(test, err, tcpServer.ErrConnectionLifetimeExceeded)
+}
+
+func TestDefaultConnectionHandler_HandleConnection_withSlowHandler(
+	test *testing.T,
+) {
+	serverConnection, clientConnection := net.Pipe()
+	defer clientConnection.Close()
+
+	handler := tcpServer.NewDefaultConnectionHandler(
+		tcpServer.DefaultConnectionHandlerOptions[string, string]{
+			IdleTimeout:        mo.Some(50 * time.Millisecond),
+			RequestReadTimeout: mo.Some(50 * time.Millisecond),
+			ServerProtocol:     testLineProtocol{},
+			RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+				ctx context.Context,
+				request string,
+			) (string, error) {
+				// the handling doesn't count towards the timeouts
+				time.Sleep(100 * time.Millisecond)
+
+				return "handled:" + request, nil
+			}),
+		},
+	)
+	go func() {
+		defer serverConnection.Close()
+
+		handler.HandleConnection( //nolint:errcheck
+			context.Background(),
+			serverConnection,
+		)
+	}()
+
+	client := tcpServer.NewTCPClientFromConnection(
+		clientConnection,
+		tcpServer.TCPClientOptions[string, string]{
+			ClientProtocol: testLineProtocol{},
+		},
+	)
+	for index := range 3 {
+		request := fmt.Sprintf("request #%d", index)
+		response, err := client.SendRequest(request)
+		require.NoError(test, err)
+
+		assert.Equal(test, "handled:"+request, response)
+	}
+}
@@ -0,0 +1,531 @@
+package tcpServer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/samber/mo"
+)
+
+const (
+	DefaultMaxHandshakeMessageSize = 4 * 1024
+)
+
+var (
+	ErrNoCommonProtocolVersion = errors.New("no common protocol version")
+	ErrHandshakeIsRejected     = errors.New("handshake is rejected")
+	ErrInvalidMaxFrameSize     = errors.New("invalid max frame size")
+	ErrMaxFrameSizeIsExceeded  = errors.New("max frame size is exceeded")
+)
+
+type HandshakeCapabilities struct {
+	// Compressions are listed in the order of preference
+	Compressions []string
+	// MaxFrameSize is the max size of the messages the peer is able to accept
+	MaxFrameSize mo.Option[int]
+	Streaming    bool
+}
+
+type HandshakeOffer struct {
+	// Versions are listed in the order of preference
+	Versions     []string
+	Capabilities HandshakeCapabilities
+}
+
+type HandshakeResult struct {
+	Version      string
+	Compression  mo.Option[string]
+	MaxFrameSize mo.Option[int]
+	Streaming    bool
+}
+
+// ServerProtocolFactory chooses the server protocol for the negotiated
+// version; the negotiated max frame size is applied to it automatically
+type ServerProtocolFactory[Req Request, Resp Response] func(
+	result HandshakeResult,
+) (ServerProtocol[Req, Resp], error)
+
+// ClientProtocolFactory chooses the client protocol for the negotiated
+// version; the negotiated max frame size is applied to it automatically
+type ClientProtocolFactory[Req Request, Resp Response] func(
+	result HandshakeResult,
+) (ClientProtocol[Req, Resp], error)
+
+// ServerHandshakeOptions makes the connection handler wait
+// for the client offer before any request; the server preferences
+// take precedence in the negotiation; the chosen protocol replaces
+// the server protocol of the handler, and the streaming is disabled
+// if it isn't negotiated
+type ServerHandshakeOptions[Req Request, Resp Response] struct {
+	Timeout         mo.Option[time.Duration]
+	Offer           HandshakeOffer
+	ProtocolFactory ServerProtocolFactory[Req, Resp]
+}
+
+// ClientHandshakeOptions is similar to `ServerHandshakeOptions`,
+// but for the client protocol and the streaming protocol of the client
+type ClientHandshakeOptions[Req Request, Resp Response] struct {
+	Timeout         mo.Option[time.Duration]
+	Offer           HandshakeOffer
+	ProtocolFactory ClientProtocolFactory[Req, Resp]
+}
+
+type handshakeResultContextKey struct{}
+
+func HandshakeResultFromContext(
+	ctx context.Context,
+) (HandshakeResult, bool) {
+	result, isPresent :=
+		ctx.Value(handshakeResultContextKey{}).(HandshakeResult)
+	return result, isPresent
+}
+
+func ContextWithHandshakeResult(
+	ctx context.Context,
+	result HandshakeResult,
+) context.Context {
+	return context.WithValue(ctx, handshakeResultContextKey{}, result)
+}
+
+// NegotiateHandshake chooses the first version and the first compression
+// of the server offer supported by the client, the least max frame size
+// and the streaming only if both peers support it
+func NegotiateHandshake(
+	serverOffer HandshakeOffer,
+	clientOffer HandshakeOffer,
+) (HandshakeResult, error) {
+	for _, offer := range []HandshakeOffer{serverOffer, clientOffer} {
+		if err := validateMaxFrameSize(offer.Capabilities.MaxFrameSize); err != nil {
+			return HandshakeResult{}, err
+		}
+	}
+
+	version, isFound :=
+		firstCommonItem(serverOffer.Versions, clientOffer.Versions)
+	if !isFound {
+		return HandshakeResult{}, ErrNoCommonProtocolVersion
+	}
+
+	compression := mo.TupleToOption(firstCommonItem(
+		serverOffer.Capabilities.Compressions,
+		clientOffer.Capabilities.Compressions,
+	))
+
+	maxFrameSize := serverOffer.Capabilities.MaxFrameSize
+	if clientMaxFrameSize, isPresent :=
+		clientOffer.Capabilities.MaxFrameSize.Get(); isPresent {
+		maxFrameSize = mo.Some(min(
+			maxFrameSize.OrElse(clientMaxFrameSize),
+			clientMaxFrameSize,
+		))
+	}
+
+	result := HandshakeResult{
+		Version:      version,
+		Compression:  compression,
+		MaxFrameSize: maxFrameSize,
+		Streaming: serverOffer.Capabilities.Streaming &&
+			clientOffer.Capabilities.Streaming,
+	}
+	return result, nil
+}
+
+type handshakeMessage struct {
+	Versions     []string `json:"versions,omitempty"`
+	Compressions []string `json:"compressions,omitempty"`
+	// zero means no limit
+	MaxFrameSize int    `json:"max_frame_size,omitempty"`
+	Streaming    bool   `json:"streaming,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+func newHandshakeOfferMessage(offer HandshakeOffer) handshakeMessage {
+	return handshakeMessage{
+		Versions:     offer.Versions,
+		Compressions: offer.Capabilities.Compressions,
+		MaxFrameSize: offer.Capabilities.MaxFrameSize.OrEmpty(),
+		Streaming:    offer.Capabilities.Streaming,
+	}
+}
+
+func newHandshakeResultMessage(result HandshakeResult) handshakeMessage {
+	var compressions []string
+	if compression, isPresent := result.Compression.Get(); isPresent {
+		compressions = []string{compression}
+	}
+
+	return handshakeMessage{
+		Versions:     []string{result.Version},
+		Compressions: compressions,
+		MaxFrameSize: result.MaxFrameSize.OrEmpty(),
+		Streaming:    result.Streaming,
+	}
+}
+
+func (message handshakeMessage) offer() HandshakeOffer {
+	return HandshakeOffer{
+		Versions: message.Versions,
+		Capabilities: HandshakeCapabilities{
+			Compressions: message.Compressions,
+			MaxFrameSize: mo.EmptyableToOption(message.MaxFrameSize),
+			Streaming:    message.Streaming,
+		},
+	}
+}
+
+func (message handshakeMessage) result() (HandshakeResult, error) {
+	if message.Error != "" {
+		return HandshakeResult{}, fmt.Errorf(
+			"%w: %s",
+			ErrHandshakeIsRejected,
+			message.Error,
+		)
+	}
+
+	if len(message.Versions) != 1 || len(message.Compressions) > 1 {
+		return HandshakeResult{}, errors.New("invalid handshake result")
+	}
+
+	result := HandshakeResult{
+		Version:      message.Versions[0],
+		Compression:  mo.None[string](),
+		MaxFrameSize: mo.EmptyableToOption(message.MaxFrameSize),
+		Streaming:    message.Streaming,
+	}
+	if err := validateMaxFrameSize(result.MaxFrameSize); err != nil {
+		return HandshakeResult{}, err
+	}
+	if len(message.Compressions) == 1 {
+		result.Compression = mo.Some(message.Compressions[0])
+	}
+
+	return result, nil
+}
+
+// handshakeConnection reads and writes the handshake messages
+// as the lines of JSON; the bytes read ahead remain in the reader,
+// so the connection should be replaced with the buffered one after
+// the handshake
+type handshakeConnection struct {
+	connection net.Conn
+	reader     *bufio.Reader
+}
+
+func newHandshakeConnection(connection net.Conn) handshakeConnection {
+	return handshakeConnection{
+		connection: connection,
+		reader: bufio.NewReaderSize(
+			connection,
+			DefaultMaxHandshakeMessageSize,
+		),
+	}
+}
+
+func (connection handshakeConnection) setDeadline(
+	timeout mo.Option[time.Duration],
+) error {
+	var deadline time.Time
+	if timeout, isPresent := timeout.Get(); isPresent {
+		deadline = time.Now().Add(timeout)
+	}
+
+	if err := connection.connection.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("unable to set the deadline: %w", err)
+	}
+
+	return nil
+}
+
+func (connection handshakeConnection) readMessage() (
+	handshakeMessage,
+	error,
+) {
+	line, err := connection.reader.ReadSlice('\n')
+	if err != nil {
+		return handshakeMessage{}, fmt.Errorf(
+			"unable to read the handshake message: %w",
+			err,
+		)
+	}
+
+	var message handshakeMessage
+	if err := json.Unmarshal(line, &message); err != nil {
+		return handshakeMessage{}, fmt.Errorf(
+			"unable to unmarshal the handshake message: %w",
+			err,
+		)
+	}
+
+	return message, nil
+}
+
+func (connection handshakeConnection) writeMessage(
+	message handshakeMessage,
+) error {
+	marshalledMessage, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("unable to marshal the handshake message: %w", err)
+	}
+
+	if _, err := connection.connection.Write(
+		append(marshalledMessage, '\n'),
+	); err != nil {
+		return fmt.Errorf("unable to write the handshake message: %w", err)
+	}
+
+	return nil
+}
+
+func (connection handshakeConnection) bufferedConnection() net.Conn {
+	return bufferedConnection{
+		Conn:   connection.connection,
+		reader: connection.reader,
+	}
+}
+
+func performServerHandshake[Req Request, Resp Response](
+	connection net.Conn,
+	options ServerHandshakeOptions[Req, Resp],
+) (HandshakeResult, ServerProtocol[Req, Resp], net.Conn, error) {
+	handshakeConnection := newHandshakeConnection(connection)
+	if err := handshakeConnection.setDeadline(options.Timeout); err != nil {
+		return HandshakeResult{}, nil, nil, err
+	}
+
+	clientMessage, err := handshakeConnection.readMessage()
+	if err != nil {
+		return HandshakeResult{}, nil, nil, err
+	}
+
+	// the protocol is chosen before the reply,
+	// so the client is notified about the failure of it as well
+	var protocol ServerProtocol[Req, Resp]
+	result, negotiationErr :=
+		NegotiateHandshake(options.Offer, clientMessage.offer())
+	if negotiationErr == nil {
+		protocol, negotiationErr = options.ProtocolFactory(result)
+	}
+
+	serverMessage := newHandshakeResultMessage(result)
+	if negotiationErr != nil {
+		serverMessage = handshakeMessage{Error: negotiationErr.Error()}
+	}
+
+	if err := handshakeConnection.writeMessage(serverMessage); err != nil {
+		return HandshakeResult{}, nil, nil, err
+	}
+	if negotiationErr != nil {
+		return HandshakeResult{}, nil, nil, fmt.Errorf(
+			"unable to negotiate: %w",
+			negotiationErr,
+		)
+	}
+
+	// reset the deadline
+	if err :=
+		handshakeConnection.setDeadline(mo.None[time.Duration]()); err != nil {
+		return HandshakeResult{}, nil, nil, err
+	}
+
+	return result,
+		newLimitedServerProtocol(protocol, result.MaxFrameSize),
+		handshakeConnection.bufferedConnection(),
+		nil
+}
+
+func performClientHandshake[Req Request, Resp Response](
+	connection net.Conn,
+	options ClientHandshakeOptions[Req, Resp],
+) (HandshakeResult, ClientProtocol[Req, Resp], net.Conn, error) {
+	handshakeConnection := newHandshakeConnection(connection)
+	if err := handshakeConnection.setDeadline(options.Timeout); err != nil {
+		return HandshakeResult{}, nil, nil, err
+	}
+
+	if err := handshakeConnection.writeMessage(
+		newHandshakeOfferMessage(options.Offer),
+	); err != nil {
+		return HandshakeResult{}, nil, nil, err
+	}
+
+	serverMessage, err := handshakeConnection.readMessage()
+	if err != nil {
+		return HandshakeResult{}, nil, nil, err
+	}
+
+	result, err := serverMessage.result()
+	if err != nil {
+		return HandshakeResult{}, nil, nil, err
+	}
+
+	// the server must choose among the offered options
+	if !slices.Contains(options.Offer.Versions, result.Version) {
+		return HandshakeResult{}, nil, nil, fmt.Errorf(
+			"server chose the unsupported version %q",
+			result.Version,
+		)
+	}
+	if compression, isPresent := result.Compression.Get(); isPresent &&
+		!slices.Contains(options.Offer.Capabilities.Compressions, compression) {
+		return HandshakeResult{}, nil, nil, fmt.Errorf(
+			"server chose the unsupported compression %q",
+			compression,
+		)
+	}
+
+	protocol, err := options.ProtocolFactory(result)
+	if err != nil {
+		return HandshakeResult{}, nil, nil, fmt.Errorf(
+			"unable to choose the client protocol: %w",
+			err,
+		)
+	}
+
+	// reset the deadline
+	if err :=
+		handshakeConnection.setDeadline(mo.None[time.Duration]()); err != nil {
+		return HandshakeResult{}, nil, nil, err
+	}
+
+	return result,
+		newLimitedClientProtocol(protocol, result.MaxFrameSize),
+		handshakeConnection.bufferedConnection(),
+		nil
+}
+
+// limitedServerProtocol applies the negotiated max frame size
+// both to the requests and to the responses
+type limitedServerProtocol[Req Request, Resp Response] struct {
+	ServerProtocol[Req, Resp]
+
+	maxTokenSize int
+	maxFrameSize int
+}
+
+func newLimitedServerProtocol[Req Request, Resp Response](
+	protocol ServerProtocol[Req, Resp],
+	maxFrameSize mo.Option[int],
+) ServerProtocol[Req, Resp] {
+	maxFrameSizeValue, isPresent := maxFrameSize.Get()
+	if !isPresent {
+		return protocol
+	}
+
+	return limitedServerProtocol[Req, Resp]{
+		ServerProtocol: protocol,
+		maxTokenSize:   min(protocol.MaxTokenSize(), maxFrameSizeValue),
+		maxFrameSize:   maxFrameSizeValue,
+	}
+}
+
+func (protocol limitedServerProtocol[Req, Resp]) InitialScannerBufferSize() int { //nolint:lll
+	return min(
+		protocol.ServerProtocol.InitialScannerBufferSize(),
+		protocol.maxTokenSize,
+	)
+}
+
+func (protocol limitedServerProtocol[Req, Resp]) MaxTokenSize() int {
+	return protocol.maxTokenSize
+}
+
+func (protocol limitedServerProtocol[Req, Resp]) MarshalResponse(
+	response Resp,
+) ([]byte, error) {
+	data, err := protocol.ServerProtocol.MarshalResponse(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkFrameSize(data, protocol.maxFrameSize)
+}
+
+// limitedClientProtocol applies the negotiated max frame size
+// both to the requests and to the responses
+type limitedClientProtocol[Req Request, Resp Response] struct {
+	ClientProtocol[Req, Resp]
+
+	maxTokenSize int
+	maxFrameSize int
+}
+
+func newLimitedClientProtocol[Req Request, Resp Response](
+	protocol ClientProtocol[Req, Resp],
+	maxFrameSize mo.Option[int],
+) ClientProtocol[Req, Resp] {
+	maxFrameSizeValue, isPresent := maxFrameSize.Get()
+	if !isPresent {
+		return protocol
+	}
+
+	return limitedClientProtocol[Req, Resp]{
+		ClientProtocol: protocol,
+		maxTokenSize:   min(protocol.MaxTokenSize(), maxFrameSizeValue),
+		maxFrameSize:   maxFrameSizeValue,
+	}
+}
+
+func (protocol limitedClientProtocol[Req, Resp]) InitialScannerBufferSize() int { //nolint:lll
+	return min(
+		protocol.ClientProtocol.InitialScannerBufferSize(),
+		protocol.maxTokenSize,
+	)
+}
+
+func (protocol limitedClientProtocol[Req, Resp]) MaxTokenSize() int {
+	return protocol.maxTokenSize
+}
+
+func (protocol limitedClientProtocol[Req, Resp]) MarshalRequest(
+	request Req,
+) ([]byte, error) {
+	data, err := protocol.ClientProtocol.MarshalRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkFrameSize(data, protocol.maxFrameSize)
+}
+
+// validateMaxFrameSize rejects the sizes that make the scanner buffer invalid
+func validateMaxFrameSize(maxFrameSize mo.Option[int]) error {
+	if maxFrameSize, isPresent := maxFrameSize.Get(); isPresent &&
+		maxFrameSize <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidMaxFrameSize, maxFrameSize)
+	}
+
+	return nil
+}
+
+func checkFrameSize(data []byte, maxFrameSize int) ([]byte, error) {
+	if len(data) > maxFrameSize {
+		return nil, fmt.Errorf(
+			"%w: %d > %d",
+			ErrMaxFrameSizeIsExceeded,
+			len(data),
+			maxFrameSize,
+		)
+	}
+
+	return data, nil
+}
+
+func firstCommonItem[T comparable](
+	preferredItems []T,
+	otherItems []T,
+) (T, bool) {
+	for _, item := range preferredItems {
+		if slices.Contains(otherItems, item) {
+			return item, true
+		}
+	}
+
+	var zeroItem T
+	return zeroItem, false
+}
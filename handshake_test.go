@@ -0,0 +1,397 @@
+package tcpServer_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestHandshakeResultFromContext(test *testing.T) {
+	_, isPresent := tcpServer.HandshakeResultFromContext(context.Background())
+
+	assert.False(test, isPresent)
+}
+
+func TestNegotiateHandshake(test *testing.T) {
+	type args struct {
+		serverOffer tcpServer.HandshakeOffer
+		clientOffer tcpServer.HandshakeOffer
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    tcpServer.HandshakeResult
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/with all the capabilities",
+			args: args{
+				serverOffer: tcpServer.HandshakeOffer{
+					Versions: []string{"v3", "v2", "v1"},
+					Capabilities: tcpServer.HandshakeCapabilities{
+						Compressions: []string{"zstd", "gzip"},
+						MaxFrameSize: mo.Some(1024),
+						Streaming:    true,
+					},
+				},
+				clientOffer: tcpServer.HandshakeOffer{
+					Versions: []string{"v1", "v2"},
+					Capabilities: tcpServer.HandshakeCapabilities{
+						Compressions: []string{"gzip", "zstd"},
+						MaxFrameSize: mo.Some(512),
+						Streaming:    true,
+					},
+				},
+			},
+			want: tcpServer.HandshakeResult{
+				Version:      "v2",
+				Compression:  mo.Some("zstd"),
+				MaxFrameSize: mo.Some(512),
+				Streaming:    true,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/without the common capabilities",
+			args: args{
+				serverOffer: tcpServer.HandshakeOffer{
+					Versions: []string{"v1"},
+					Capabilities: tcpServer.HandshakeCapabilities{
+						Compressions: []string{"zstd"},
+						MaxFrameSize: mo.None[int](),
+						Streaming:    true,
+					},
+				},
+				clientOffer: tcpServer.HandshakeOffer{
+					Versions: []string{"v1"},
+					Capabilities: tcpServer.HandshakeCapabilities{
+						Compressions: []string{"gzip"},
+						MaxFrameSize: mo.Some(512),
+						Streaming:    false,
+					},
+				},
+			},
+			want: tcpServer.HandshakeResult{
+				Version:      "v1",
+				Compression:  mo.None[string](),
+				MaxFrameSize: mo.Some(512),
+				Streaming:    false,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/without the max frame sizes",
+			args: args{
+				serverOffer: tcpServer.HandshakeOffer{
+					Versions: []string{"v1"},
+				},
+				clientOffer: tcpServer.HandshakeOffer{
+					Versions: []string{"v1"},
+				},
+			},
+			want: tcpServer.HandshakeResult{
+				Version:      "v1",
+				Compression:  mo.None[string](),
+				MaxFrameSize: mo.None[int](),
+				Streaming:    false,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/invalid max frame size",
+			args: args{
+				serverOffer: tcpServer.HandshakeOffer{
+					Versions: []string{"v1"},
+				},
+				clientOffer: tcpServer.HandshakeOffer{
+					Versions: []string{"v1"},
+					Capabilities: tcpServer.HandshakeCapabilities{
+						MaxFrameSize: mo.Some(-5),
+					},
+				},
+			},
+			want: tcpServer.HandshakeResult{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, tcpServer.ErrInvalidMaxFrameSize)
+			},
+		},
+		{
+			name: "error/no common version",
+			args: args{
+				serverOffer: tcpServer.HandshakeOffer{
+					Versions: []string{"v2"},
+				},
+				clientOffer: tcpServer.HandshakeOffer{
+					Versions: []string{"v1"},
+				},
+			},
+			want: tcpServer.HandshakeResult{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, tcpServer.ErrNoCommonProtocolVersion)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := tcpServer.NegotiateHandshake(
+				data.args.serverOffer,
+				data.args.clientOffer,
+			)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestHandshake(test *testing.T) {
+	for _, data := range []struct {
+		name            string
+		serverOffer     tcpServer.HandshakeOffer
+		clientOffer     tcpServer.HandshakeOffer
+		request         string
+		wantResult      mo.Option[tcpServer.HandshakeResult]
+		wantResponse    string
+		wantClientErr   assert.ErrorAssertionFunc
+		wantHandlingErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			serverOffer: tcpServer.HandshakeOffer{
+				Versions: []string{"v2", "v1"},
+				Capabilities: tcpServer.HandshakeCapabilities{
+					Compressions: []string{"gzip"},
+					MaxFrameSize: mo.Some(1024),
+				},
+			},
+			clientOffer: tcpServer.HandshakeOffer{
+				Versions: []string{"v1", "v2"},
+				Capabilities: tcpServer.HandshakeCapabilities{
+					Compressions: []string{"gzip"},
+				},
+			},
+			request: "request",
+			wantResult: mo.Some(tcpServer.HandshakeResult{
+				Version:      "v2",
+				Compression:  mo.Some("gzip"),
+				MaxFrameSize: mo.Some(1024),
+				Streaming:    false,
+			}),
+			wantResponse:    "v2:request",
+			wantClientErr:   assert.NoError,
+			wantHandlingErr: assert.NoError,
+		},
+		{
+			name: "error/too large response",
+			serverOffer: tcpServer.HandshakeOffer{
+				Versions: []string{"v1"},
+			},
+			clientOffer: tcpServer.HandshakeOffer{
+				Versions: []string{"v1"},
+				Capabilities: tcpServer.HandshakeCapabilities{
+					MaxFrameSize: mo.Some(16),
+				},
+			},
+			request: "a long request",
+			wantResult: mo.Some(tcpServer.HandshakeResult{
+				Version:      "v1",
+				Compression:  mo.None[string](),
+				MaxFrameSize: mo.Some(16),
+				Streaming:    false,
+			}),
+			wantResponse:  "",
+			wantClientErr: assert.NoError,
+			wantHandlingErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(test, err, tcpServer.ErrMaxFrameSizeIsExceeded)
+			},
+		},
+		{
+			name: "error/no common version",
+			serverOffer: tcpServer.HandshakeOffer{
+				Versions: []string{"v2"},
+			},
+			clientOffer: tcpServer.HandshakeOffer{
+				Versions: []string{"v1"},
+			},
+			request:      "",
+			wantResult:   mo.None[tcpServer.HandshakeResult](),
+			wantResponse: "",
+			wantClientErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(test, err, tcpServer.ErrHandshakeIsRejected)
+			},
+			wantHandlingErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(test, err, tcpServer.ErrNoCommonProtocolVersion)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			serverConnection, clientConnection := net.Pipe()
+			defer clientConnection.Close()
+
+			handler := tcpServer.NewDefaultConnectionHandler(
+				tcpServer.DefaultConnectionHandlerOptions[string, string]{
+					Handshake: mo.Some(tcpServer.ServerHandshakeOptions[string, string]{
+						Offer: data.serverOffer,
+						ProtocolFactory: func(
+							result tcpServer.HandshakeResult,
+						) (tcpServer.ServerProtocol[string, string], error) {
+							return testLineProtocol{}, nil
+						},
+					}),
+					RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+						ctx context.Context,
+						request string,
+					) (string, error) {
+						result, isPresent := tcpServer.HandshakeResultFromContext(ctx)
+						if !isPresent {
+							return "", errors.New("handshake result is missed")
+						}
+
+						return result.Version + ":" + request, nil
+					}),
+				},
+			)
+
+			handlingErr := make(chan error, 1)
+			go func() {
+				defer serverConnection.Close()
+
+				handlingErr <- handler.HandleConnection(
+					context.Background(),
+					serverConnection,
+				)
+			}()
+
+			client, err := tcpServer.NewTCPClientWithHandshake(
+				clientConnection,
+				tcpServer.TCPClientOptions[string, string]{
+					Handshake: mo.Some(tcpServer.ClientHandshakeOptions[string, string]{
+						Offer: data.clientOffer,
+						ProtocolFactory: func(
+							result tcpServer.HandshakeResult,
+						) (tcpServer.ClientProtocol[string, string], error) {
+							return testLineProtocol{}, nil
+						},
+					}),
+				},
+			)
+			data.wantClientErr(test, err)
+
+			var gotResponse string
+			if err == nil {
+				assert.Equal(test, data.wantResult, client.HandshakeResult())
+
+				gotResponse, _ = client.SendRequest(data.request)
+				require.NoError(test, client.Close())
+			}
+
+			assert.Equal(test, data.wantResponse, gotResponse)
+			data.wantHandlingErr(test, <-handlingErr)
+		})
+	}
+}
+
+func TestHandshake_withRawClient(test *testing.T) {
+	for _, data := range []struct {
+		name            string
+		offer           string
+		request         string
+		wantReply       string
+		wantHandlingErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "error/too large request",
+			offer:     `{"versions":["v1"],"max_frame_size":16}`,
+			request:   "request longer than the max frame size\n",
+			wantReply: `{"versions":["v1"],"max_frame_size":16}` + "\n",
+			wantHandlingErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(test, err, bufio.ErrTooLong)
+			},
+		},
+		{
+			name:      "error/negative max frame size",
+			offer:     `{"versions":["v1"],"max_frame_size":-5}`,
+			request:   "",
+			wantReply: `{"error":"invalid max frame size: -5"}` + "\n",
+			wantHandlingErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(test, err, tcpServer.ErrInvalidMaxFrameSize)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			serverConnection, clientConnection := net.Pipe()
+			defer clientConnection.Close()
+
+			handler := tcpServer.NewDefaultConnectionHandler(
+				tcpServer.DefaultConnectionHandlerOptions[string, string]{
+					Handshake: mo.Some(tcpServer.ServerHandshakeOptions[string, string]{
+						Offer: tcpServer.HandshakeOffer{Versions: []string{"v1"}},
+						ProtocolFactory: func(
+							result tcpServer.HandshakeResult,
+						) (tcpServer.ServerProtocol[string, string], error) {
+							return testLineProtocol{}, nil
+						},
+					}),
+					RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+						ctx context.Context,
+						request string,
+					) (string, error) {
+						return request, nil
+					}),
+				},
+			)
+
+			handlingErr := make(chan error, 1)
+			go func() {
+				defer serverConnection.Close()
+
+				handlingErr <- handler.HandleConnection(
+					context.Background(),
+					serverConnection,
+				)
+			}()
+
+			_, err := clientConnection.Write([]byte(data.offer + "\n"))
+			require.NoError(test, err)
+
+			reader := bufio.NewReader(clientConnection)
+			reply, err := reader.ReadString('\n')
+			require.NoError(test, err)
+			assert.Equal(test, data.wantReply, reply)
+
+			if data.request != "" {
+				_, err = clientConnection.Write([]byte(data.request))
+				require.NoError(test, err)
+			}
+
+			data.wantHandlingErr(test, <-handlingErr)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package tcpServer
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrHeartbeatTimeout = errors.New("heartbeat timeout")
+)
+
+type HeartbeatProtocol[Req Request, Resp Response] interface {
+	NewPingRequest() (Req, error)
+	IsPingRequest(request Req) bool
+	NewPongResponse() (Resp, error)
+	IsPongResponse(response Resp) bool
+}
+
+type HeartbeatOptions[Req Request, Resp Response] struct {
+	Interval       time.Duration
+	MaxMissedPongs int
+	Protocol       HeartbeatProtocol[Req, Resp]
+}
+
+// Timeout returns the time without pings or pongs after which the peer
+// is considered dead
+func (options HeartbeatOptions[Req, Resp]) Timeout() time.Duration {
+	return options.Interval * time.Duration(max(options.MaxMissedPongs, 1))
+}
@@ -0,0 +1,274 @@
+package tcpServer_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	tcpServerExternalMocks "github.com/thewizardplusplus/go-tcp-server/mocks/external/github.com/thewizardplusplus/go-tcp-server"
+	tcpServerMocks "github.com/thewizardplusplus/go-tcp-server/mocks/github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestHeartbeatOptions_Timeout(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		options tcpServer.HeartbeatOptions[string, string]
+		want    time.Duration
+	}{
+		{
+			name: "success/with missed pongs",
+			options: tcpServer.HeartbeatOptions[string, string]{
+				Interval:       5 * time.Second,
+				MaxMissedPongs: 3,
+			},
+			want: 15 * time.Second,
+		},
+		{
+			name: "success/without missed pongs",
+			options: tcpServer.HeartbeatOptions[string, string]{
+				Interval: 5 * time.Second,
+			},
+			want: 5 * time.Second,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.options.Timeout()
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestDefaultConnectionHandler_HandleRequest_withHeartbeat(
+	test *testing.T,
+) {
+	const acceptableDeadlineError = time.Minute
+
+	test.Run("success/ping request", func(test *testing.T) {
+		serverProtocolMock :=
+			tcpServerExternalMocks.NewMockServerProtocol[string, string](test)
+		serverProtocolMock.EXPECT().
+			ParseRequest([]byte("ping")).
+			Return("ping", nil)
+		serverProtocolMock.EXPECT().
+			MarshalResponse("pong").
+			Return([]byte("marshalled-pong"), nil)
+
+		// the request handler shouldn't be called
+		requestHandlerMock :=
+			tcpServerExternalMocks.NewMockRequestHandler[string, string](test)
+
+		netConnMock := tcpServerMocks.NewMocknetConn(test)
+		netConnMock.EXPECT().
+			SetReadDeadline(mock.MatchedBy(func(deadline time.Time) bool {
+				expectedDeadline := time.Now().Add(15 * time.Minute)
+				return expectedDeadline.Sub(deadline).Abs() < acceptableDeadlineError
+			})).
+			Return(nil)
+		netConnMock.EXPECT().
+			Write([]byte("marshalled-pong")).
+			RunAndReturn(func(data []byte) (int, error) {
+				return len(data), nil
+			})
+
+		handler := tcpServer.NewDefaultConnectionHandler(
+			tcpServer.DefaultConnectionHandlerOptions[string, string]{
+				ReadTimeout: mo.Some(time.Hour),
+				Heartbeat: mo.Some(tcpServer.HeartbeatOptions[string, string]{
+					Interval:       5 * time.Minute,
+					MaxMissedPongs: 3,
+					Protocol:       testHeartbeatProtocol{},
+				}),
+				ServerProtocol: serverProtocolMock,
+				RequestHandler: requestHandlerMock,
+			},
+		)
+		err := handler.HandleRequest(
+			context.Background(),
+			netConnMock,
+			bufio.NewScanner(strings.NewReader("ping")),
+		)
+
+		assert.NoError(test, err)
+	})
+
+	test.Run("error/heartbeat timeout", func(test *testing.T) {
+		serverConnection, clientConnection := net.Pipe()
+		defer serverConnection.Close()
+		defer clientConnection.Close()
+
+		handler := tcpServer.NewDefaultConnectionHandler(
+			tcpServer.DefaultConnectionHandlerOptions[string, string]{
+				Heartbeat: mo.Some(tcpServer.HeartbeatOptions[string, string]{
+					Interval:       time.Millisecond,
+					MaxMissedPongs: 3,
+					Protocol:       testHeartbeatProtocol{},
+				}),
+				ServerProtocol: tcpServerExternalMocks.
+					NewMockServerProtocol[string, string](test),
+				RequestHandler: tcpServerExternalMocks.
+					NewMockRequestHandler[string, string](test),
+			},
+		)
+		err := handler.HandleRequest(
+			context.Background(),
+			serverConnection,
+			bufio.NewScanner(serverConnection),
+		)
+
+		assert.ErrorIs(test, err, tcpServer.ErrHeartbeatTimeout)
+	})
+}
+
+func TestTCPClient_withHeartbeat(test *testing.T) {
+	for _, data := range []struct {
+		name              string
+		answeredPingCount int
+	}{
+		{
+			name:              "success/without answered pings",
+			answeredPingCount: 0,
+		},
+		{
+			name:              "success/with answered pings",
+			answeredPingCount: 3,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			serverConnection, clientConnection := net.Pipe()
+			defer serverConnection.Close()
+
+			client := tcpServer.NewTCPClientFromConnection(
+				clientConnection,
+				tcpServer.TCPClientOptions[string, string]{
+					Heartbeat: mo.Some(tcpServer.HeartbeatOptions[string, string]{
+						Interval:       10 * time.Millisecond,
+						MaxMissedPongs: 2,
+						Protocol:       testHeartbeatProtocol{},
+					}),
+					ClientProtocol: testLineProtocol{},
+				},
+			)
+			defer client.Close()
+
+			scanner := bufio.NewScanner(serverConnection)
+			for range data.answeredPingCount {
+				require.True(test, scanner.Scan())
+				require.Equal(test, "ping", scanner.Text())
+
+				_, err := serverConnection.Write([]byte("pong\n"))
+				require.NoError(test, err)
+			}
+
+			// stop answering the pings, so the client should close the connection
+			for scanner.Scan() {
+				require.Equal(test, "ping", scanner.Text())
+			}
+
+			_, err := client.SendRequest("request")
+			assert.Error(test, err)
+		})
+	}
+}
+
+func TestTCPClient_withHeartbeatAndLatePong(test *testing.T) {
+	serverConnection, clientConnection := net.Pipe()
+	defer serverConnection.Close()
+
+	go func() {
+		defer serverConnection.Close()
+
+		scanner := bufio.NewScanner(serverConnection)
+		for scanner.Scan() {
+			response := "pong\n"
+			if scanner.Text() == "request" {
+				// the late pong arrives before the response
+				response = "pong\nresponse\n"
+			}
+
+			if _, err := serverConnection.Write([]byte(response)); err != nil {
+				return
+			}
+		}
+	}()
+
+	client := tcpServer.NewTCPClientFromConnection(
+		clientConnection,
+		tcpServer.TCPClientOptions[string, string]{
+			Heartbeat: mo.Some(tcpServer.HeartbeatOptions[string, string]{
+				Interval:       10 * time.Millisecond,
+				MaxMissedPongs: 2,
+				Protocol:       testHeartbeatProtocol{},
+			}),
+			ClientProtocol: testLineProtocol{},
+		},
+	)
+	defer client.Close()
+
+	for range 3 {
+		response, err := client.SendRequest("request")
+		require.NoError(test, err)
+		assert.Equal(test, "response", response)
+
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+type testHeartbeatProtocol struct{}
+
+func (testHeartbeatProtocol) NewPingRequest() (string, error) {
+	return "ping", nil
+}
+
+func (testHeartbeatProtocol) IsPingRequest(request string) bool {
+	return request == "ping"
+}
+
+func (testHeartbeatProtocol) NewPongResponse() (string, error) {
+	return "pong", nil
+}
+
+func (testHeartbeatProtocol) IsPongResponse(response string) bool {
+	return response == "pong"
+}
+
+type testLineProtocol struct{}
+
+func (testLineProtocol) InitialScannerBufferSize() int {
+	return 4 * 1024
+}
+
+func (testLineProtocol) MaxTokenSize() int {
+	return 64 * 1024
+}
+
+func (testLineProtocol) ExtractToken(
+	data []byte,
+	isLatestData bool,
+) (offsetToNextToken int, token []byte, err error) {
+	return bufio.ScanLines(data, isLatestData)
+}
+
+func (testLineProtocol) ParseRequest(token []byte) (string, error) {
+	return string(token), nil
+}
+
+func (testLineProtocol) MarshalRequest(request string) ([]byte, error) {
+	return []byte(request + "\n"), nil
+}
+
+func (testLineProtocol) ParseResponse(token []byte) (string, error) {
+	return string(token), nil
+}
+
+func (testLineProtocol) MarshalResponse(response string) ([]byte, error) {
+	return []byte(response + "\n"), nil
+}
@@ -0,0 +1,113 @@
+package tcpServer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/samber/mo"
+)
+
+var (
+	ErrConnectionIsHijacked      = errors.New("connection is hijacked")
+	ErrHijackingIsUnavailable    = errors.New("hijacking is unavailable")
+	ErrHijackingIsAlreadyPending = errors.New("hijacking is already pending")
+)
+
+// HijackHandler takes over the connection after the response to the request,
+// which has requested the hijacking, is written; the connection is passed
+// without the timeouts of the handling (and after the TLS upgrade,
+// it's the TLS connection); the handler is responsible for closing it
+type HijackHandler func(ctx context.Context, connection net.Conn)
+
+// Hijack requests the taking over of the connection;
+// it's available only within the handling of the connection
+// by `DefaultConnectionHandler.HandleConnection()`
+func (session *Session[Req, Resp]) Hijack(handler HijackHandler) error {
+	if !session.splitFunc.IsPresent() {
+		return ErrHijackingIsUnavailable
+	}
+
+	if !session.hijackHandler.CompareAndSwap(nil, &handler) {
+		return ErrHijackingIsAlreadyPending
+	}
+
+	return nil
+}
+
+// setHijackableConnection is called by
+// `DefaultConnectionHandler.HandleConnection()` and after the TLS upgrade;
+// the timeouts are applied over the given connection, so the hijack handler
+// gets it without them; the wrapped split function remembers the data
+// buffered by the scanner, but not consumed yet, to pass it to the handler
+func (session *Session[Req, Resp]) setHijackableConnection(
+	connection net.Conn,
+	splitFunc bufio.SplitFunc,
+) {
+	session.rawConnection = connection
+	session.connection = connection
+	session.splitFunc = mo.Some(splitFunc)
+	session.bufferedData = nil
+
+	scannerSplitFunc := splitFunc
+	if timeoutOptions, isPresent := session.timeoutOptions.Get(); isPresent {
+		connectionWithTimeouts := newTimeoutConnection(connection, timeoutOptions)
+		session.connection = connectionWithTimeouts
+		scannerSplitFunc = connectionWithTimeouts.wrapSplitFunc(splitFunc)
+	}
+
+	session.scanner = InitializeScanner(InitializeScannerParams[Req, Resp]{
+		Reader:       session.connection,
+		BaseProtocol: session.handler.options.ServerProtocol,
+	})
+	session.scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := scannerSplitFunc(data, atEOF)
+		if advance >= 0 && advance <= len(data) {
+			session.bufferedData = data[advance:]
+		}
+
+		return advance, token, err
+	})
+}
+
+func (session *Session[Req, Resp]) hijack(ctx context.Context) (bool, error) {
+	hijackHandler := session.hijackHandler.Load()
+	if hijackHandler == nil {
+		return false, nil
+	}
+
+	// after the TLS upgrade, the connection is taken over encrypted
+	connection := session.rawConnection
+
+	// reset the deadlines set by the handling
+	if err := connection.SetDeadline(time.Time{}); err != nil {
+		return false, fmt.Errorf("unable to reset the deadlines: %w", err)
+	}
+
+	(*hijackHandler)(ctx, hijackedConnection{
+		Conn: connection,
+
+		reader: io.MultiReader(
+			bytes.NewReader(slices.Clone(session.bufferedData)),
+			connection,
+		),
+	})
+
+	return true, nil
+}
+
+type hijackedConnection struct {
+	net.Conn
+
+	reader io.Reader
+}
+
+func (connection hijackedConnection) Read(buffer []byte) (int, error) {
+	return connection.reader.Read(buffer)
+}
@@ -0,0 +1,239 @@
+package tcpServer_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestSession_Hijack(test *testing.T) {
+	hijackedConnections := make(chan net.Conn, 1)
+	handlingErrs := make(chan error, 1)
+	handler := tcpServer.NewDefaultConnectionHandler(
+		tcpServer.DefaultConnectionHandlerOptions[string, string]{
+			ReadTimeout:    mo.Some(100 * time.Millisecond),
+			ServerProtocol: testLineProtocol{},
+			RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+				ctx context.Context,
+				request string,
+			) (string, error) {
+				session, isPresent := tcpServer.SessionFromContext[string, string](ctx)
+				require.True(test, isPresent)
+
+				err := session.Hijack(func(ctx context.Context, connection net.Conn) {
+					// pass the connection beyond the connection handling
+					hijackedConnections <- connection
+				})
+				require.NoError(test, err)
+
+				err = session.Hijack(func(ctx context.Context, connection net.Conn) {})
+				assert.ErrorIs(test, err, tcpServer.ErrHijackingIsAlreadyPending)
+
+				return "hijacked:" + request, nil
+			}),
+		},
+	)
+
+	server, err := tcpServer.NewTCPServer(
+		context.Background(),
+		tcpServer.TCPServerOptions{
+			Address: "127.0.0.1:",
+			ConnectionHandler: tcpServer.ConnectionHandlerFunc(func(
+				ctx context.Context,
+				connection net.Conn,
+			) error {
+				err := handler.HandleConnection(ctx, connection)
+				handlingErrs <- err
+
+				return err
+			}),
+			ErrorHandler: func(err error) {
+				// the accepting is interrupted by the server stop
+				if !errors.Is(err, net.ErrClosed) {
+					assert.Fail(test, "unexpected error", err)
+				}
+			},
+		},
+	)
+	require.NoError(test, err)
+
+	serverDone := make(chan struct{})
+	go func() {
+		defer close(serverDone)
+
+		server.Run(context.Background())
+	}()
+	defer func() {
+		server.Stop()
+		<-serverDone
+	}()
+
+	connection, err := net.Dial("tcp", server.Address())
+	require.NoError(test, err)
+	defer connection.Close()
+
+	// the raw data is sent along with the request,
+	// so the server has it buffered by the scanner
+	_, err = connection.Write([]byte("tunnel\nraw data"))
+	require.NoError(test, err)
+
+	reader := bufio.NewReader(connection)
+	response, err := reader.ReadString('\n')
+	require.NoError(test, err)
+	assert.Equal(test, "hijacked:tunnel\n", response)
+
+	assert.ErrorIs(test, <-handlingErrs, tcpServer.ErrConnectionIsHijacked)
+
+	hijackedConnection := <-hijackedConnections
+	defer hijackedConnection.Close()
+
+	_, err = connection.Write([]byte(" and more"))
+	require.NoError(test, err)
+
+	// the connection isn't closed by the server
+	// and its read deadline is reset
+	time.Sleep(200 * time.Millisecond)
+
+	gotData := make([]byte, len("raw data and more"))
+	_, err = io.ReadFull(hijackedConnection, gotData)
+	require.NoError(test, err)
+	assert.Equal(test, "raw data and more", string(gotData))
+
+	_, err = hijackedConnection.Write([]byte("tunneled"))
+	require.NoError(test, err)
+
+	gotData = make([]byte, len("tunneled"))
+	_, err = io.ReadFull(reader, gotData)
+	require.NoError(test, err)
+	assert.Equal(test, "tunneled", string(gotData))
+}
+
+func TestSession_Hijack_afterTLSUpgrade(test *testing.T) {
+	serverTLSConfig, clientTLSConfig := newTestTLSConfigs(test)
+
+	serverConnection, clientConnection := net.Pipe()
+	defer clientConnection.Close()
+
+	hijackingErrs := make(chan error, 1)
+	handler := tcpServer.NewDefaultConnectionHandler(
+		tcpServer.DefaultConnectionHandlerOptions[string, string]{
+			IdleTimeout: mo.Some(50 * time.Millisecond),
+			TLSUpgrade: mo.Some(tcpServer.TLSUpgradeOptions[string, string]{
+				Protocol: testTLSUpgradeProtocol{},
+				Config:   serverTLSConfig,
+			}),
+			ServerProtocol: testLineProtocol{},
+			RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+				ctx context.Context,
+				request string,
+			) (string, error) {
+				session, isPresent := tcpServer.SessionFromContext[string, string](ctx)
+				require.True(test, isPresent)
+
+				err := session.Hijack(func(ctx context.Context, connection net.Conn) {
+					line, err := bufio.NewReader(connection).ReadString('\n')
+					if err != nil {
+						hijackingErrs <- err
+						return
+					}
+
+					_, err = connection.Write([]byte("tunneled:" + line))
+					hijackingErrs <- err
+				})
+				require.NoError(test, err)
+
+				return "hijacked:" + request, nil
+			}),
+		},
+	)
+	go func() {
+		defer serverConnection.Close()
+
+		handler.HandleConnection( //nolint:errcheck
+			context.Background(),
+			serverConnection,
+		)
+	}()
+
+	_, err := clientConnection.Write([]byte("starttls\n"))
+	require.NoError(test, err)
+
+	response, err := bufio.NewReader(clientConnection).ReadString('\n')
+	require.NoError(test, err)
+	require.Equal(test, "tls-accepted\n", response)
+
+	tlsConnection := tls.Client(clientConnection, clientTLSConfig)
+	require.NoError(test, tlsConnection.Handshake())
+
+	_, err = tlsConnection.Write([]byte("tunnel\n"))
+	require.NoError(test, err)
+
+	reader := bufio.NewReader(tlsConnection)
+	response, err = reader.ReadString('\n')
+	require.NoError(test, err)
+	assert.Equal(test, "hijacked:tunnel\n", response)
+
+	// the hijacked connection isn't affected by the timeouts
+	time.Sleep(100 * time.Millisecond)
+
+	_, err = tlsConnection.Write([]byte("raw data\n"))
+	require.NoError(test, err)
+
+	// the hijacked connection remains encrypted
+	response, err = reader.ReadString('\n')
+	require.NoError(test, err)
+	assert.Equal(test, "tunneled:raw data\n", response)
+
+	assert.NoError(test, <-hijackingErrs)
+}
+
+func TestSession_Hijack_withoutConnectionHandling(test *testing.T) {
+	serverConnection, clientConnection := net.Pipe()
+	defer serverConnection.Close()
+	defer clientConnection.Close()
+
+	hijackingErrs := make(chan error, 1)
+	handler := tcpServer.NewDefaultConnectionHandler(
+		tcpServer.DefaultConnectionHandlerOptions[string, string]{
+			ServerProtocol: testLineProtocol{},
+			RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+				ctx context.Context,
+				request string,
+			) (string, error) {
+				session, isPresent := tcpServer.SessionFromContext[string, string](ctx)
+				require.True(test, isPresent)
+
+				hijackingErrs <- session.Hijack(
+					func(ctx context.Context, connection net.Conn) {},
+				)
+				return request, nil
+			}),
+		},
+	)
+
+	go func() {
+		clientConnection.Write([]byte("request\n")) //nolint:errcheck
+		io.Copy(io.Discard, clientConnection)       //nolint:errcheck
+	}()
+
+	scanner := tcpServer.InitializeScanner(
+		tcpServer.InitializeScannerParams[string, string]{
+			Reader:       serverConnection,
+			BaseProtocol: testLineProtocol{},
+		},
+	)
+	err := handler.HandleRequest(context.Background(), serverConnection, scanner)
+	require.NoError(test, err)
+
+	assert.ErrorIs(test, <-hijackingErrs, tcpServer.ErrHijackingIsUnavailable)
+}
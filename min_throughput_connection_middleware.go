@@ -0,0 +1,270 @@
+package tcpServer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samber/mo"
+)
+
+const (
+	DefaultThroughputWindow      = 10 * time.Second
+	DefaultThroughputBucketCount = 10
+)
+
+type TransferDirection string
+
+const (
+	ReadTransferDirection  TransferDirection = "read"
+	WriteTransferDirection TransferDirection = "write"
+)
+
+type LowThroughputError struct {
+	RemoteAddress net.Addr
+	Direction     TransferDirection
+	Rate          float64
+	MinRate       float64
+}
+
+func (err LowThroughputError) Error() string {
+	return fmt.Sprintf(
+		"%s throughput of connection from %s is too low: %.2f B/s < %.2f B/s",
+		err.Direction,
+		err.RemoteAddress,
+		err.Rate,
+		err.MinRate,
+	)
+}
+
+// MinThroughputOptions specifies the minimum rates in bytes per second;
+// the rates are measured over the sliding window split into the buckets
+// and only over the active buckets of it, i.e. the ones in which the data
+// was transferred (or, for writing, was waiting to be transferred),
+// so idle connections are not affected
+type MinThroughputOptions struct {
+	MinReadRate  mo.Option[float64]
+	MinWriteRate mo.Option[float64]
+	Window       mo.Option[time.Duration]
+	BucketCount  mo.Option[int]
+	// MinActiveBucketCount is the number of the active buckets in the window
+	// required to measure the rate, half of the buckets by default;
+	// the lower values catch the rarer transfers (e.g. the slowloris attacks),
+	// but also the connections sending the small messages occasionally
+	MinActiveBucketCount mo.Option[int]
+	ErrorHandler         ErrorHandler
+}
+
+func NewMinThroughputConnectionMiddleware(
+	options MinThroughputOptions,
+) ConnectionMiddleware {
+	return func(handler ConnectionHandler) ConnectionHandler {
+		return ConnectionHandlerFunc(func(
+			ctx context.Context,
+			connection net.Conn,
+		) error {
+			throughputConnection :=
+				newMinThroughputConnection(connection, options)
+			defer throughputConnection.stopMonitoring()
+
+			return handler.HandleConnection(ctx, throughputConnection)
+		})
+	}
+}
+
+type minThroughputConnection struct {
+	net.Conn
+
+	options        MinThroughputOptions
+	readMeter      *throughputMeter
+	writeMeter     *throughputMeter
+	monitoringDone chan struct{}
+	stoppingOnce   sync.Once
+	closingErr     atomic.Pointer[LowThroughputError]
+}
+
+func newMinThroughputConnection(
+	connection net.Conn,
+	options MinThroughputOptions,
+) *minThroughputConnection {
+	bucketCount := max(options.BucketCount.OrElse(DefaultThroughputBucketCount), 1)
+	minActiveBucketCount := min(
+		max(options.MinActiveBucketCount.OrElse((bucketCount+1)/2), 1),
+		bucketCount,
+	)
+	throughputConnection := &minThroughputConnection{
+		Conn: connection,
+
+		options:        options,
+		readMeter:      newThroughputMeter(bucketCount, minActiveBucketCount),
+		writeMeter:     newThroughputMeter(bucketCount, minActiveBucketCount),
+		monitoringDone: make(chan struct{}),
+	}
+
+	bucketDuration :=
+		options.Window.OrElse(DefaultThroughputWindow) / time.Duration(bucketCount)
+	go throughputConnection.monitor(bucketDuration)
+
+	return throughputConnection
+}
+
+func (connection *minThroughputConnection) Read(buffer []byte) (int, error) {
+	n, err := connection.Conn.Read(buffer)
+	connection.readMeter.addTransferredBytes(n)
+
+	return n, connection.wrapError(err)
+}
+
+func (connection *minThroughputConnection) Write(buffer []byte) (int, error) {
+	connection.writeMeter.startPendingTransfer()
+	defer connection.writeMeter.finishPendingTransfer()
+
+	n, err := connection.Conn.Write(buffer)
+	connection.writeMeter.addTransferredBytes(n)
+
+	return n, connection.wrapError(err)
+}
+
+func (connection *minThroughputConnection) wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if closingErr := connection.closingErr.Load(); closingErr != nil {
+		return errors.Join(err, *closingErr)
+	}
+
+	return err
+}
+
+func (connection *minThroughputConnection) stopMonitoring() {
+	connection.stoppingOnce.Do(func() { close(connection.monitoringDone) })
+}
+
+func (connection *minThroughputConnection) monitor(
+	bucketDuration time.Duration,
+) {
+	ticker := time.NewTicker(bucketDuration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-connection.monitoringDone:
+			return
+
+		case <-ticker.C:
+		}
+
+		for _, params := range []struct {
+			direction TransferDirection
+			meter     *throughputMeter
+			minRate   mo.Option[float64]
+		}{
+			{
+				direction: ReadTransferDirection,
+				meter:     connection.readMeter,
+				minRate:   connection.options.MinReadRate,
+			},
+			{
+				direction: WriteTransferDirection,
+				meter:     connection.writeMeter,
+				minRate:   connection.options.MinWriteRate,
+			},
+		} {
+			rate, isMeasured := params.meter.rotate(bucketDuration).Get()
+			minRate, isPresent := params.minRate.Get()
+			if !isMeasured || !isPresent || rate >= minRate {
+				continue
+			}
+
+			closingErr := LowThroughputError{
+				RemoteAddress: connection.RemoteAddr(),
+				Direction:     params.direction,
+				Rate:          rate,
+				MinRate:       minRate,
+			}
+			connection.closingErr.Store(&closingErr)
+
+			if connection.options.ErrorHandler != nil {
+				connection.options.ErrorHandler(closingErr)
+			}
+			connection.Close() //nolint:errcheck
+
+			return
+		}
+	}
+}
+
+type throughputMeter struct {
+	transferredBytes     atomic.Int64
+	pendingTransferCount atomic.Int64
+	hasPendingTransfer   atomic.Bool
+
+	// the fields below are accessed only by the monitoring goroutine
+	minActiveBucketCount int
+	buckets              []throughputBucket
+	bucketIndex          int
+}
+
+type throughputBucket struct {
+	transferredBytes int64
+	isActive         bool
+}
+
+func newThroughputMeter(
+	bucketCount int,
+	minActiveBucketCount int,
+) *throughputMeter {
+	return &throughputMeter{
+		minActiveBucketCount: minActiveBucketCount,
+		buckets:              make([]throughputBucket, bucketCount),
+	}
+}
+
+func (meter *throughputMeter) addTransferredBytes(count int) {
+	meter.transferredBytes.Add(int64(count))
+}
+
+func (meter *throughputMeter) startPendingTransfer() {
+	meter.pendingTransferCount.Add(1)
+	meter.hasPendingTransfer.Store(true)
+}
+
+func (meter *throughputMeter) finishPendingTransfer() {
+	meter.pendingTransferCount.Add(-1)
+}
+
+// rotate closes the current bucket and returns the rate over the active
+// buckets of the window, if there are enough of them
+func (meter *throughputMeter) rotate(
+	bucketDuration time.Duration,
+) mo.Option[float64] {
+	transferredBytes := meter.transferredBytes.Swap(0)
+	hasPendingTransfer := meter.hasPendingTransfer.Swap(false) ||
+		meter.pendingTransferCount.Load() > 0
+
+	meter.buckets[meter.bucketIndex] = throughputBucket{
+		transferredBytes: transferredBytes,
+		isActive:         transferredBytes > 0 || hasPendingTransfer,
+	}
+	meter.bucketIndex = (meter.bucketIndex + 1) % len(meter.buckets)
+
+	var totalTransferredBytes int64
+	var activeBucketCount int
+	for _, bucket := range meter.buckets {
+		if bucket.isActive {
+			totalTransferredBytes += bucket.transferredBytes
+			activeBucketCount++
+		}
+	}
+	if activeBucketCount < meter.minActiveBucketCount {
+		return mo.None[float64]()
+	}
+
+	activeDuration := time.Duration(activeBucketCount) * bucketDuration
+	return mo.Some(float64(totalTransferredBytes) / activeDuration.Seconds())
+}
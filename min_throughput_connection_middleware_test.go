@@ -0,0 +1,224 @@
+package tcpServer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThroughputMeter_rotate(test *testing.T) {
+	type bucketParams struct {
+		transferredBytes   int
+		hasPendingTransfer bool
+	}
+
+	for _, data := range []struct {
+		name                 string
+		minActiveBucketCount int
+		buckets              []bucketParams
+		want                 mo.Option[float64]
+	}{
+		{
+			name:                 "success/idle",
+			minActiveBucketCount: 2,
+			buckets:              []bucketParams{{}, {}, {}, {}},
+			want:                 mo.None[float64](),
+		},
+		{
+			name:                 "success/not enough active buckets",
+			minActiveBucketCount: 2,
+			buckets: []bucketParams{
+				{transferredBytes: 100},
+				{},
+				{},
+				{},
+			},
+			want: mo.None[float64](),
+		},
+		{
+			name:                 "success/with transferred bytes",
+			minActiveBucketCount: 2,
+			buckets: []bucketParams{
+				{transferredBytes: 1},
+				{},
+				{transferredBytes: 2},
+				{transferredBytes: 3},
+			},
+			want: mo.Some(2.0),
+		},
+		{
+			name:                 "success/with a pending transfer",
+			minActiveBucketCount: 2,
+			buckets: []bucketParams{
+				{transferredBytes: 3},
+				{hasPendingTransfer: true},
+				{hasPendingTransfer: true},
+				{},
+			},
+			want: mo.Some(1.0),
+		},
+		{
+			name:                 "success/with the single active bucket",
+			minActiveBucketCount: 1,
+			buckets: []bucketParams{
+				{transferredBytes: 100},
+				{},
+				{},
+				{},
+			},
+			want: mo.Some(100.0),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			meter :=
+				newThroughputMeter(len(data.buckets), data.minActiveBucketCount)
+
+			var got mo.Option[float64]
+			for _, bucket := range data.buckets {
+				meter.addTransferredBytes(bucket.transferredBytes)
+				if bucket.hasPendingTransfer {
+					meter.startPendingTransfer()
+					meter.finishPendingTransfer()
+				}
+
+				got = meter.rotate(time.Second)
+			}
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestNewMinThroughputConnectionMiddleware(test *testing.T) {
+	for _, data := range []struct {
+		name                 string
+		minActiveBucketCount mo.Option[int]
+		writeInput           func(connection net.Conn)
+		wantErr              bool
+	}{
+		{
+			name:                 "success/idle connection",
+			minActiveBucketCount: mo.None[int](),
+			writeInput: func(connection net.Conn) {
+				// the small messages separated by the idle windows
+				// leave a single active bucket in each window
+				for range 3 {
+					time.Sleep(100 * time.Millisecond)
+					connection.Write([]byte("dummy")) //nolint:errcheck
+				}
+
+				time.Sleep(100 * time.Millisecond)
+				connection.Close()
+			},
+			wantErr: false,
+		},
+		{
+			name:                 "error/slowloris connection",
+			minActiveBucketCount: mo.Some(2),
+			writeInput: func(connection net.Conn) {
+				for range 10 {
+					if _, err := connection.Write([]byte("d")); err != nil {
+						return
+					}
+
+					time.Sleep(25 * time.Millisecond)
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name:                 "error/slow connection",
+			minActiveBucketCount: mo.None[int](),
+			writeInput: func(connection net.Conn) {
+				for range 100 {
+					if _, err := connection.Write([]byte("d")); err != nil {
+						return
+					}
+
+					time.Sleep(5 * time.Millisecond)
+				}
+			},
+			wantErr: true,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			serverConnection, clientConnection := net.Pipe()
+			defer serverConnection.Close()
+			defer clientConnection.Close()
+
+			go data.writeInput(clientConnection)
+
+			var reportedErrs []error
+			middleware := NewMinThroughputConnectionMiddleware(MinThroughputOptions{
+				MinReadRate:          mo.Some(1000.0),
+				Window:               mo.Some(50 * time.Millisecond),
+				BucketCount:          mo.Some(5),
+				MinActiveBucketCount: data.minActiveBucketCount,
+				ErrorHandler: func(err error) {
+					reportedErrs = append(reportedErrs, err)
+				},
+			})
+			handler := middleware(ConnectionHandlerFunc(func(
+				ctx context.Context,
+				connection net.Conn,
+			) error {
+				_, err := io.Copy(io.Discard, connection)
+				return err
+			}))
+			err := handler.HandleConnection(context.Background(), serverConnection)
+
+			if data.wantErr {
+				var lowThroughputErr LowThroughputError
+				require.True(test, errors.As(err, &lowThroughputErr))
+				assert.Equal(test, ReadTransferDirection, lowThroughputErr.Direction)
+				assert.Less(test, lowThroughputErr.Rate, 1000.0)
+				assert.Equal(test, []error{lowThroughputErr}, reportedErrs)
+			} else {
+				assert.NoError(test, err)
+				assert.Empty(test, reportedErrs)
+			}
+		})
+	}
+}
+
+func TestNewMinThroughputConnectionMiddleware_withoutErrorHandler(
+	test *testing.T,
+) {
+	serverConnection, clientConnection := net.Pipe()
+	defer serverConnection.Close()
+	defer clientConnection.Close()
+
+	go func() {
+		for range 100 {
+			if _, err := clientConnection.Write([]byte("d")); err != nil {
+				return
+			}
+
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+
+	middleware := NewMinThroughputConnectionMiddleware(MinThroughputOptions{
+		MinReadRate: mo.Some(1000.0),
+		Window:      mo.Some(50 * time.Millisecond),
+		BucketCount: mo.Some(5),
+	})
+	handler := middleware(ConnectionHandlerFunc(func(
+		ctx context.Context,
+		connection net.Conn,
+	) error {
+		_, err := io.Copy(io.Discard, connection)
+		return err
+	}))
+	err := handler.HandleConnection(context.Background(), serverConnection)
+
+	var lowThroughputErr LowThroughputError
+	assert.True(test, errors.As(err, &lowThroughputErr))
+}
@@ -0,0 +1,178 @@
+package tcpServer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/samber/mo"
+)
+
+const (
+	DefaultMaxSniffingSize = 512
+)
+
+var (
+	ErrUnknownProtocol = errors.New("unknown protocol")
+)
+
+type ProtocolMatchingResult string
+
+const (
+	MatchedProtocol    ProtocolMatchingResult = "matched"
+	MismatchedProtocol ProtocolMatchingResult = "mismatched"
+	// UndecidedProtocol requires more data to decide
+	UndecidedProtocol ProtocolMatchingResult = "undecided"
+)
+
+// ProtocolMatcher is called with the first bytes of the connection;
+// it's called again with more bytes, while it returns `UndecidedProtocol`
+type ProtocolMatcher func(data []byte) ProtocolMatchingResult
+
+func NewMagicBytesMatcher(magicBytes []byte) ProtocolMatcher {
+	return func(data []byte) ProtocolMatchingResult {
+		if len(data) >= len(magicBytes) {
+			if bytes.HasPrefix(data, magicBytes) {
+				return MatchedProtocol
+			}
+
+			return MismatchedProtocol
+		}
+
+		if bytes.HasPrefix(magicBytes, data) {
+			return UndecidedProtocol
+		}
+
+		return MismatchedProtocol
+	}
+}
+
+type ProtocolRoute struct {
+	Matcher ProtocolMatcher
+	// Handler is usually the `DefaultConnectionHandler`
+	// with the corresponding server protocol
+	Handler ConnectionHandler
+}
+
+type ProtocolSniffingOptions struct {
+	// MaxSniffingSize limits the number of the first bytes
+	// available to the matchers; if it's reached or the connection
+	// is closed by the peer, the undecided matchers are considered mismatched
+	MaxSniffingSize mo.Option[int]
+	SniffingTimeout mo.Option[time.Duration]
+	// Routes are checked in order, so an undecided route takes precedence
+	// over the matched routes after it
+	Routes []ProtocolRoute
+	// FallbackHandler handles the connections not matched by any route;
+	// without it, they are rejected with `ErrUnknownProtocol`
+	FallbackHandler mo.Option[ConnectionHandler]
+}
+
+type ProtocolSniffingConnectionHandler struct {
+	options ProtocolSniffingOptions
+}
+
+func NewProtocolSniffingConnectionHandler(
+	options ProtocolSniffingOptions,
+) ProtocolSniffingConnectionHandler {
+	return ProtocolSniffingConnectionHandler{
+		options: options,
+	}
+}
+
+func (handler ProtocolSniffingConnectionHandler) HandleConnection(
+	ctx context.Context,
+	connection net.Conn,
+) error {
+	reader := bufio.NewReaderSize(
+		connection,
+		handler.options.MaxSniffingSize.OrElse(DefaultMaxSniffingSize),
+	)
+
+	routeHandler, err := handler.sniffProtocol(connection, reader)
+	if err != nil {
+		return err
+	}
+
+	// the peeked bytes remain in the reader, so they aren't lost
+	return routeHandler.HandleConnection(
+		ctx,
+		bufferedConnection{Conn: connection, reader: reader},
+	)
+}
+
+func (handler ProtocolSniffingConnectionHandler) sniffProtocol(
+	connection net.Conn,
+	reader *bufio.Reader,
+) (ConnectionHandler, error) {
+	if sniffingTimeout, isPresent :=
+		handler.options.SniffingTimeout.Get(); isPresent {
+		sniffingDeadline := time.Now().Add(sniffingTimeout)
+		if err := connection.SetReadDeadline(sniffingDeadline); err != nil {
+			return nil, fmt.Errorf("unable to set the read deadline: %w", err)
+		}
+
+		defer connection.SetReadDeadline(time.Time{}) //nolint:errcheck
+	}
+
+	maxSniffingSize := reader.Size()
+	for peekingSize := 1; ; peekingSize = reader.Buffered() + 1 {
+		_, peekingErr := reader.Peek(peekingSize)
+		if peekingErr != nil &&
+			(!errors.Is(peekingErr, io.EOF) || reader.Buffered() == 0) {
+			return nil, fmt.Errorf("unable to peek the first bytes: %w", peekingErr)
+		}
+
+		// peek all the buffered bytes, it doesn't block
+		data, _ := reader.Peek(reader.Buffered())
+		isLatestData := peekingErr != nil || len(data) >= maxSniffingSize
+		routeHandler, isDecided := handler.matchRoute(data, isLatestData)
+		if !isDecided {
+			continue
+		}
+
+		if routeHandler, isPresent := routeHandler.Get(); isPresent {
+			return routeHandler, nil
+		}
+
+		fallbackHandler, isPresent := handler.options.FallbackHandler.Get()
+		if !isPresent {
+			return nil, ErrUnknownProtocol
+		}
+
+		return fallbackHandler, nil
+	}
+}
+
+func (handler ProtocolSniffingConnectionHandler) matchRoute(
+	data []byte,
+	isLatestData bool,
+) (routeHandler mo.Option[ConnectionHandler], isDecided bool) {
+	for _, route := range handler.options.Routes {
+		switch route.Matcher(data) {
+		case MatchedProtocol:
+			return mo.Some(route.Handler), true
+		case UndecidedProtocol:
+			if !isLatestData {
+				return mo.None[ConnectionHandler](), false
+			}
+		}
+	}
+
+	return mo.None[ConnectionHandler](), true
+}
+
+type bufferedConnection struct {
+	net.Conn
+
+	reader *bufio.Reader
+}
+
+func (connection bufferedConnection) Read(buffer []byte) (int, error) {
+	return connection.reader.Read(buffer)
+}
@@ -0,0 +1,359 @@
+package tcpServer_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestNewMagicBytesMatcher(test *testing.T) {
+	for _, data := range []struct {
+		name string
+		data []byte
+		want tcpServer.ProtocolMatchingResult
+	}{
+		{
+			name: "matched/exact",
+			data: []byte("BIN:"),
+			want: tcpServer.MatchedProtocol,
+		},
+		{
+			name: "matched/with the rest",
+			data: []byte("BIN:rest"),
+			want: tcpServer.MatchedProtocol,
+		},
+		{
+			name: "mismatched/short",
+			data: []byte("BX"),
+			want: tcpServer.MismatchedProtocol,
+		},
+		{
+			name: "mismatched/long",
+			data: []byte("BIN!rest"),
+			want: tcpServer.MismatchedProtocol,
+		},
+		{
+			name: "undecided",
+			data: []byte("BI"),
+			want: tcpServer.UndecidedProtocol,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := tcpServer.NewMagicBytesMatcher([]byte("BIN:"))(data.data)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestProtocolSniffingConnectionHandler_interface(test *testing.T) {
+	assert.Implements(
+		test,
+		(*tcpServer.ConnectionHandler)(nil),
+		tcpServer.ProtocolSniffingConnectionHandler{},
+	)
+}
+
+func TestProtocolSniffingConnectionHandler_HandleConnection(
+	test *testing.T,
+) {
+	type args struct {
+		data          string
+		isDataWritten bool
+	}
+
+	undecidedMatcher := func(data []byte) tcpServer.ProtocolMatchingResult {
+		return tcpServer.UndecidedProtocol
+	}
+
+	for _, data := range []struct {
+		name    string
+		options func(handledBy chan string) tcpServer.ProtocolSniffingOptions
+		args    args
+		want    string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/matched by the magic bytes",
+			options: func(
+				handledBy chan string,
+			) tcpServer.ProtocolSniffingOptions {
+				return tcpServer.ProtocolSniffingOptions{
+					Routes: []tcpServer.ProtocolRoute{
+						newTestProtocolRoute(handledBy, "binary", "BIN:"),
+						newTestProtocolRoute(handledBy, "text", "TXT:"),
+					},
+				}
+			},
+			args: args{
+				data:          "TXT:request",
+				isDataWritten: true,
+			},
+			want:    "text:TXT:request",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/matched after the undecided route",
+			options: func(
+				handledBy chan string,
+			) tcpServer.ProtocolSniffingOptions {
+				return tcpServer.ProtocolSniffingOptions{
+					Routes: []tcpServer.ProtocolRoute{
+						newTestProtocolRoute(handledBy, "binary", "BINARY:"),
+						newTestProtocolRoute(handledBy, "text", "BIN"),
+					},
+				}
+			},
+			args: args{
+				data:          "BINXRY:request",
+				isDataWritten: true,
+			},
+			want:    "text:BINXRY:request",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/fallback",
+			options: func(
+				handledBy chan string,
+			) tcpServer.ProtocolSniffingOptions {
+				return tcpServer.ProtocolSniffingOptions{
+					Routes: []tcpServer.ProtocolRoute{
+						newTestProtocolRoute(handledBy, "binary", "BIN:"),
+					},
+					FallbackHandler: mo.Some(
+						newTestProtocolRoute(handledBy, "fallback", "").Handler,
+					),
+				}
+			},
+			args: args{
+				data:          "request",
+				isDataWritten: true,
+			},
+			want:    "fallback:request",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/fallback on the closed connection",
+			options: func(
+				handledBy chan string,
+			) tcpServer.ProtocolSniffingOptions {
+				return tcpServer.ProtocolSniffingOptions{
+					Routes: []tcpServer.ProtocolRoute{
+						newTestProtocolRoute(handledBy, "binary", "BIN:"),
+					},
+					FallbackHandler: mo.Some(
+						newTestProtocolRoute(handledBy, "fallback", "").Handler,
+					),
+				}
+			},
+			args: args{
+				data:          "BI",
+				isDataWritten: true,
+			},
+			want:    "fallback:BI",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/fallback on the max sniffing size",
+			options: func(
+				handledBy chan string,
+			) tcpServer.ProtocolSniffingOptions {
+				route := newTestProtocolRoute(handledBy, "binary", "")
+				route.Matcher = undecidedMatcher
+
+				return tcpServer.ProtocolSniffingOptions{
+					MaxSniffingSize: mo.Some(16),
+					Routes:          []tcpServer.ProtocolRoute{route},
+					FallbackHandler: mo.Some(
+						newTestProtocolRoute(handledBy, "fallback", "").Handler,
+					),
+				}
+			},
+			args: args{
+				data:          "request longer than the max sniffing size",
+				isDataWritten: true,
+			},
+			want:    "fallback:request longer than the max sniffing size",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/unknown protocol",
+			options: func(
+				handledBy chan string,
+			) tcpServer.ProtocolSniffingOptions {
+				return tcpServer.ProtocolSniffingOptions{
+					Routes: []tcpServer.ProtocolRoute{
+						newTestProtocolRoute(handledBy, "binary", "BIN:"),
+					},
+				}
+			},
+			args: args{
+				data:          "request",
+				isDataWritten: true,
+			},
+			want: "",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, tcpServer.ErrUnknownProtocol)
+			},
+		},
+		{
+			name: "error/sniffing timeout",
+			options: func(
+				handledBy chan string,
+			) tcpServer.ProtocolSniffingOptions {
+				return tcpServer.ProtocolSniffingOptions{
+					SniffingTimeout: mo.Some(10 * time.Millisecond),
+					Routes: []tcpServer.ProtocolRoute{
+						newTestProtocolRoute(handledBy, "binary", "BIN:"),
+					},
+				}
+			},
+			args: args{
+				data:          "",
+				isDataWritten: false,
+			},
+			want:    "",
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			serverConnection, clientConnection := net.Pipe()
+			defer serverConnection.Close()
+
+			if data.args.isDataWritten {
+				go func() {
+					defer clientConnection.Close()
+
+					clientConnection.Write([]byte(data.args.data)) //nolint:errcheck
+				}()
+			} else {
+				defer clientConnection.Close()
+			}
+
+			handledBy := make(chan string, 1)
+			err := tcpServer.NewProtocolSniffingConnectionHandler(
+				data.options(handledBy),
+			).
+				HandleConnection(context.Background(), serverConnection)
+
+			var got string
+			if err == nil {
+				got = <-handledBy
+			}
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestProtocolSniffingConnectionHandler_withDefaultConnectionHandlers(
+	test *testing.T,
+) {
+	newHandler := func(prefix string) tcpServer.ConnectionHandler {
+		return tcpServer.NewDefaultConnectionHandler(
+			tcpServer.DefaultConnectionHandlerOptions[string, string]{
+				ServerProtocol: testLineProtocol{},
+				RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+					ctx context.Context,
+					request string,
+				) (string, error) {
+					return prefix + request, nil
+				}),
+			},
+		)
+	}
+
+	for _, data := range []struct {
+		name      string
+		requests  []string
+		wantReply []string
+	}{
+		{
+			name:      "binary",
+			requests:  []string{"BIN:one", "two"},
+			wantReply: []string{"binary:BIN:one", "binary:two"},
+		},
+		{
+			name:      "text",
+			requests:  []string{"one", "BIN:two"},
+			wantReply: []string{"text:one", "text:BIN:two"},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			serverConnection, clientConnection := net.Pipe()
+
+			handler := tcpServer.NewProtocolSniffingConnectionHandler(
+				tcpServer.ProtocolSniffingOptions{
+					Routes: []tcpServer.ProtocolRoute{
+						{
+							Matcher: tcpServer.NewMagicBytesMatcher([]byte("BIN:")),
+							Handler: newHandler("binary:"),
+						},
+					},
+					FallbackHandler: mo.Some(newHandler("text:")),
+				},
+			)
+
+			handlingDone := make(chan struct{})
+			go func() {
+				defer close(handlingDone)
+				defer serverConnection.Close()
+
+				handler.HandleConnection( //nolint:errcheck
+					context.Background(),
+					serverConnection,
+				)
+			}()
+
+			client := tcpServer.NewTCPClientFromConnection(
+				clientConnection,
+				tcpServer.TCPClientOptions[string, string]{
+					ClientProtocol: testLineProtocol{},
+				},
+			)
+
+			var gotReply []string
+			for _, request := range data.requests {
+				response, err := client.SendRequest(request)
+				require.NoError(test, err)
+
+				gotReply = append(gotReply, response)
+			}
+
+			require.NoError(test, client.Close())
+			<-handlingDone
+
+			assert.Equal(test, data.wantReply, gotReply)
+		})
+	}
+}
+
+func newTestProtocolRoute(
+	handledBy chan string,
+	name string,
+	magicBytes string,
+) tcpServer.ProtocolRoute {
+	return tcpServer.ProtocolRoute{
+		Matcher: tcpServer.NewMagicBytesMatcher([]byte(magicBytes)),
+		Handler: tcpServer.ConnectionHandlerFunc(func(
+			ctx context.Context,
+			connection net.Conn,
+		) error {
+			data, err := io.ReadAll(connection)
+			if err != nil {
+				return err
+			}
+
+			handledBy <- name + ":" + string(data)
+			return nil
+		}),
+	}
+}
@@ -0,0 +1,147 @@
+package cborProtocol
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// only the subset of CBOR used by the message format is supported:
+// maps, text strings and byte strings of definite lengths
+
+const (
+	byteStringMajorType = 2
+	textStringMajorType = 3
+	mapMajorType        = 5
+
+	majorTypeShift      = 5
+	additionalInfoMask  = 0x1f
+	maxDirectLength     = 23
+	uint8LengthFollows  = 24
+	uint16LengthFollows = 25
+	uint32LengthFollows = 26
+	uint64LengthFollows = 27
+)
+
+func AppendMapHeader(buffer []byte, length int) []byte {
+	return appendHead(buffer, mapMajorType, uint64(length))
+}
+
+func AppendTextString(buffer []byte, text string) []byte {
+	buffer = appendHead(buffer, textStringMajorType, uint64(len(text)))
+	return append(buffer, text...)
+}
+
+func AppendByteString(buffer []byte, data []byte) []byte {
+	buffer = appendHead(buffer, byteStringMajorType, uint64(len(data)))
+	return append(buffer, data...)
+}
+
+type Decoder struct {
+	data []byte
+}
+
+func NewDecoder(data []byte) *Decoder {
+	return &Decoder{
+		data: data,
+	}
+}
+
+func (decoder *Decoder) IsEmpty() bool {
+	return len(decoder.data) == 0
+}
+
+func (decoder *Decoder) ReadMapHeader() (int, error) {
+	majorType, length, err := decoder.readHead()
+	if err != nil {
+		return 0, err
+	}
+	if majorType != mapMajorType {
+		return 0, fmt.Errorf("expected a map, got the major type %d", majorType)
+	}
+
+	return length, nil
+}
+
+// ReadBytes reads either a text string or a byte string
+func (decoder *Decoder) ReadBytes() ([]byte, error) {
+	majorType, length, err := decoder.readHead()
+	if err != nil {
+		return nil, err
+	}
+	if majorType != byteStringMajorType && majorType != textStringMajorType {
+		return nil, fmt.Errorf(
+			"expected a string, got the major type %d",
+			majorType,
+		)
+	}
+
+	return decoder.readData(length)
+}
+
+func (decoder *Decoder) readHead() (majorType byte, length int, err error) {
+	head, err := decoder.readData(1)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	majorType = head[0] >> majorTypeShift
+	additionalInfo := head[0] & additionalInfoMask
+
+	var rawLength uint64
+	switch {
+	case additionalInfo <= maxDirectLength:
+		rawLength = uint64(additionalInfo)
+	case additionalInfo <= uint64LengthFollows:
+		size := 1 << (additionalInfo - uint8LengthFollows)
+		data, err := decoder.readData(size)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		for _, dataByte := range data {
+			rawLength = rawLength<<8 | uint64(dataByte)
+		}
+	default:
+		return 0, 0, fmt.Errorf(
+			"unsupported additional information %d",
+			additionalInfo,
+		)
+	}
+	if rawLength > uint64(len(decoder.data)) {
+		return 0, 0, fmt.Errorf("length %d exceeds the remaining data", rawLength)
+	}
+
+	return majorType, int(rawLength), nil
+}
+
+func (decoder *Decoder) readData(size int) ([]byte, error) {
+	if len(decoder.data) < size {
+		return nil, errors.New("data is incomplete")
+	}
+
+	data := decoder.data[:size]
+	decoder.data = decoder.data[size:]
+
+	return data, nil
+}
+
+func appendHead(buffer []byte, majorType byte, length uint64) []byte {
+	head := majorType << majorTypeShift
+	switch {
+	case length <= maxDirectLength:
+		return append(buffer, head|byte(length))
+	case length <= math.MaxUint8:
+		return append(buffer, head|uint8LengthFollows, byte(length))
+	case length <= math.MaxUint16:
+		buffer = append(buffer, head|uint16LengthFollows)
+		return binary.BigEndian.AppendUint16(buffer, uint16(length))
+	case length <= math.MaxUint32:
+		buffer = append(buffer, head|uint32LengthFollows)
+		return binary.BigEndian.AppendUint32(buffer, uint32(length))
+	default:
+		buffer = append(buffer, head|uint64LengthFollows)
+		return binary.BigEndian.AppendUint64(buffer, length)
+	}
+}
@@ -0,0 +1,138 @@
+package cborProtocol
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendMapHeader(test *testing.T) {
+	for _, data := range []struct {
+		name   string
+		length int
+		want   []byte
+	}{
+		{
+			name:   "success/direct length",
+			length: 3,
+			want:   []byte{0xa3},
+		},
+		{
+			name:   "success/1-byte length",
+			length: 24,
+			want:   []byte{0xb8, 0x18},
+		},
+		{
+			name:   "success/2-byte length",
+			length: 0x100,
+			want:   []byte{0xb9, 0x01, 0x00},
+		},
+		{
+			name:   "success/4-byte length",
+			length: 0x10000,
+			want:   []byte{0xba, 0x00, 0x01, 0x00, 0x00},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := AppendMapHeader(nil, data.length)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestAppendTextString(test *testing.T) {
+	got := AppendTextString(nil, "IETF")
+
+	assert.Equal(test, []byte{0x64, 0x49, 0x45, 0x54, 0x46}, got)
+}
+
+func TestAppendByteString(test *testing.T) {
+	got := AppendByteString(nil, []byte{0x01, 0x02, 0x03, 0x04})
+
+	assert.Equal(test, []byte{0x44, 0x01, 0x02, 0x03, 0x04}, got)
+}
+
+func TestDecoder(test *testing.T) {
+	data := AppendMapHeader(nil, 2)
+	data = AppendTextString(data, "one")
+	data = AppendByteString(data, []byte(strings.Repeat("d", 300)))
+	data = AppendTextString(data, strings.Repeat("t", 40))
+	data = AppendByteString(data, nil)
+
+	decoder := NewDecoder(data)
+
+	length, err := decoder.ReadMapHeader()
+	require.NoError(test, err)
+	assert.Equal(test, 2, length)
+
+	for _, want := range [][]byte{
+		[]byte("one"),
+		[]byte(strings.Repeat("d", 300)),
+		[]byte(strings.Repeat("t", 40)),
+		{},
+	} {
+		got, err := decoder.ReadBytes()
+		require.NoError(test, err)
+		assert.Equal(test, want, got)
+	}
+
+	assert.True(test, decoder.IsEmpty())
+}
+
+func TestDecoder_errors(test *testing.T) {
+	for _, data := range []struct {
+		name string
+		data []byte
+		read func(decoder *Decoder) error
+	}{
+		{
+			name: "error/not a map",
+			data: []byte{0x61, 'a'},
+			read: func(decoder *Decoder) error {
+				_, err := decoder.ReadMapHeader()
+				return err
+			},
+		},
+		{
+			name: "error/not a string",
+			data: []byte{0x01},
+			read: func(decoder *Decoder) error {
+				_, err := decoder.ReadBytes()
+				return err
+			},
+		},
+		{
+			name: "error/indefinite length",
+			data: []byte{0x5f, 0x41, 'a', 0xff},
+			read: func(decoder *Decoder) error {
+				_, err := decoder.ReadBytes()
+				return err
+			},
+		},
+		{
+			name: "error/incomplete length",
+			data: []byte{0x59, 0x01},
+			read: func(decoder *Decoder) error {
+				_, err := decoder.ReadBytes()
+				return err
+			},
+		},
+		{
+			name: "error/incomplete data",
+			data: []byte{0x45, 'd'},
+			read: func(decoder *Decoder) error {
+				_, err := decoder.ReadBytes()
+				return err
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			err := data.read(NewDecoder(data.data))
+
+			assert.Error(test, err)
+		})
+	}
+}
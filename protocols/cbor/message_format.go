@@ -0,0 +1,51 @@
+package cborProtocol
+
+import (
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	mapBasedProtocol "github.com/thewizardplusplus/go-tcp-server/protocols/map-based"
+)
+
+const (
+	IntroductionKey = mapBasedProtocol.IntroductionKey
+	HeadersKey      = mapBasedProtocol.HeadersKey
+	BodyKey         = mapBasedProtocol.BodyKey
+)
+
+// MessageFormat represents the message as a map with string keys;
+// the introduction and the body are byte strings and the headers are a map
+// with byte string keys and values; the headers and the body may be omitted
+type MessageFormat struct{}
+
+func NewMessageFormat() MessageFormat {
+	return MessageFormat{}
+}
+
+func (format MessageFormat) ParseMessage(
+	data []byte,
+) (defaultProtocolModels.Message, error) {
+	return mapBasedProtocol.NewMessageFormat(encoding{}).ParseMessage(data)
+}
+
+func (format MessageFormat) MarshalMessage(
+	message defaultProtocolModels.Message,
+) ([]byte, error) {
+	return mapBasedProtocol.NewMessageFormat(encoding{}).MarshalMessage(message)
+}
+
+type encoding struct{}
+
+func (encoding) AppendMapHeader(buffer []byte, length int) []byte {
+	return AppendMapHeader(buffer, length)
+}
+
+func (encoding) AppendString(buffer []byte, text string) []byte {
+	return AppendTextString(buffer, text)
+}
+
+func (encoding) AppendBinary(buffer []byte, data []byte) []byte {
+	return AppendByteString(buffer, data)
+}
+
+func (encoding) NewDecoder(data []byte) mapBasedProtocol.Decoder {
+	return NewDecoder(data)
+}
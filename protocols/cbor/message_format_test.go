@@ -0,0 +1,114 @@
+package cborProtocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	defaultProtocol "github.com/thewizardplusplus/go-tcp-server/protocols/default"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+func TestMessageFormat_interface(test *testing.T) {
+	assert.Implements(test, (*defaultProtocol.MessageFormat)(nil), MessageFormat{})
+}
+
+func TestMessageFormat_MarshalMessage(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		message func(test *testing.T) defaultProtocolModels.Message
+		want    []byte
+	}{
+		{
+			name: "success/minimal message",
+			message: func(test *testing.T) defaultProtocolModels.Message {
+				introduction, err :=
+					defaultProtocolModelValueTypes.NewIntroduction([]byte("dummy"))
+				require.NoError(test, err)
+
+				message, err := defaultProtocolModels.NewMessageBuilder().
+					SetIntroduction(introduction).
+					Build()
+				require.NoError(test, err)
+
+				return message
+			},
+			want: []byte("\xa1\x6cintroduction\x45dummy"),
+		},
+		{
+			name: "success/full message",
+			message: func(test *testing.T) defaultProtocolModels.Message {
+				introduction, err :=
+					defaultProtocolModelValueTypes.NewIntroduction([]byte("dummy"))
+				require.NoError(test, err)
+
+				message, err := defaultProtocolModels.NewMessageBuilder().
+					SetIntroduction(introduction).
+					SetHeaders(defaultProtocolModelValueTypes.NewHeaders(
+						map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue{ //nolint:lll
+							defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("two")): defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("2")), //nolint:lll
+							defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("one")): defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("1")), //nolint:lll
+						},
+					)).
+					SetBody(defaultProtocolModelValueTypes.NewBody([]byte("b\x00dy"))).
+					Build()
+				require.NoError(test, err)
+
+				return message
+			},
+			want: []byte(
+				"\xa3" +
+					"\x6cintroduction\x45dummy" +
+					"\x67headers\xa2\x43one\x411\x43two\x412" +
+					"\x64body\x44b\x00dy",
+			),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			message := data.message(test)
+
+			got, err := MessageFormat{}.MarshalMessage(message)
+			require.NoError(test, err)
+			assert.Equal(test, data.want, got)
+
+			gotMessage, err := MessageFormat{}.ParseMessage(got)
+			require.NoError(test, err)
+			assert.Equal(test, message, gotMessage)
+		})
+	}
+}
+
+func TestMessageFormat_ParseMessage_errors(test *testing.T) {
+	for _, data := range []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "error/not a map",
+			data: []byte("\x65dummy"),
+		},
+		{
+			name: "error/unknown field",
+			data: []byte("\xa1\x65dummy\x45dummy"),
+		},
+		{
+			name: "error/missed introduction",
+			data: []byte("\xa1\x64body\x44body"),
+		},
+		{
+			name: "error/empty header key",
+			data: []byte("\xa2\x6cintroduction\x45dummy\x67headers\xa1\x40\x411"),
+		},
+		{
+			name: "error/extra data",
+			data: []byte("\xa1\x6cintroduction\x45dummy\xf6"),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			_, err := MessageFormat{}.ParseMessage(data.data)
+
+			assert.Error(test, err)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package checksumProtocol
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+
+	"github.com/samber/mo"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+var (
+	crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+)
+
+func NewCRC32CHash() hash.Hash {
+	return crc32.New(crc32cTable)
+}
+
+type ChecksumMismatchError struct {
+	ExpectedChecksum []byte
+	ActualChecksum   []byte
+}
+
+func (err ChecksumMismatchError) Error() string {
+	return fmt.Sprintf(
+		"checksum mismatch: expected %x, actual %x",
+		err.ExpectedChecksum,
+		err.ActualChecksum,
+	)
+}
+
+type ProtocolOptions struct {
+	// NewHash constructs the hash for the checksums (e.g. `xxhash.New`);
+	// CRC32C is used by default
+	NewHash mo.Option[func() hash.Hash]
+}
+
+// BaseProtocol appends the checksum of each marshalled message after it
+// and verifies and strips the checksum on the token extraction;
+// the wrapped protocol should extract the whole message
+// without looking ahead of it; its final token (see `bufio.ErrFinalToken`)
+// takes the rest of the data, so the checksum is verified at its end
+type BaseProtocol[Req tcpServer.Request, Resp tcpServer.Response] struct {
+	protocol     tcpServer.BaseProtocol[Req, Resp]
+	newHash      func() hash.Hash
+	checksumSize int
+}
+
+func NewBaseProtocol[Req tcpServer.Request, Resp tcpServer.Response](
+	protocol tcpServer.BaseProtocol[Req, Resp],
+	options ProtocolOptions,
+) BaseProtocol[Req, Resp] {
+	newHash := options.NewHash.OrElse(NewCRC32CHash)
+	return BaseProtocol[Req, Resp]{
+		protocol:     protocol,
+		newHash:      newHash,
+		checksumSize: newHash().Size(),
+	}
+}
+
+func (protocol BaseProtocol[Req, Resp]) InitialScannerBufferSize() int {
+	return protocol.protocol.InitialScannerBufferSize()
+}
+
+func (protocol BaseProtocol[Req, Resp]) MaxTokenSize() int {
+	return protocol.protocol.MaxTokenSize() + protocol.checksumSize
+}
+
+func (protocol BaseProtocol[Req, Resp]) ExtractToken(
+	data []byte,
+	isLatestData bool,
+) (offsetToNextToken int, token []byte, err error) {
+	offsetToNextToken, token, err =
+		protocol.protocol.ExtractToken(data, isLatestData)
+	if errors.Is(err, bufio.ErrFinalToken) && token != nil {
+		// the final token takes the rest of the data,
+		// so it ends with the checksum
+		message, verificationErr := protocol.verifyFinalToken(token)
+		if verificationErr != nil {
+			return 0, nil, verificationErr
+		}
+
+		return offsetToNextToken, message, err
+	}
+	if err != nil || token == nil {
+		return offsetToNextToken, token, err
+	}
+
+	messageSize := offsetToNextToken + protocol.checksumSize
+	if len(data) < messageSize {
+		if !isLatestData {
+			return 0, nil, nil // request more data
+		}
+
+		return 0, nil, fmt.Errorf(
+			"unable to extract the checksum: %w",
+			io.ErrUnexpectedEOF,
+		)
+	}
+
+	if err := protocol.verifyChecksum(
+		data[:offsetToNextToken],
+		data[offsetToNextToken:messageSize],
+	); err != nil {
+		return 0, nil, err
+	}
+
+	return messageSize, token, nil
+}
+
+func (protocol BaseProtocol[Req, Resp]) verifyFinalToken(
+	token []byte,
+) ([]byte, error) {
+	messageSize := len(token) - protocol.checksumSize
+	if messageSize < 0 {
+		return nil, fmt.Errorf(
+			"unable to extract the checksum: %w",
+			io.ErrUnexpectedEOF,
+		)
+	}
+
+	if err := protocol.verifyChecksum(
+		token[:messageSize],
+		token[messageSize:],
+	); err != nil {
+		return nil, err
+	}
+
+	return token[:messageSize], nil
+}
+
+func (protocol BaseProtocol[Req, Resp]) verifyChecksum(
+	message []byte,
+	expectedChecksum []byte,
+) error {
+	actualChecksum := protocol.checksum(message)
+	if !bytes.Equal(expectedChecksum, actualChecksum) {
+		return ChecksumMismatchError{
+			ExpectedChecksum: bytes.Clone(expectedChecksum),
+			ActualChecksum:   actualChecksum,
+		}
+	}
+
+	return nil
+}
+
+func (protocol BaseProtocol[Req, Resp]) appendChecksum(data []byte) []byte {
+	return append(data, protocol.checksum(data)...)
+}
+
+func (protocol BaseProtocol[Req, Resp]) checksum(data []byte) []byte {
+	hash := protocol.newHash()
+	hash.Write(data) //nolint:errcheck
+
+	return hash.Sum(nil)
+}
+
+type ServerProtocol[Req tcpServer.Request, Resp tcpServer.Response] struct {
+	BaseProtocol[Req, Resp]
+
+	protocol tcpServer.ServerProtocol[Req, Resp]
+}
+
+func NewServerProtocol[Req tcpServer.Request, Resp tcpServer.Response](
+	protocol tcpServer.ServerProtocol[Req, Resp],
+	options ProtocolOptions,
+) ServerProtocol[Req, Resp] {
+	return ServerProtocol[Req, Resp]{
+		BaseProtocol: NewBaseProtocol[Req, Resp](protocol, options),
+		protocol:     protocol,
+	}
+}
+
+func (protocol ServerProtocol[Req, Resp]) ParseRequest(
+	token []byte,
+) (Req, error) {
+	return protocol.protocol.ParseRequest(token)
+}
+
+func (protocol ServerProtocol[Req, Resp]) MarshalResponse(
+	response Resp,
+) ([]byte, error) {
+	data, err := protocol.protocol.MarshalResponse(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return protocol.appendChecksum(data), nil
+}
+
+type ClientProtocol[Req tcpServer.Request, Resp tcpServer.Response] struct {
+	BaseProtocol[Req, Resp]
+
+	protocol tcpServer.ClientProtocol[Req, Resp]
+}
+
+func NewClientProtocol[Req tcpServer.Request, Resp tcpServer.Response](
+	protocol tcpServer.ClientProtocol[Req, Resp],
+	options ProtocolOptions,
+) ClientProtocol[Req, Resp] {
+	return ClientProtocol[Req, Resp]{
+		BaseProtocol: NewBaseProtocol[Req, Resp](protocol, options),
+		protocol:     protocol,
+	}
+}
+
+func (protocol ClientProtocol[Req, Resp]) MarshalRequest(
+	request Req,
+) ([]byte, error) {
+	data, err := protocol.protocol.MarshalRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return protocol.appendChecksum(data), nil
+}
+
+func (protocol ClientProtocol[Req, Resp]) ParseResponse(
+	data []byte,
+) (Resp, error) {
+	return protocol.protocol.ParseResponse(data)
+}
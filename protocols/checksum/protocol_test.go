@@ -0,0 +1,328 @@
+package checksumProtocol
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"hash/crc64"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+	lengthPrefixedProtocol "github.com/thewizardplusplus/go-tcp-server/protocols/length-prefixed"
+	separatorBasedProtocol "github.com/thewizardplusplus/go-tcp-server/protocols/separator-based"
+)
+
+func TestProtocol_interface(test *testing.T) {
+	assert.Implements(
+		test,
+		(*tcpServer.ServerProtocol[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		])(nil),
+		ServerProtocol[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		]{},
+	)
+	assert.Implements(
+		test,
+		(*tcpServer.ClientProtocol[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		])(nil),
+		ClientProtocol[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		]{},
+	)
+}
+
+func TestBaseProtocol_ExtractToken(test *testing.T) {
+	crc64Table := crc64.MakeTable(crc64.ECMA)
+
+	type args struct {
+		options ProtocolOptions
+		data    func(options ProtocolOptions) []byte
+	}
+
+	for _, data := range []struct {
+		name       string
+		args       args
+		wantTokens [][]byte
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/several tokens",
+			args: args{
+				options: ProtocolOptions{},
+				data: func(options ProtocolOptions) []byte {
+					return newTestData(options, "one", "", "three")
+				},
+			},
+			wantTokens: [][]byte{[]byte("one"), {}, []byte("three")},
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "success/custom hash",
+			args: args{
+				options: ProtocolOptions{
+					NewHash: mo.Some(func() hash.Hash { return crc64.New(crc64Table) }),
+				},
+				data: func(options ProtocolOptions) []byte {
+					return newTestData(options, "one", "two")
+				},
+			},
+			wantTokens: [][]byte{[]byte("one"), []byte("two")},
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "success/empty data",
+			args: args{
+				options: ProtocolOptions{},
+				data: func(options ProtocolOptions) []byte {
+					return nil
+				},
+			},
+			wantTokens: nil,
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "error/corrupted message",
+			args: args{
+				options: ProtocolOptions{},
+				data: func(options ProtocolOptions) []byte {
+					data := newTestData(options, "one", "two")
+					data[len(data)-5] ^= 0xff // corrupt the second message
+
+					return data
+				},
+			},
+			wantTokens: [][]byte{[]byte("one")},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				var checksumMismatchErr ChecksumMismatchError
+				return assert.True(test, errors.As(err, &checksumMismatchErr))
+			},
+		},
+		{
+			name: "error/incomplete checksum",
+			args: args{
+				options: ProtocolOptions{},
+				data: func(options ProtocolOptions) []byte {
+					data := newTestData(options, "one", "two")
+					return data[:len(data)-1]
+				},
+			},
+			wantTokens: [][]byte{[]byte("one")},
+			wantErr:    assert.Error,
+		},
+		{
+			name: "error/incomplete message",
+			args: args{
+				options: ProtocolOptions{},
+				data: func(options ProtocolOptions) []byte {
+					return []byte("\x00\x00\x00\x05thr")
+				},
+			},
+			wantTokens: nil,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				var checksumMismatchErr ChecksumMismatchError
+				return assert.Error(test, err) &&
+					assert.False(test, errors.As(err, &checksumMismatchErr))
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			scanner := tcpServer.InitializeScanner(
+				tcpServer.InitializeScannerParams[
+					defaultProtocolModels.Request,
+					defaultProtocolModels.Response,
+				]{
+					Reader: bytes.NewReader(data.args.data(data.args.options)),
+					BaseProtocol: NewBaseProtocol[
+						defaultProtocolModels.Request,
+						defaultProtocolModels.Response,
+					](
+						newTestProtocol(),
+						data.args.options,
+					),
+				},
+			)
+
+			var gotTokens [][]byte
+			for scanner.Scan() {
+				gotTokens = append(gotTokens, bytes.Clone(scanner.Bytes()))
+			}
+
+			assert.Equal(test, data.wantTokens, gotTokens)
+			data.wantErr(test, scanner.Err())
+		})
+	}
+}
+
+func TestBaseProtocol_ExtractToken_withFinalToken(test *testing.T) {
+	for _, data := range []struct {
+		name       string
+		data       func() []byte
+		wantTokens [][]byte
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			data: func() []byte {
+				return slices.Concat(
+					[]byte("one\n"),
+					checksumOf([]byte("one\n")),
+					[]byte("two"),
+					checksumOf([]byte("two")),
+				)
+			},
+			wantTokens: [][]byte{[]byte("one"), []byte("two")},
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "error/corrupted message",
+			data: func() []byte {
+				return slices.Concat(
+					[]byte("one\n"),
+					checksumOf([]byte("one\n")),
+					[]byte("tw0"),
+					checksumOf([]byte("two")),
+				)
+			},
+			wantTokens: [][]byte{[]byte("one")},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				var checksumMismatchErr ChecksumMismatchError
+				return assert.True(test, errors.As(err, &checksumMismatchErr))
+			},
+		},
+		{
+			name: "error/incomplete checksum",
+			data: func() []byte {
+				return slices.Concat(
+					[]byte("one\n"),
+					checksumOf([]byte("one\n")),
+					[]byte("tw"),
+				)
+			},
+			wantTokens: [][]byte{[]byte("one")},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, io.ErrUnexpectedEOF)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			scanner := tcpServer.InitializeScanner(
+				tcpServer.InitializeScannerParams[
+					defaultProtocolModels.Request,
+					defaultProtocolModels.Response,
+				]{
+					Reader: bytes.NewReader(data.data()),
+					BaseProtocol: NewBaseProtocol[
+						defaultProtocolModels.Request,
+						defaultProtocolModels.Response,
+					](
+						separatorBasedProtocol.NewProtocol(
+							separatorBasedProtocol.SeparationParams{
+								MessageSeparator:        []byte("\n"),
+								MessagePartSeparator:    []byte("|"),
+								HeaderSeparator:         []byte("&"),
+								HeaderKeyValueSeparator: []byte("="),
+							},
+						),
+						ProtocolOptions{},
+					),
+				},
+			)
+
+			var gotTokens [][]byte
+			for scanner.Scan() {
+				gotTokens = append(gotTokens, bytes.Clone(scanner.Bytes()))
+			}
+
+			assert.Equal(test, data.wantTokens, gotTokens)
+			data.wantErr(test, scanner.Err())
+		})
+	}
+}
+
+func TestClientProtocol_MarshalRequest(test *testing.T) {
+	action, err := defaultProtocolModelValueTypes.NewAction([]byte("dummy"))
+	require.NoError(test, err)
+
+	request, err := defaultProtocolModels.NewRequestBuilder().
+		SetAction(action).
+		SetBody(defaultProtocolModelValueTypes.NewBody([]byte("body"))).
+		Build()
+	require.NoError(test, err)
+
+	clientProtocol := NewClientProtocol[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	](newTestProtocol(), ProtocolOptions{})
+	got, err := clientProtocol.MarshalRequest(request)
+	require.NoError(test, err)
+
+	wantMessage := []byte("\x00\x00\x00\x0bdummy||body")
+	assert.Equal(
+		test,
+		append(bytes.Clone(wantMessage), checksumOf(wantMessage)...),
+		got,
+	)
+
+	serverProtocol := NewServerProtocol[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	](newTestProtocol(), ProtocolOptions{})
+	offsetToNextToken, token, err := serverProtocol.ExtractToken(got, true)
+	require.NoError(test, err)
+	assert.Equal(test, len(got), offsetToNextToken)
+
+	gotRequest, err := serverProtocol.ParseRequest(token)
+	require.NoError(test, err)
+	assert.Equal(test, request, gotRequest)
+}
+
+func newTestProtocol() lengthPrefixedProtocol.Protocol {
+	return lengthPrefixedProtocol.NewProtocol(
+		lengthPrefixedProtocol.ProtocolOptions{
+			MessageFormat: separatorBasedProtocol.NewMessageFormat(
+				separatorBasedProtocol.SeparationParams{
+					MessageSeparator:        []byte("\n"),
+					MessagePartSeparator:    []byte("|"),
+					HeaderSeparator:         []byte("&"),
+					HeaderKeyValueSeparator: []byte("="),
+				},
+			),
+		},
+	)
+}
+
+func newTestData(options ProtocolOptions, messages ...string) []byte {
+	protocol := NewBaseProtocol[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	](newTestProtocol(), options)
+
+	var data []byte
+	for _, message := range messages {
+		data = append(data, protocol.appendChecksum(
+			lengthPrefixedProtocol.AppendLengthPrefixed(nil, []byte(message)),
+		)...)
+	}
+
+	return data
+}
+
+func checksumOf(data []byte) []byte {
+	hash := NewCRC32CHash()
+	hash.Write(data) //nolint:errcheck
+
+	return hash.Sum(nil)
+}
@@ -0,0 +1,130 @@
+package defaultProtocol
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/samber/mo"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+const (
+	AuthenticationAction             = "__authenticate__"
+	AuthenticationMechanismHeaderKey = "__mechanism__"
+	ChallengeStatus                  = "__challenge__"
+	AuthenticatedStatus              = "__authenticated__"
+	UnauthenticatedStatus            = "__unauthenticated__"
+)
+
+var (
+	authenticationStatuses = map[tcpServer.AuthenticationStatus]string{
+		tcpServer.ChallengeAuthenticationStatus: ChallengeStatus,
+		tcpServer.SucceededAuthenticationStatus: AuthenticatedStatus,
+		tcpServer.FailedAuthenticationStatus:    UnauthenticatedStatus,
+	}
+)
+
+type AuthenticationProtocol struct{}
+
+func NewAuthenticationProtocol() AuthenticationProtocol {
+	return AuthenticationProtocol{}
+}
+
+func (protocol AuthenticationProtocol) NewAuthenticationRequest(
+	message tcpServer.AuthenticationMessage,
+) (defaultProtocolModels.Request, error) {
+	action, err :=
+		defaultProtocolModelValueTypes.NewAction([]byte(AuthenticationAction))
+	if err != nil {
+		return defaultProtocolModels.Request{}, fmt.Errorf(
+			"unable to construct the action: %w",
+			err,
+		)
+	}
+
+	requestBuilder := defaultProtocolModels.NewRequestBuilder().
+		SetAction(action).
+		SetBody(defaultProtocolModelValueTypes.NewBody(message.Data))
+	if message.Mechanism != "" {
+		requestBuilder.SetHeaders(addHeader(
+			mo.None[defaultProtocolModelValueTypes.Headers](),
+			AuthenticationMechanismHeaderKey,
+			message.Mechanism,
+		))
+	}
+
+	request, err := requestBuilder.Build()
+	if err != nil {
+		return defaultProtocolModels.Request{}, fmt.Errorf(
+			"unable to build the request: %w",
+			err,
+		)
+	}
+
+	return request, nil
+}
+
+func (protocol AuthenticationProtocol) AuthenticationRequest(
+	request defaultProtocolModels.Request,
+) mo.Option[tcpServer.AuthenticationMessage] {
+	if !bytes.Equal(request.Action().ToBytes(), []byte(AuthenticationAction)) {
+		return mo.None[tcpServer.AuthenticationMessage]()
+	}
+
+	mechanism :=
+		headerValue(request.Headers(), AuthenticationMechanismHeaderKey)
+	return mo.Some(tcpServer.AuthenticationMessage{
+		Mechanism: string(mechanism.OrEmpty()),
+		Data:      request.Body().OrEmpty().ToBytes(),
+	})
+}
+
+func (protocol AuthenticationProtocol) NewAuthenticationResponse(
+	reply tcpServer.AuthenticationReply,
+) (defaultProtocolModels.Response, error) {
+	rawStatus, isFound := authenticationStatuses[reply.Status]
+	if !isFound {
+		return defaultProtocolModels.Response{}, fmt.Errorf(
+			"unknown authentication status %q",
+			reply.Status,
+		)
+	}
+
+	status, err := defaultProtocolModelValueTypes.NewStatus([]byte(rawStatus))
+	if err != nil {
+		return defaultProtocolModels.Response{}, fmt.Errorf(
+			"unable to construct the status: %w",
+			err,
+		)
+	}
+
+	response, err := defaultProtocolModels.NewResponseBuilder().
+		SetStatus(status).
+		SetBody(defaultProtocolModelValueTypes.NewBody(reply.Data)).
+		Build()
+	if err != nil {
+		return defaultProtocolModels.Response{}, fmt.Errorf(
+			"unable to build the response: %w",
+			err,
+		)
+	}
+
+	return response, nil
+}
+
+func (protocol AuthenticationProtocol) AuthenticationReply(
+	response defaultProtocolModels.Response,
+) mo.Option[tcpServer.AuthenticationReply] {
+	for status, rawStatus := range authenticationStatuses {
+		if bytes.Equal(response.Status().ToBytes(), []byte(rawStatus)) {
+			return mo.Some(tcpServer.AuthenticationReply{
+				Status: status,
+				Data:   response.Body().OrEmpty().ToBytes(),
+			})
+		}
+	}
+
+	return mo.None[tcpServer.AuthenticationReply]()
+}
@@ -0,0 +1,137 @@
+package defaultProtocol
+
+import (
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+)
+
+func TestAuthenticationProtocol_interface(test *testing.T) {
+	assert.Implements(
+		test,
+		(*tcpServer.AuthenticationProtocol[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		])(nil),
+		AuthenticationProtocol{},
+	)
+}
+
+func TestAuthenticationProtocol_request(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		message tcpServer.AuthenticationMessage
+	}{
+		{
+			name: "with the mechanism",
+			message: tcpServer.AuthenticationMessage{
+				Mechanism: "PLAIN",
+				Data:      []byte("\x00user\x00password"),
+			},
+		},
+		{
+			name: "without the mechanism",
+			message: tcpServer.AuthenticationMessage{
+				Data: []byte("answer"),
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			protocol := NewAuthenticationProtocol()
+
+			request, err := protocol.NewAuthenticationRequest(data.message)
+			require.NoError(test, err)
+
+			assert.Equal(
+				test,
+				[]byte(AuthenticationAction),
+				request.Action().ToBytes(),
+			)
+			assert.Equal(
+				test,
+				mo.Some(data.message),
+				protocol.AuthenticationRequest(request),
+			)
+		})
+	}
+
+	assert.Equal(
+		test,
+		mo.None[tcpServer.AuthenticationMessage](),
+		NewAuthenticationProtocol().
+			AuthenticationRequest(newTestRequest(test, "dummy", "body")),
+	)
+}
+
+func TestAuthenticationProtocol_response(test *testing.T) {
+	for _, data := range []struct {
+		name       string
+		reply      tcpServer.AuthenticationReply
+		wantStatus string
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/challenge",
+			reply: tcpServer.AuthenticationReply{
+				Status: tcpServer.ChallengeAuthenticationStatus,
+				Data:   []byte("challenge"),
+			},
+			wantStatus: ChallengeStatus,
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "success/succeeded",
+			reply: tcpServer.AuthenticationReply{
+				Status: tcpServer.SucceededAuthenticationStatus,
+				Data:   []byte("data"),
+			},
+			wantStatus: AuthenticatedStatus,
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "success/failed",
+			reply: tcpServer.AuthenticationReply{
+				Status: tcpServer.FailedAuthenticationStatus,
+				Data:   []byte("reason"),
+			},
+			wantStatus: UnauthenticatedStatus,
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "error/unknown status",
+			reply: tcpServer.AuthenticationReply{
+				Status: "unknown",
+			},
+			wantStatus: "",
+			wantErr:    assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			protocol := NewAuthenticationProtocol()
+
+			response, err := protocol.NewAuthenticationResponse(data.reply)
+			data.wantErr(test, err)
+			if err != nil {
+				return
+			}
+
+			assert.Equal(test, []byte(data.wantStatus), response.Status().ToBytes())
+			assert.Equal(
+				test,
+				mo.Some(data.reply),
+				protocol.AuthenticationReply(response),
+			)
+		})
+	}
+
+	assert.Equal(
+		test,
+		mo.None[tcpServer.AuthenticationReply](),
+		NewAuthenticationProtocol().
+			AuthenticationReply(newTestResponse(test, "dummy", "body")),
+	)
+}
@@ -0,0 +1,233 @@
+package defaultProtocol
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/samber/mo"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+const (
+	ForbiddenStatus = "__forbidden__"
+)
+
+type AuthorizationRule struct {
+	// ActionPattern can contain the `*` wildcards matching any characters
+	ActionPattern string
+	// Roles are alternatives: any of them is sufficient
+	Roles []string
+	// Scopes are requirements: all of them are necessary
+	Scopes []string
+	// IsAnonymousAllowed permits the requests without the principal
+	IsAnonymousAllowed bool
+}
+
+type AuthorizationDecision struct {
+	IsAllowed bool
+	Reason    string
+}
+
+type AuthorizationDecisionFunc func(
+	ctx context.Context,
+	principal mo.Option[tcpServer.Principal],
+	request defaultProtocolModels.Request,
+) (AuthorizationDecision, error)
+
+type AuthorizationEvent struct {
+	Time      time.Time
+	Principal mo.Option[tcpServer.Principal]
+	Action    string
+	Decision  AuthorizationDecision
+	Err       error
+}
+
+type AuthorizationOptions struct {
+	Rules []AuthorizationRule
+	// DecisionFunc replaces the rules; use `DecideByRules()`
+	// to combine them with the custom logic
+	DecisionFunc mo.Option[AuthorizationDecisionFunc]
+	AuditHandler mo.Option[func(ctx context.Context, event AuthorizationEvent)]
+}
+
+// NewAuthorizationMiddleware replies to the denied requests
+// with the forbidden status and the reason in the body;
+// the decision errors lead to the denial as well
+func NewAuthorizationMiddleware(
+	options AuthorizationOptions,
+) tcpServer.RequestMiddleware[
+	defaultProtocolModels.Request,
+	defaultProtocolModels.Response,
+] {
+	decisionFunc := options.DecisionFunc.OrElse(DecideByRules(options.Rules))
+
+	return func(
+		handler tcpServer.RequestHandler[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		],
+	) tcpServer.RequestHandler[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	] {
+		return tcpServer.RequestHandlerFunc[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		](func(
+			ctx context.Context,
+			request defaultProtocolModels.Request,
+		) (defaultProtocolModels.Response, error) {
+			principal := mo.TupleToOption(tcpServer.PrincipalFromContext(ctx))
+			decision, err := decisionFunc(ctx, principal, request)
+			if err != nil {
+				decision = AuthorizationDecision{
+					IsAllowed: false,
+					Reason:    "unable to make the decision",
+				}
+			}
+
+			if auditHandler, isPresent := options.AuditHandler.Get(); isPresent {
+				auditHandler(ctx, AuthorizationEvent{
+					Time:      time.Now(),
+					Principal: principal,
+					Action:    string(request.Action().ToBytes()),
+					Decision:  decision,
+					Err:       err,
+				})
+			}
+
+			if !decision.IsAllowed {
+				return newForbiddenResponse(decision.Reason)
+			}
+
+			return handler.HandleRequest(ctx, request)
+		})
+	}
+}
+
+// DecideByRules applies the first rule, which pattern matches the action;
+// the action without the matching rule is denied
+func DecideByRules(rules []AuthorizationRule) AuthorizationDecisionFunc {
+	return func(
+		ctx context.Context,
+		principal mo.Option[tcpServer.Principal],
+		request defaultProtocolModels.Request,
+	) (AuthorizationDecision, error) {
+		action := string(request.Action().ToBytes())
+		for _, rule := range rules {
+			if !MatchActionPattern(rule.ActionPattern, action) {
+				continue
+			}
+
+			return rule.decide(principal), nil
+		}
+
+		return AuthorizationDecision{
+			IsAllowed: false,
+			Reason:    "no matching rule",
+		}, nil
+	}
+}
+
+// MatchActionPattern backtracks only to the last wildcard on a mismatch,
+// so its cost is bounded by the product of the lengths
+// regardless of the number of the wildcards
+func MatchActionPattern(pattern string, action string) bool {
+	patternIndex, actionIndex := 0, 0
+	lastWildcardIndex, lastWildcardActionIndex := -1, 0
+	for actionIndex < len(action) {
+		switch {
+		case patternIndex < len(pattern) && pattern[patternIndex] == '*':
+			lastWildcardIndex = patternIndex
+			lastWildcardActionIndex = actionIndex
+			patternIndex++
+
+		case patternIndex < len(pattern) &&
+			pattern[patternIndex] == action[actionIndex]:
+			patternIndex++
+			actionIndex++
+
+		case lastWildcardIndex != -1:
+			// extend the last wildcard by one character
+			patternIndex = lastWildcardIndex + 1
+			lastWildcardActionIndex++
+			actionIndex = lastWildcardActionIndex
+
+		default:
+			return false
+		}
+	}
+
+	for patternIndex < len(pattern) && pattern[patternIndex] == '*' {
+		patternIndex++
+	}
+
+	return patternIndex == len(pattern)
+}
+
+func (rule AuthorizationRule) decide(
+	principal mo.Option[tcpServer.Principal],
+) AuthorizationDecision {
+	principalValue, isPresent := principal.Get()
+	if !isPresent {
+		if rule.IsAnonymousAllowed {
+			return AuthorizationDecision{IsAllowed: true}
+		}
+
+		return AuthorizationDecision{
+			IsAllowed: false,
+			Reason:    "principal is missed",
+		}
+	}
+
+	if len(rule.Roles) != 0 &&
+		!slices.ContainsFunc(rule.Roles, func(role string) bool {
+			return slices.Contains(principalValue.Roles, role)
+		}) {
+		return AuthorizationDecision{
+			IsAllowed: false,
+			Reason:    "required role is missed",
+		}
+	}
+
+	for _, scope := range rule.Scopes {
+		if !slices.Contains(principalValue.Scopes, scope) {
+			return AuthorizationDecision{
+				IsAllowed: false,
+				Reason:    fmt.Sprintf("required scope %q is missed", scope),
+			}
+		}
+	}
+
+	return AuthorizationDecision{IsAllowed: true}
+}
+
+func newForbiddenResponse(
+	reason string,
+) (defaultProtocolModels.Response, error) {
+	status, err :=
+		defaultProtocolModelValueTypes.NewStatus([]byte(ForbiddenStatus))
+	if err != nil {
+		return defaultProtocolModels.Response{}, fmt.Errorf(
+			"unable to construct the status: %w",
+			err,
+		)
+	}
+
+	response, err := defaultProtocolModels.NewResponseBuilder().
+		SetStatus(status).
+		SetBody(defaultProtocolModelValueTypes.NewBody([]byte(reason))).
+		Build()
+	if err != nil {
+		return defaultProtocolModels.Response{}, fmt.Errorf(
+			"unable to build the response: %w",
+			err,
+		)
+	}
+
+	return response, nil
+}
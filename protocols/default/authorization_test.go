@@ -0,0 +1,312 @@
+package defaultProtocol
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+)
+
+func TestMatchActionPattern(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		pattern string
+		action  string
+		want    bool
+	}{
+		{
+			name:    "without wildcards/matched",
+			pattern: "users.get",
+			action:  "users.get",
+			want:    true,
+		},
+		{
+			name:    "without wildcards/not matched",
+			pattern: "users.get",
+			action:  "users.delete",
+			want:    false,
+		},
+		{
+			name:    "with the suffix wildcard",
+			pattern: "users.*",
+			action:  "users.delete",
+			want:    true,
+		},
+		{
+			name:    "with the middle wildcard",
+			pattern: "users.*.get",
+			action:  "users.admin.profile.get",
+			want:    true,
+		},
+		{
+			name:    "with the empty wildcard",
+			pattern: "users.*get",
+			action:  "users.get",
+			want:    true,
+		},
+		{
+			name:    "with several wildcards/not matched",
+			pattern: "*.get.*",
+			action:  "users.delete.all",
+			want:    false,
+		},
+		{
+			name:    "with several wildcards/matched",
+			pattern: "*a*b*c",
+			action:  "xaybzc",
+			want:    true,
+		},
+		{
+			name:    "with the long action and several wildcards",
+			pattern: "*a*a*a*b",
+			action:  strings.Repeat("a", 100_000),
+			want:    false,
+		},
+		{
+			name:    "with the single wildcard",
+			pattern: "*",
+			action:  "anything",
+			want:    true,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := MatchActionPattern(data.pattern, data.action)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestDecideByRules(test *testing.T) {
+	rules := []AuthorizationRule{
+		{ActionPattern: "public.*", IsAnonymousAllowed: true},
+		{ActionPattern: "users.get", Roles: []string{"admin", "reader"}},
+		{
+			ActionPattern: "users.*",
+			Roles:         []string{"admin"},
+			Scopes:        []string{"users:write", "users:delete"},
+		},
+	}
+
+	for _, data := range []struct {
+		name      string
+		principal mo.Option[tcpServer.Principal]
+		action    string
+		want      AuthorizationDecision
+	}{
+		{
+			name:      "success/anonymous",
+			principal: mo.None[tcpServer.Principal](),
+			action:    "public.info",
+			want:      AuthorizationDecision{IsAllowed: true},
+		},
+		{
+			name: "success/with one of the roles",
+			principal: mo.Some(tcpServer.Principal{
+				Name:  "user",
+				Roles: []string{"reader"},
+			}),
+			action: "users.get",
+			want:   AuthorizationDecision{IsAllowed: true},
+		},
+		{
+			name: "success/with the role and the scopes",
+			principal: mo.Some(tcpServer.Principal{
+				Name:   "user",
+				Roles:  []string{"admin"},
+				Scopes: []string{"users:delete", "users:write"},
+			}),
+			action: "users.delete",
+			want:   AuthorizationDecision{IsAllowed: true},
+		},
+		{
+			name:      "error/without the principal",
+			principal: mo.None[tcpServer.Principal](),
+			action:    "users.get",
+			want: AuthorizationDecision{
+				IsAllowed: false,
+				Reason:    "principal is missed",
+			},
+		},
+		{
+			name: "error/without the role",
+			principal: mo.Some(tcpServer.Principal{
+				Name:  "user",
+				Roles: []string{"guest"},
+			}),
+			action: "users.get",
+			want: AuthorizationDecision{
+				IsAllowed: false,
+				Reason:    "required role is missed",
+			},
+		},
+		{
+			name: "error/without the scope",
+			principal: mo.Some(tcpServer.Principal{
+				Name:   "user",
+				Roles:  []string{"admin"},
+				Scopes: []string{"users:write"},
+			}),
+			action: "users.delete",
+			want: AuthorizationDecision{
+				IsAllowed: false,
+				Reason:    `required scope "users:delete" is missed`,
+			},
+		},
+		{
+			name: "error/without the matching rule",
+			principal: mo.Some(tcpServer.Principal{
+				Name:  "user",
+				Roles: []string{"admin"},
+			}),
+			action: "orders.get",
+			want: AuthorizationDecision{
+				IsAllowed: false,
+				Reason:    "no matching rule",
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := DecideByRules(rules)(
+				context.Background(),
+				data.principal,
+				newTestRequest(test, data.action, "body"),
+			)
+			require.NoError(test, err)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestNewAuthorizationMiddleware(test *testing.T) {
+	principal := tcpServer.Principal{Name: "user", Roles: []string{"admin"}}
+
+	for _, data := range []struct {
+		name         string
+		options      AuthorizationOptions
+		ctx          context.Context
+		action       string
+		wantResponse defaultProtocolModels.Response
+		wantEvent    AuthorizationEvent
+	}{
+		{
+			name: "success/allowed",
+			options: AuthorizationOptions{
+				Rules: []AuthorizationRule{
+					{ActionPattern: "users.*", Roles: []string{"admin"}},
+				},
+			},
+			ctx:          newTestPrincipalContext(principal),
+			action:       "users.get",
+			wantResponse: newTestResponse(test, "dummy", ""),
+			wantEvent: AuthorizationEvent{
+				Principal: mo.Some(principal),
+				Action:    "users.get",
+				Decision:  AuthorizationDecision{IsAllowed: true},
+			},
+		},
+		{
+			name: "success/denied",
+			options: AuthorizationOptions{
+				Rules: []AuthorizationRule{
+					{ActionPattern: "users.*", Roles: []string{"admin"}},
+				},
+			},
+			ctx:          context.Background(),
+			action:       "users.get",
+			wantResponse: newTestResponse(test, ForbiddenStatus, "principal is missed"),
+			wantEvent: AuthorizationEvent{
+				Principal: mo.None[tcpServer.Principal](),
+				Action:    "users.get",
+				Decision: AuthorizationDecision{
+					IsAllowed: false,
+					Reason:    "principal is missed",
+				},
+			},
+		},
+		{
+			name: "success/with the decision function",
+			options: AuthorizationOptions{
+				DecisionFunc: mo.Some[AuthorizationDecisionFunc](func(
+					ctx context.Context,
+					principal mo.Option[tcpServer.Principal],
+					request defaultProtocolModels.Request,
+				) (AuthorizationDecision, error) {
+					return AuthorizationDecision{IsAllowed: principal.IsPresent()}, nil
+				}),
+			},
+			ctx:          newTestPrincipalContext(principal),
+			action:       "orders.get",
+			wantResponse: newTestResponse(test, "dummy", ""),
+			wantEvent: AuthorizationEvent{
+				Principal: mo.Some(principal),
+				Action:    "orders.get",
+				Decision:  AuthorizationDecision{IsAllowed: true},
+			},
+		},
+		{
+			name: "error/with the decision function",
+			options: AuthorizationOptions{
+				DecisionFunc: mo.Some[AuthorizationDecisionFunc](func(
+					ctx context.Context,
+					principal mo.Option[tcpServer.Principal],
+					request defaultProtocolModels.Request,
+				) (AuthorizationDecision, error) {
+					return AuthorizationDecision{IsAllowed: true}, errors.New("dummy")
+				}),
+			},
+			ctx:    newTestPrincipalContext(principal),
+			action: "orders.get",
+			wantResponse: newTestResponse(
+				test,
+				ForbiddenStatus,
+				"unable to make the decision",
+			),
+			wantEvent: AuthorizationEvent{
+				Principal: mo.Some(principal),
+				Action:    "orders.get",
+				Decision: AuthorizationDecision{
+					IsAllowed: false,
+					Reason:    "unable to make the decision",
+				},
+				Err: errors.New("dummy"),
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var events []AuthorizationEvent
+			data.options.AuditHandler = mo.Some(func(
+				ctx context.Context,
+				event AuthorizationEvent,
+			) {
+				events = append(events, event)
+			})
+
+			handler := NewAuthorizationMiddleware(data.options)(
+				newTestStaticHandler(test),
+			)
+			response, err := handler.HandleRequest(
+				data.ctx,
+				newTestRequest(test, data.action, "body"),
+			)
+			require.NoError(test, err)
+
+			assert.Equal(test, data.wantResponse, response)
+			require.Len(test, events, 1)
+			assert.NotZero(test, events[0].Time)
+			events[0].Time = data.wantEvent.Time
+			assert.Equal(test, data.wantEvent, events[0])
+		})
+	}
+}
+
+func newTestPrincipalContext(principal tcpServer.Principal) context.Context {
+	return tcpServer.ContextWithPrincipal(context.Background(), principal)
+}
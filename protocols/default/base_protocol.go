@@ -1,11 +1,17 @@
 package defaultProtocol
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/samber/mo"
 	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
 )
 
+var (
+	ErrMessageIsTooLarge = errors.New("message is too large")
+)
+
 type MessageFormat interface {
 	ParseMessage(data []byte) (defaultProtocolModels.Message, error)
 	MarshalMessage(message defaultProtocolModels.Message) ([]byte, error)
@@ -13,6 +19,9 @@ type MessageFormat interface {
 
 type BaseProtocolOptions struct {
 	MessageFormat MessageFormat
+	// MaxTokenSize limits the size of a single message;
+	// use the chunked transfer to exchange larger bodies
+	MaxTokenSize mo.Option[int]
 }
 
 type BaseProtocol struct {
@@ -26,11 +35,14 @@ func NewBaseProtocol(options BaseProtocolOptions) BaseProtocol {
 }
 
 func (protocol BaseProtocol) InitialScannerBufferSize() int {
-	return 4 * 1024 // based on the default values in package `bufio`
+	// based on the default values in package `bufio`;
+	// the buffer can't exceed the maximum, otherwise it would allow larger tokens
+	return min(4*1024, protocol.MaxTokenSize())
 }
 
 func (protocol BaseProtocol) MaxTokenSize() int {
-	return 64 * 1024 // based on the default values in package `bufio`
+	// based on the default values in package `bufio`
+	return protocol.options.MaxTokenSize.OrElse(64 * 1024)
 }
 
 func (protocol BaseProtocol) ParseRequest(
@@ -95,6 +107,15 @@ func (protocol BaseProtocol) MarshalRequest(
 		return nil, fmt.Errorf("unable to marshal the message: %w", err)
 	}
 
+	// the peer would be unable to extract such a message,
+	// so reject it before writing instead of breaking the connection
+	if err := CheckMessageSize(
+		marshalledMessage,
+		protocol.MaxTokenSize(),
+	); err != nil {
+		return nil, err
+	}
+
 	return marshalledMessage, nil
 }
 
@@ -116,5 +137,27 @@ func (protocol BaseProtocol) MarshalResponse(
 		return nil, fmt.Errorf("unable to marshal the message: %w", err)
 	}
 
+	// the peer would be unable to extract such a message,
+	// so reject it before writing instead of breaking the connection
+	if err := CheckMessageSize(
+		marshalledMessage,
+		protocol.MaxTokenSize(),
+	); err != nil {
+		return nil, err
+	}
+
 	return marshalledMessage, nil
 }
+
+func CheckMessageSize(marshalledMessage []byte, maxSize int) error {
+	if len(marshalledMessage) > maxSize {
+		return fmt.Errorf(
+			"%w: %d bytes exceed the maximum %d",
+			ErrMessageIsTooLarge,
+			len(marshalledMessage),
+			maxSize,
+		)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"testing/iotest"
 
+	"github.com/samber/mo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	defaultBaseProtocolMocks "github.com/thewizardplusplus/go-tcp-server/mocks/github.com/thewizardplusplus/go-tcp-server/protocols/default"
@@ -48,17 +49,34 @@ func TestNewBaseProtocol(test *testing.T) {
 }
 
 func TestBaseProtocol_InitialScannerBufferSize(test *testing.T) {
+	type fields struct {
+		options BaseProtocolOptions
+	}
+
 	for _, data := range []struct {
-		name string
-		want int
+		name   string
+		fields fields
+		want   int
 	}{
 		{
-			name: "success",
+			name: "success/by default",
+			fields: fields{
+				options: BaseProtocolOptions{},
+			},
 			want: 4 * 1024,
 		},
+		{
+			name: "success/with the small maximum",
+			fields: fields{
+				options: BaseProtocolOptions{
+					MaxTokenSize: mo.Some(1024),
+				},
+			},
+			want: 1024,
+		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
-			got := (BaseProtocol{}).InitialScannerBufferSize()
+			got := NewBaseProtocol(data.fields.options).InitialScannerBufferSize()
 
 			assert.Equal(test, data.want, got)
 		})
@@ -66,17 +84,34 @@ func TestBaseProtocol_InitialScannerBufferSize(test *testing.T) {
 }
 
 func TestBaseProtocol_MaxTokenSize(test *testing.T) {
+	type fields struct {
+		options BaseProtocolOptions
+	}
+
 	for _, data := range []struct {
-		name string
-		want int
+		name   string
+		fields fields
+		want   int
 	}{
 		{
-			name: "success",
+			name: "success/by default",
+			fields: fields{
+				options: BaseProtocolOptions{},
+			},
 			want: 64 * 1024,
 		},
+		{
+			name: "success/with the maximum",
+			fields: fields{
+				options: BaseProtocolOptions{
+					MaxTokenSize: mo.Some(1024 * 1024),
+				},
+			},
+			want: 1024 * 1024,
+		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
-			got := (BaseProtocol{}).MaxTokenSize()
+			got := NewBaseProtocol(data.fields.options).MaxTokenSize()
 
 			assert.Equal(test, data.want, got)
 		})
@@ -409,6 +444,53 @@ func TestBaseProtocol_MarshalRequest(test *testing.T) {
 			want:    nil,
 			wantErr: assert.Error,
 		},
+		{
+			name: "error/message is too large",
+			fields: fields{
+				options: func(test *testing.T) BaseProtocolOptions {
+					introduction, err :=
+						defaultProtocolModelValueTypes.NewIntroduction([]byte("introduction"))
+					require.NoError(test, err)
+
+					message, err := defaultProtocolModels.NewMessageBuilder().
+						SetIntroduction(introduction).
+						Build()
+					require.NoError(test, err)
+
+					messageFormatMock := defaultBaseProtocolMocks.NewMockMessageFormat(test)
+					messageFormatMock.EXPECT().
+						MarshalMessage(message).
+						Return([]byte("dummy"), nil)
+
+					return BaseProtocolOptions{
+						MessageFormat: messageFormatMock,
+						MaxTokenSize:  mo.Some(4),
+					}
+				},
+			},
+			args: args{
+				request: func() defaultProtocolModels.Request {
+					action, err :=
+						defaultProtocolModelValueTypes.NewAction([]byte("introduction"))
+					require.NoError(test, err)
+
+					request, err := defaultProtocolModels.NewRequestBuilder().
+						SetAction(action).
+						Build()
+					require.NoError(test, err)
+
+					return request
+				}(),
+			},
+			want: nil,
+			wantErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(test, err, ErrMessageIsTooLarge, msgAndArgs...)
+			},
+		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
 			protocol := BaseProtocol{
@@ -532,6 +614,53 @@ func TestBaseProtocol_MarshalResponse(test *testing.T) {
 			want:    nil,
 			wantErr: assert.Error,
 		},
+		{
+			name: "error/message is too large",
+			fields: fields{
+				options: func(test *testing.T) BaseProtocolOptions {
+					introduction, err :=
+						defaultProtocolModelValueTypes.NewIntroduction([]byte("introduction"))
+					require.NoError(test, err)
+
+					message, err := defaultProtocolModels.NewMessageBuilder().
+						SetIntroduction(introduction).
+						Build()
+					require.NoError(test, err)
+
+					messageFormatMock := defaultBaseProtocolMocks.NewMockMessageFormat(test)
+					messageFormatMock.EXPECT().
+						MarshalMessage(message).
+						Return([]byte("dummy"), nil)
+
+					return BaseProtocolOptions{
+						MessageFormat: messageFormatMock,
+						MaxTokenSize:  mo.Some(4),
+					}
+				},
+			},
+			args: args{
+				response: func() defaultProtocolModels.Response {
+					status, err :=
+						defaultProtocolModelValueTypes.NewStatus([]byte("introduction"))
+					require.NoError(test, err)
+
+					response, err := defaultProtocolModels.NewResponseBuilder().
+						SetStatus(status).
+						Build()
+					require.NoError(test, err)
+
+					return response
+				}(),
+			},
+			want: nil,
+			wantErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(test, err, ErrMessageIsTooLarge, msgAndArgs...)
+			},
+		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
 			protocol := BaseProtocol{
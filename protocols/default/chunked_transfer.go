@@ -0,0 +1,450 @@
+package defaultProtocol
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/samber/mo"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+// the chunked message consists of the head marked with the chunked header,
+// whose body is the first part of the whole body, and the chunk frames
+// with the next parts; the chunk frame with an empty body ends the message
+
+const (
+	ChunkedHeaderKey   = "__chunked__"
+	ChunkedHeaderValue = "true"
+	ChunkAction        = "__chunk__"
+	ChunkStatus        = "__chunk__"
+	// the escaping of the separator-based format can triple the chunk,
+	// so the escaped chunk together with its head should still fit
+	// the default maximum token size of 64 KiB
+	DefaultChunkSize = 16 * 1024
+)
+
+type ChunkedTransferOptions struct {
+	ChunkSize mo.Option[int]
+}
+
+func (options ChunkedTransferOptions) chunkSize() int {
+	return options.ChunkSize.OrElse(DefaultChunkSize)
+}
+
+func IsChunkedRequest(request defaultProtocolModels.Request) bool {
+	return hasHeader(request.Headers(), ChunkedHeaderKey)
+}
+
+func IsChunkedResponse(response defaultProtocolModels.Response) bool {
+	return hasHeader(response.Headers(), ChunkedHeaderKey)
+}
+
+// NewChunkedTransferMiddleware allows the request handler to read
+// the chunked request bodies via RequestBody() and to write the chunked
+// response bodies via NewResponseBodyWriter()
+func NewChunkedTransferMiddleware(
+	options ChunkedTransferOptions,
+) tcpServer.RequestMiddleware[
+	defaultProtocolModels.Request,
+	defaultProtocolModels.Response,
+] {
+	return func(
+		handler tcpServer.RequestHandler[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		],
+	) tcpServer.RequestHandler[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	] {
+		return tcpServer.RequestHandlerFunc[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		](func(
+			ctx context.Context,
+			request defaultProtocolModels.Request,
+		) (defaultProtocolModels.Response, error) {
+			stream, isPresent := tcpServer.ServerStreamFromContext[
+				defaultProtocolModels.Request,
+				defaultProtocolModels.Response,
+			](ctx)
+			if !isPresent {
+				return handler.HandleRequest(ctx, request)
+			}
+
+			state := &chunkedTransferState{
+				stream:    stream,
+				chunkSize: options.chunkSize(),
+			}
+			if IsChunkedRequest(request) {
+				state.requestBody = mo.Some(newChunkReader(
+					request.Body().OrEmpty().ToBytes(),
+					func() ([]byte, error) {
+						chunk, err := stream.ReadRequest()
+						if err != nil {
+							return nil, fmt.Errorf("unable to read the chunk: %w", err)
+						}
+						if !bytes.Equal(chunk.Action().ToBytes(), []byte(ChunkAction)) {
+							return nil, errors.New("request is not a chunk")
+						}
+
+						return chunk.Body().OrEmpty().ToBytes(), nil
+					},
+				))
+			}
+
+			response, err := handler.HandleRequest(
+				context.WithValue(ctx, chunkedTransferStateContextKey{}, state),
+				request,
+			)
+
+			// the unread chunks would be taken for the next requests
+			if requestBody, isPresent := state.requestBody.Get(); isPresent {
+				if _, err := io.Copy(io.Discard, requestBody); err != nil {
+					return defaultProtocolModels.Response{}, fmt.Errorf(
+						"unable to drain the request body: %w",
+						err,
+					)
+				}
+			}
+
+			if responseBody, isPresent := state.responseBody.Get(); isPresent {
+				if closingErr := responseBody.Close(); closingErr != nil {
+					return defaultProtocolModels.Response{}, fmt.Errorf(
+						"unable to close the response body writer: %w",
+						closingErr,
+					)
+				}
+
+				// the head of the response is already sent,
+				// so only the end of the response body is left
+				lastChunk, chunkErr := newChunkResponse(nil)
+				if chunkErr != nil {
+					return defaultProtocolModels.Response{}, fmt.Errorf(
+						"unable to construct the last chunk: %w",
+						chunkErr,
+					)
+				}
+
+				return lastChunk, err
+			}
+
+			return response, err
+		})
+	}
+}
+
+// RequestBody returns the whole body of the request;
+// the chunked body is read on demand, so it's available only within
+// the request handling and requires the chunked transfer middleware
+func RequestBody(
+	ctx context.Context,
+	request defaultProtocolModels.Request,
+) (io.Reader, error) {
+	if !IsChunkedRequest(request) {
+		return bytes.NewReader(request.Body().OrEmpty().ToBytes()), nil
+	}
+
+	state, isPresent :=
+		ctx.Value(chunkedTransferStateContextKey{}).(*chunkedTransferState)
+	if !isPresent {
+		return nil, errors.New("chunked transfer is not enabled")
+	}
+
+	requestBody, isPresent := state.requestBody.Get()
+	if !isPresent {
+		return nil, errors.New("request is not handled by the middleware")
+	}
+
+	return requestBody, nil
+}
+
+// NewResponseBodyWriter sends the head of the response immediately
+// and the written data in chunks; in this case the chunked transfer
+// middleware ends the response itself, so the response returned
+// by the request handler is ignored
+func NewResponseBodyWriter(
+	ctx context.Context,
+	response defaultProtocolModels.Response,
+) (io.WriteCloser, error) {
+	state, isPresent :=
+		ctx.Value(chunkedTransferStateContextKey{}).(*chunkedTransferState)
+	if !isPresent {
+		return nil, errors.New("chunked transfer is not enabled")
+	}
+	if state.responseBody.IsPresent() {
+		return nil, errors.New("response body writer is already created")
+	}
+
+	responseHead, err :=
+		addResponseHeader(response, ChunkedHeaderKey, ChunkedHeaderValue)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct the response head: %w", err)
+	}
+
+	if err := state.stream.WriteResponse(responseHead); err != nil {
+		return nil, fmt.Errorf("unable to write the response head: %w", err)
+	}
+
+	responseBody := newChunkWriter(state.chunkSize, func(chunk []byte) error {
+		chunkResponse, err := newChunkResponse(chunk)
+		if err != nil {
+			return fmt.Errorf("unable to construct the chunk: %w", err)
+		}
+
+		if err := state.stream.WriteResponse(chunkResponse); err != nil {
+			return fmt.Errorf("unable to write the chunk: %w", err)
+		}
+
+		return nil
+	})
+	state.responseBody = mo.Some(responseBody)
+
+	return responseBody, nil
+}
+
+// WriteChunkedRequest sends the head of the request and then its body
+// in chunks; the own body of the request is sent before the passed one
+func WriteChunkedRequest(
+	stream tcpServer.ClientStream[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	],
+	request defaultProtocolModels.Request,
+	body io.Reader,
+	options ChunkedTransferOptions,
+) error {
+	requestHead, err :=
+		addRequestHeader(request, ChunkedHeaderKey, ChunkedHeaderValue)
+	if err != nil {
+		return fmt.Errorf("unable to construct the request head: %w", err)
+	}
+
+	if err := stream.WriteRequest(requestHead); err != nil {
+		return fmt.Errorf("unable to write the request head: %w", err)
+	}
+
+	requestBody := newChunkWriter(options.chunkSize(), func(chunk []byte) error {
+		chunkRequest, err := newChunkRequest(chunk)
+		if err != nil {
+			return fmt.Errorf("unable to construct the chunk: %w", err)
+		}
+
+		if err := stream.WriteRequest(chunkRequest); err != nil {
+			return fmt.Errorf("unable to write the chunk: %w", err)
+		}
+
+		return nil
+	})
+	if _, err := io.Copy(requestBody, body); err != nil {
+		return fmt.Errorf("unable to write the request body: %w", err)
+	}
+	if err := requestBody.Close(); err != nil {
+		return fmt.Errorf("unable to close the request body writer: %w", err)
+	}
+
+	lastChunk, err := newChunkRequest(nil)
+	if err != nil {
+		return fmt.Errorf("unable to construct the last chunk: %w", err)
+	}
+
+	if err := stream.WriteRequest(lastChunk); err != nil {
+		return fmt.Errorf("unable to write the last chunk: %w", err)
+	}
+
+	return nil
+}
+
+// ResponseBody returns the whole body of the response;
+// the chunked body is read on demand, so it must be read to the end
+// within the same exchange
+func ResponseBody(
+	stream tcpServer.ClientStream[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	],
+	response defaultProtocolModels.Response,
+) io.Reader {
+	if !IsChunkedResponse(response) {
+		return bytes.NewReader(response.Body().OrEmpty().ToBytes())
+	}
+
+	return newChunkReader(
+		response.Body().OrEmpty().ToBytes(),
+		func() ([]byte, error) {
+			chunk, err := stream.ReadResponse()
+			if err != nil {
+				return nil, fmt.Errorf("unable to read the chunk: %w", err)
+			}
+			if !bytes.Equal(chunk.Status().ToBytes(), []byte(ChunkStatus)) {
+				return nil, errors.New("response is not a chunk")
+			}
+
+			return chunk.Body().OrEmpty().ToBytes(), nil
+		},
+	)
+}
+
+type chunkedTransferStateContextKey struct{}
+
+type chunkedTransferState struct {
+	stream tcpServer.ServerStream[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	]
+	chunkSize    int
+	requestBody  mo.Option[*chunkReader]
+	responseBody mo.Option[*chunkWriter]
+}
+
+type chunkReader struct {
+	chunk         []byte
+	readNextChunk func() ([]byte, error)
+	err           error
+}
+
+func newChunkReader(
+	firstChunk []byte,
+	readNextChunk func() ([]byte, error),
+) *chunkReader {
+	return &chunkReader{
+		chunk:         firstChunk,
+		readNextChunk: readNextChunk,
+	}
+}
+
+func (reader *chunkReader) Read(buffer []byte) (int, error) {
+	if len(buffer) == 0 {
+		return 0, nil
+	}
+
+	for len(reader.chunk) == 0 {
+		if reader.err != nil {
+			return 0, reader.err
+		}
+
+		chunk, err := reader.readNextChunk()
+		if err == nil && len(chunk) == 0 {
+			err = io.EOF
+		}
+
+		reader.chunk, reader.err = chunk, err
+	}
+
+	count := copy(buffer, reader.chunk)
+	reader.chunk = reader.chunk[count:]
+
+	return count, nil
+}
+
+type chunkWriter struct {
+	chunkSize  int
+	buffer     []byte
+	writeChunk func(chunk []byte) error
+	isClosed   bool
+}
+
+func newChunkWriter(
+	chunkSize int,
+	writeChunk func(chunk []byte) error,
+) *chunkWriter {
+	return &chunkWriter{
+		chunkSize:  chunkSize,
+		buffer:     make([]byte, 0, chunkSize),
+		writeChunk: writeChunk,
+	}
+}
+
+func (writer *chunkWriter) Write(data []byte) (int, error) {
+	if writer.isClosed {
+		return 0, errors.New("writer is closed")
+	}
+
+	writtenCount := 0
+	for len(data) > 0 {
+		count := min(len(data), writer.chunkSize-len(writer.buffer))
+		writer.buffer = append(writer.buffer, data[:count]...)
+		data = data[count:]
+		writtenCount += count
+
+		if len(writer.buffer) == writer.chunkSize {
+			if err := writer.flush(); err != nil {
+				return writtenCount, err
+			}
+		}
+	}
+
+	return writtenCount, nil
+}
+
+func (writer *chunkWriter) Close() error {
+	if writer.isClosed {
+		return nil
+	}
+
+	writer.isClosed = true
+	return writer.flush()
+}
+
+func (writer *chunkWriter) flush() error {
+	if len(writer.buffer) == 0 {
+		return nil
+	}
+
+	// the chunk can be retained by the stream, so the buffer isn't reused
+	err := writer.writeChunk(writer.buffer)
+	writer.buffer = make([]byte, 0, writer.chunkSize)
+
+	return err
+}
+
+func newChunkRequest(chunk []byte) (defaultProtocolModels.Request, error) {
+	action, err := defaultProtocolModelValueTypes.NewAction([]byte(ChunkAction))
+	if err != nil {
+		return defaultProtocolModels.Request{}, fmt.Errorf(
+			"unable to construct the action: %w",
+			err,
+		)
+	}
+
+	request, err := defaultProtocolModels.NewRequestBuilder().
+		SetAction(action).
+		SetBody(defaultProtocolModelValueTypes.NewBody(chunk)).
+		Build()
+	if err != nil {
+		return defaultProtocolModels.Request{}, fmt.Errorf(
+			"unable to build the request: %w",
+			err,
+		)
+	}
+
+	return request, nil
+}
+
+func newChunkResponse(chunk []byte) (defaultProtocolModels.Response, error) {
+	status, err := defaultProtocolModelValueTypes.NewStatus([]byte(ChunkStatus))
+	if err != nil {
+		return defaultProtocolModels.Response{}, fmt.Errorf(
+			"unable to construct the status: %w",
+			err,
+		)
+	}
+
+	response, err := defaultProtocolModels.NewResponseBuilder().
+		SetStatus(status).
+		SetBody(defaultProtocolModelValueTypes.NewBody(chunk)).
+		Build()
+	if err != nil {
+		return defaultProtocolModels.Response{}, fmt.Errorf(
+			"unable to build the response: %w",
+			err,
+		)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,578 @@
+package defaultProtocol
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+func TestNewChunkedTransferMiddleware(test *testing.T) {
+	type args struct {
+		handler func(test *testing.T) tcpServer.RequestHandler[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		]
+		request func(test *testing.T) defaultProtocolModels.Request
+	}
+
+	for _, data := range []struct {
+		name              string
+		args              args
+		streamRequests    func(test *testing.T) []defaultProtocolModels.Request
+		wantResponse      func(test *testing.T) defaultProtocolModels.Response
+		wantStreamWritten func(test *testing.T) []defaultProtocolModels.Response
+		wantErr           assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/with the chunked request",
+			args: args{
+				handler: func(test *testing.T) tcpServer.RequestHandler[
+					defaultProtocolModels.Request,
+					defaultProtocolModels.Response,
+				] {
+					return newTestBodyEchoHandler(test)
+				},
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestChunkedRequest(test, "one")
+				},
+			},
+			streamRequests: func(test *testing.T) []defaultProtocolModels.Request {
+				return []defaultProtocolModels.Request{
+					newTestChunkRequest(test, "two"),
+					newTestChunkRequest(test, "three"),
+					newTestChunkRequest(test, ""),
+				}
+			},
+			wantResponse: func(test *testing.T) defaultProtocolModels.Response {
+				return newTestResponse(test, "dummy", "onetwothree")
+			},
+			wantStreamWritten: func(
+				test *testing.T,
+			) []defaultProtocolModels.Response {
+				return nil
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/with the unread chunks",
+			args: args{
+				handler: func(test *testing.T) tcpServer.RequestHandler[
+					defaultProtocolModels.Request,
+					defaultProtocolModels.Response,
+				] {
+					return newTestStaticHandler(test)
+				},
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestChunkedRequest(test, "one")
+				},
+			},
+			streamRequests: func(test *testing.T) []defaultProtocolModels.Request {
+				return []defaultProtocolModels.Request{
+					newTestChunkRequest(test, "two"),
+					newTestChunkRequest(test, ""),
+				}
+			},
+			wantResponse: func(test *testing.T) defaultProtocolModels.Response {
+				return newTestResponse(test, "dummy", "")
+			},
+			wantStreamWritten: func(
+				test *testing.T,
+			) []defaultProtocolModels.Response {
+				return nil
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/with the chunked response",
+			args: args{
+				handler: func(test *testing.T) tcpServer.RequestHandler[
+					defaultProtocolModels.Request,
+					defaultProtocolModels.Response,
+				] {
+					return tcpServer.RequestHandlerFunc[
+						defaultProtocolModels.Request,
+						defaultProtocolModels.Response,
+					](func(
+						ctx context.Context,
+						request defaultProtocolModels.Request,
+					) (defaultProtocolModels.Response, error) {
+						writer, err := NewResponseBodyWriter(
+							ctx,
+							newTestResponse(test, "dummy", "head"),
+						)
+						require.NoError(test, err)
+
+						_, err = io.WriteString(writer, "0123456789")
+						require.NoError(test, err)
+
+						return newTestResponse(test, "ignored", ""), nil
+					})
+				},
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestRequest(test, "dummy", "")
+				},
+			},
+			streamRequests: func(test *testing.T) []defaultProtocolModels.Request {
+				return nil
+			},
+			wantResponse: func(test *testing.T) defaultProtocolModels.Response {
+				return newTestChunkResponse(test, "")
+			},
+			wantStreamWritten: func(
+				test *testing.T,
+			) []defaultProtocolModels.Response {
+				return []defaultProtocolModels.Response{
+					newTestChunkedResponse(test, "head"),
+					newTestChunkResponse(test, "0123"),
+					newTestChunkResponse(test, "4567"),
+					newTestChunkResponse(test, "89"),
+				}
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/with the request that is not a chunk",
+			args: args{
+				handler: func(test *testing.T) tcpServer.RequestHandler[
+					defaultProtocolModels.Request,
+					defaultProtocolModels.Response,
+				] {
+					return newTestStaticHandler(test)
+				},
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestChunkedRequest(test, "one")
+				},
+			},
+			streamRequests: func(test *testing.T) []defaultProtocolModels.Request {
+				return []defaultProtocolModels.Request{
+					newTestRequest(test, "dummy", "two"),
+				}
+			},
+			wantResponse: func(test *testing.T) defaultProtocolModels.Response {
+				return defaultProtocolModels.Response{}
+			},
+			wantStreamWritten: func(
+				test *testing.T,
+			) []defaultProtocolModels.Response {
+				return nil
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/with the interrupted stream",
+			args: args{
+				handler: func(test *testing.T) tcpServer.RequestHandler[
+					defaultProtocolModels.Request,
+					defaultProtocolModels.Response,
+				] {
+					return newTestStaticHandler(test)
+				},
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestChunkedRequest(test, "one")
+				},
+			},
+			streamRequests: func(test *testing.T) []defaultProtocolModels.Request {
+				return []defaultProtocolModels.Request{
+					newTestChunkRequest(test, "two"),
+				}
+			},
+			wantResponse: func(test *testing.T) defaultProtocolModels.Response {
+				return defaultProtocolModels.Response{}
+			},
+			wantStreamWritten: func(
+				test *testing.T,
+			) []defaultProtocolModels.Response {
+				return nil
+			},
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			stream := &testServerStream{
+				requests: data.streamRequests(test),
+			}
+			ctx := tcpServer.ContextWithServerStream[
+				defaultProtocolModels.Request,
+				defaultProtocolModels.Response,
+			](context.Background(), stream)
+
+			middleware := NewChunkedTransferMiddleware(ChunkedTransferOptions{
+				ChunkSize: mo.Some(4),
+			})
+			got, err := middleware(data.args.handler(test)).
+				HandleRequest(ctx, data.args.request(test))
+
+			assert.Equal(test, data.wantResponse(test), got)
+			assert.Equal(test, data.wantStreamWritten(test), stream.responses)
+			assert.Empty(test, stream.requests)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestRequestBody(test *testing.T) {
+	type args struct {
+		ctx     context.Context
+		request func(test *testing.T) defaultProtocolModels.Request
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    []byte
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/with the regular request",
+			args: args{
+				ctx: context.Background(),
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestRequest(test, "dummy", "body")
+				},
+			},
+			want:    []byte("body"),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/with the regular request without the body",
+			args: args{
+				ctx: context.Background(),
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestRequest(test, "dummy", "")
+				},
+			},
+			want:    []byte{},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/with the chunked request without the middleware",
+			args: args{
+				ctx: context.Background(),
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestChunkedRequest(test, "body")
+				},
+			},
+			want:    nil,
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			reader, err := RequestBody(data.args.ctx, data.args.request(test))
+
+			var got []byte
+			if reader != nil {
+				got, err = io.ReadAll(reader)
+				require.NoError(test, err)
+			}
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestNewResponseBodyWriter_withoutMiddleware(test *testing.T) {
+	_, err := NewResponseBodyWriter(
+		context.Background(),
+		newTestResponse(test, "dummy", ""),
+	)
+
+	assert.Error(test, err)
+}
+
+func TestWriteChunkedRequest(test *testing.T) {
+	stream := &testClientStream{}
+	err := WriteChunkedRequest(
+		stream,
+		newTestRequest(test, "dummy", "head"),
+		strings.NewReader("0123456789"),
+		ChunkedTransferOptions{
+			ChunkSize: mo.Some(4),
+		},
+	)
+	require.NoError(test, err)
+
+	assert.Equal(
+		test,
+		[]defaultProtocolModels.Request{
+			newTestChunkedRequest(test, "head"),
+			newTestChunkRequest(test, "0123"),
+			newTestChunkRequest(test, "4567"),
+			newTestChunkRequest(test, "89"),
+			newTestChunkRequest(test, ""),
+		},
+		stream.requests,
+	)
+}
+
+func TestResponseBody(test *testing.T) {
+	type args struct {
+		response func(test *testing.T) defaultProtocolModels.Response
+	}
+
+	for _, data := range []struct {
+		name            string
+		args            args
+		streamResponses func(test *testing.T) []defaultProtocolModels.Response
+		want            []byte
+		wantErr         assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/with the regular response",
+			args: args{
+				response: func(test *testing.T) defaultProtocolModels.Response {
+					return newTestResponse(test, "dummy", "body")
+				},
+			},
+			streamResponses: func(
+				test *testing.T,
+			) []defaultProtocolModels.Response {
+				return nil
+			},
+			want:    []byte("body"),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/with the chunked response",
+			args: args{
+				response: func(test *testing.T) defaultProtocolModels.Response {
+					return newTestChunkedResponse(test, "one")
+				},
+			},
+			streamResponses: func(
+				test *testing.T,
+			) []defaultProtocolModels.Response {
+				return []defaultProtocolModels.Response{
+					newTestChunkResponse(test, "two"),
+					newTestChunkResponse(test, "three"),
+					newTestChunkResponse(test, ""),
+				}
+			},
+			want:    []byte("onetwothree"),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/with the response that is not a chunk",
+			args: args{
+				response: func(test *testing.T) defaultProtocolModels.Response {
+					return newTestChunkedResponse(test, "one")
+				},
+			},
+			streamResponses: func(
+				test *testing.T,
+			) []defaultProtocolModels.Response {
+				return []defaultProtocolModels.Response{
+					newTestResponse(test, "dummy", "two"),
+				}
+			},
+			want:    []byte("one"),
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			stream := &testClientStream{
+				responses: data.streamResponses(test),
+			}
+			got, err := io.ReadAll(ResponseBody(stream, data.args.response(test)))
+
+			assert.Equal(test, data.want, got)
+			assert.Empty(test, stream.responses)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+type testServerStream struct {
+	requests  []defaultProtocolModels.Request
+	responses []defaultProtocolModels.Response
+}
+
+func (stream *testServerStream) ReadRequest() (
+	defaultProtocolModels.Request,
+	error,
+) {
+	if len(stream.requests) == 0 {
+		return defaultProtocolModels.Request{}, errors.New("no more requests")
+	}
+
+	request := stream.requests[0]
+	stream.requests = stream.requests[1:]
+
+	return request, nil
+}
+
+func (stream *testServerStream) WriteResponse(
+	response defaultProtocolModels.Response,
+) error {
+	stream.responses = append(stream.responses, response)
+	return nil
+}
+
+type testClientStream struct {
+	requests  []defaultProtocolModels.Request
+	responses []defaultProtocolModels.Response
+}
+
+func (stream *testClientStream) WriteRequest(
+	request defaultProtocolModels.Request,
+) error {
+	stream.requests = append(stream.requests, request)
+	return nil
+}
+
+func (stream *testClientStream) ReadResponse() (
+	defaultProtocolModels.Response,
+	error,
+) {
+	if len(stream.responses) == 0 {
+		return defaultProtocolModels.Response{}, errors.New("no more responses")
+	}
+
+	response := stream.responses[0]
+	stream.responses = stream.responses[1:]
+
+	return response, nil
+}
+
+func newTestBodyEchoHandler(test *testing.T) tcpServer.RequestHandler[
+	defaultProtocolModels.Request,
+	defaultProtocolModels.Response,
+] {
+	return tcpServer.RequestHandlerFunc[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	](func(
+		ctx context.Context,
+		request defaultProtocolModels.Request,
+	) (defaultProtocolModels.Response, error) {
+		reader, err := RequestBody(ctx, request)
+		require.NoError(test, err)
+
+		body, err := io.ReadAll(reader)
+		require.NoError(test, err)
+
+		return newTestResponse(test, "dummy", string(body)), nil
+	})
+}
+
+func newTestStaticHandler(test *testing.T) tcpServer.RequestHandler[
+	defaultProtocolModels.Request,
+	defaultProtocolModels.Response,
+] {
+	return tcpServer.RequestHandlerFunc[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	](func(
+		ctx context.Context,
+		request defaultProtocolModels.Request,
+	) (defaultProtocolModels.Response, error) {
+		return newTestResponse(test, "dummy", ""), nil
+	})
+}
+
+func newTestRequest(
+	test *testing.T,
+	action string,
+	body string,
+) defaultProtocolModels.Request {
+	request, err := defaultProtocolModels.NewRequestBuilder().
+		SetAction(newTestAction(test, action)).
+		SetBody(defaultProtocolModelValueTypes.NewBody([]byte(body))).
+		Build()
+	require.NoError(test, err)
+
+	return request
+}
+
+func newTestChunkedRequest(
+	test *testing.T,
+	body string,
+) defaultProtocolModels.Request {
+	request, err := defaultProtocolModels.NewRequestBuilder().
+		SetAction(newTestAction(test, "dummy")).
+		SetHeaders(newTestChunkedHeaders()).
+		SetBody(defaultProtocolModelValueTypes.NewBody([]byte(body))).
+		Build()
+	require.NoError(test, err)
+
+	return request
+}
+
+func newTestChunkRequest(
+	test *testing.T,
+	body string,
+) defaultProtocolModels.Request {
+	return newTestRequest(test, ChunkAction, body)
+}
+
+func newTestResponse(
+	test *testing.T,
+	status string,
+	body string,
+) defaultProtocolModels.Response {
+	response, err := defaultProtocolModels.NewResponseBuilder().
+		SetStatus(newTestStatus(test, status)).
+		SetBody(defaultProtocolModelValueTypes.NewBody([]byte(body))).
+		Build()
+	require.NoError(test, err)
+
+	return response
+}
+
+func newTestChunkedResponse(
+	test *testing.T,
+	body string,
+) defaultProtocolModels.Response {
+	response, err := defaultProtocolModels.NewResponseBuilder().
+		SetStatus(newTestStatus(test, "dummy")).
+		SetHeaders(newTestChunkedHeaders()).
+		SetBody(defaultProtocolModelValueTypes.NewBody([]byte(body))).
+		Build()
+	require.NoError(test, err)
+
+	return response
+}
+
+func newTestChunkResponse(
+	test *testing.T,
+	body string,
+) defaultProtocolModels.Response {
+	return newTestResponse(test, ChunkStatus, body)
+}
+
+func newTestAction(
+	test *testing.T,
+	rawAction string,
+) defaultProtocolModelValueTypes.Action {
+	action, err := defaultProtocolModelValueTypes.NewAction([]byte(rawAction))
+	require.NoError(test, err)
+
+	return action
+}
+
+func newTestStatus(
+	test *testing.T,
+	rawStatus string,
+) defaultProtocolModelValueTypes.Status {
+	status, err := defaultProtocolModelValueTypes.NewStatus([]byte(rawStatus))
+	require.NoError(test, err)
+
+	return status
+}
+
+func newTestChunkedHeaders() defaultProtocolModelValueTypes.Headers {
+	return defaultProtocolModelValueTypes.NewHeaders(
+		map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue{ //nolint:lll
+			defaultProtocolModelValueTypes.MustNewHeaderKey([]byte(ChunkedHeaderKey)): defaultProtocolModelValueTypes.MustNewHeaderValue([]byte(ChunkedHeaderValue)), //nolint:lll
+		},
+	)
+}
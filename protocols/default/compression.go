@@ -0,0 +1,325 @@
+package defaultProtocol
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/samber/mo"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+const (
+	CompressionHeaderKey        = "__compression__"
+	DefaultCompressionThreshold = 1024
+	DefaultMaxDecompressedSize  = 16 * 1024 * 1024
+)
+
+var (
+	ErrUnknownCompressionCodec         = errors.New("unknown compression codec")
+	ErrDecompressedSizeLimitIsExceeded = errors.New(
+		"decompressed size limit is exceeded",
+	)
+)
+
+type CompressionOptions struct {
+	// Codecs are available for the decompression;
+	// gzip, deflate and zlib are provided by the root package,
+	// the other formats (e.g. zstd) are plugged in
+	// via the `tcpServer.CompressionCodec` interface;
+	// the server compresses the response with the codec negotiated
+	// by the handshake or with the one of the request,
+	// the client compresses the requests with the first codec
+	Codecs []tcpServer.CompressionCodec
+	// Threshold is the min body size to compress
+	Threshold           mo.Option[int]
+	MaxDecompressedSize mo.Option[int]
+}
+
+// NewCompressionMiddleware decompresses the requests before the handler
+// and compresses its responses; the bodies, which compressed size
+// isn't less than the original one, are sent as is
+func NewCompressionMiddleware(
+	options CompressionOptions,
+) tcpServer.RequestMiddleware[
+	defaultProtocolModels.Request,
+	defaultProtocolModels.Response,
+] {
+	return func(
+		handler tcpServer.RequestHandler[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		],
+	) tcpServer.RequestHandler[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	] {
+		return tcpServer.RequestHandlerFunc[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		](func(
+			ctx context.Context,
+			request defaultProtocolModels.Request,
+		) (defaultProtocolModels.Response, error) {
+			responseCodec := mo.None[tcpServer.CompressionCodec]()
+			if IsCompressedRequest(request) {
+				codec, err := options.findCodec(request.Headers())
+				if err != nil {
+					return defaultProtocolModels.Response{}, err
+				}
+
+				request, err = DecompressRequest(request, options)
+				if err != nil {
+					return defaultProtocolModels.Response{}, err
+				}
+
+				responseCodec = mo.Some(codec)
+			}
+			if result, isPresent :=
+				tcpServer.HandshakeResultFromContext(ctx); isPresent {
+				if codecName, isPresent := result.Compression.Get(); isPresent {
+					if codec, isFound := tcpServer.FindCompressionCodec(
+						options.Codecs,
+						codecName,
+					); isFound {
+						responseCodec = mo.Some(codec)
+					}
+				}
+			}
+
+			response, err := handler.HandleRequest(ctx, request)
+			if err != nil {
+				return response, err
+			}
+
+			codec, isPresent := responseCodec.Get()
+			if !isPresent {
+				return response, nil
+			}
+
+			return CompressResponse(response, codec, options)
+		})
+	}
+}
+
+// CompressingClientProtocol is the client counterpart
+// of the compression middleware
+type CompressingClientProtocol struct {
+	tcpServer.ClientProtocol[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	]
+
+	options CompressionOptions
+}
+
+func NewCompressingClientProtocol(
+	protocol tcpServer.ClientProtocol[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	],
+	options CompressionOptions,
+) CompressingClientProtocol {
+	return CompressingClientProtocol{
+		ClientProtocol: protocol,
+		options:        options,
+	}
+}
+
+func (protocol CompressingClientProtocol) MarshalRequest(
+	request defaultProtocolModels.Request,
+) ([]byte, error) {
+	if len(protocol.options.Codecs) != 0 {
+		var err error
+		request, err =
+			CompressRequest(request, protocol.options.Codecs[0], protocol.options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return protocol.ClientProtocol.MarshalRequest(request)
+}
+
+func (protocol CompressingClientProtocol) ParseResponse(
+	data []byte,
+) (defaultProtocolModels.Response, error) {
+	response, err := protocol.ClientProtocol.ParseResponse(data)
+	if err != nil {
+		return defaultProtocolModels.Response{}, err
+	}
+
+	return DecompressResponse(response, protocol.options)
+}
+
+func IsCompressedRequest(request defaultProtocolModels.Request) bool {
+	return hasHeader(request.Headers(), CompressionHeaderKey)
+}
+
+func IsCompressedResponse(response defaultProtocolModels.Response) bool {
+	return hasHeader(response.Headers(), CompressionHeaderKey)
+}
+
+func CompressRequest(
+	request defaultProtocolModels.Request,
+	codec tcpServer.CompressionCodec,
+	options CompressionOptions,
+) (defaultProtocolModels.Request, error) {
+	if IsCompressedRequest(request) {
+		return request, nil
+	}
+
+	compressedBody, isCompressed, err :=
+		options.compress(codec, request.Body().OrEmpty().ToBytes())
+	if err != nil || !isCompressed {
+		return request, err
+	}
+
+	return rebuildRequest(
+		request,
+		mo.Some(addHeader(request.Headers(), CompressionHeaderKey, codec.Name())),
+		defaultProtocolModelValueTypes.NewBody(compressedBody),
+	)
+}
+
+func CompressResponse(
+	response defaultProtocolModels.Response,
+	codec tcpServer.CompressionCodec,
+	options CompressionOptions,
+) (defaultProtocolModels.Response, error) {
+	if IsCompressedResponse(response) {
+		return response, nil
+	}
+
+	compressedBody, isCompressed, err :=
+		options.compress(codec, response.Body().OrEmpty().ToBytes())
+	if err != nil || !isCompressed {
+		return response, err
+	}
+
+	return rebuildResponse(
+		response,
+		mo.Some(addHeader(response.Headers(), CompressionHeaderKey, codec.Name())),
+		defaultProtocolModelValueTypes.NewBody(compressedBody),
+	)
+}
+
+func DecompressRequest(
+	request defaultProtocolModels.Request,
+	options CompressionOptions,
+) (defaultProtocolModels.Request, error) {
+	if !IsCompressedRequest(request) {
+		return request, nil
+	}
+
+	body, err := options.decompress(
+		request.Headers(),
+		request.Body().OrEmpty().ToBytes(),
+	)
+	if err != nil {
+		return defaultProtocolModels.Request{}, err
+	}
+
+	return rebuildRequest(
+		request,
+		removeHeader(request.Headers(), CompressionHeaderKey),
+		defaultProtocolModelValueTypes.NewBody(body),
+	)
+}
+
+func DecompressResponse(
+	response defaultProtocolModels.Response,
+	options CompressionOptions,
+) (defaultProtocolModels.Response, error) {
+	if !IsCompressedResponse(response) {
+		return response, nil
+	}
+
+	body, err := options.decompress(
+		response.Headers(),
+		response.Body().OrEmpty().ToBytes(),
+	)
+	if err != nil {
+		return defaultProtocolModels.Response{}, err
+	}
+
+	return rebuildResponse(
+		response,
+		removeHeader(response.Headers(), CompressionHeaderKey),
+		defaultProtocolModelValueTypes.NewBody(body),
+	)
+}
+
+func (options CompressionOptions) findCodec(
+	headers mo.Option[defaultProtocolModelValueTypes.Headers],
+) (tcpServer.CompressionCodec, error) {
+	codecName := string(headerValue(headers, CompressionHeaderKey).OrEmpty())
+	codec, isFound := tcpServer.FindCompressionCodec(options.Codecs, codecName)
+	if !isFound {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCompressionCodec, codecName)
+	}
+
+	return codec, nil
+}
+
+func (options CompressionOptions) compress(
+	codec tcpServer.CompressionCodec,
+	body []byte,
+) (compressedBody []byte, isCompressed bool, err error) {
+	if len(body) < options.Threshold.OrElse(DefaultCompressionThreshold) {
+		return nil, false, nil
+	}
+
+	var buffer bytes.Buffer
+	writer, err := codec.NewWriter(&buffer)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to construct the writer: %w", err)
+	}
+
+	if _, err := writer.Write(body); err != nil {
+		return nil, false, fmt.Errorf("unable to compress the body: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, false, fmt.Errorf("unable to close the writer: %w", err)
+	}
+
+	if buffer.Len() >= len(body) {
+		return nil, false, nil
+	}
+
+	return buffer.Bytes(), true, nil
+}
+
+func (options CompressionOptions) decompress(
+	headers mo.Option[defaultProtocolModelValueTypes.Headers],
+	body []byte,
+) ([]byte, error) {
+	codec, err := options.findCodec(headers)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := codec.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct the reader: %w", err)
+	}
+	defer reader.Close()
+
+	// read one byte over the limit to detect its exceeding
+	maxDecompressedSize :=
+		options.MaxDecompressedSize.OrElse(DefaultMaxDecompressedSize)
+	decompressedBody, err :=
+		io.ReadAll(io.LimitReader(reader, int64(maxDecompressedSize)+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decompress the body: %w", err)
+	}
+	if len(decompressedBody) > maxDecompressedSize {
+		return nil, ErrDecompressedSizeLimitIsExceeded
+	}
+
+	return decompressedBody, nil
+}
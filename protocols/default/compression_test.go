@@ -0,0 +1,315 @@
+package defaultProtocol
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+func TestNewCompressionMiddleware(test *testing.T) {
+	largeBody := strings.Repeat("data", 100)
+	options := CompressionOptions{
+		Codecs: []tcpServer.CompressionCodec{
+			tcpServer.NewGzipCodec(tcpServer.CompressionCodecOptions{}),
+			tcpServer.NewDeflateCodec(tcpServer.CompressionCodecOptions{}),
+		},
+		Threshold:           mo.Some(100),
+		MaxDecompressedSize: mo.Some(1000),
+	}
+
+	type args struct {
+		ctx     context.Context
+		request func(test *testing.T) defaultProtocolModels.Request
+	}
+
+	for _, data := range []struct {
+		name             string
+		args             args
+		responseBody     string
+		wantCodecName    mo.Option[string]
+		wantResponseBody string
+		wantErr          assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/without the compression",
+			args: args{
+				ctx: context.Background(),
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestRequest(test, "dummy", largeBody)
+				},
+			},
+			responseBody:     largeBody,
+			wantCodecName:    mo.None[string](),
+			wantResponseBody: largeBody,
+			wantErr:          assert.NoError,
+		},
+		{
+			name: "success/with the compressed request",
+			args: args{
+				ctx: context.Background(),
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestCompressedRequest(test, options.Codecs[1], largeBody)
+				},
+			},
+			responseBody:     largeBody,
+			wantCodecName:    mo.Some(tcpServer.DeflateCodecName),
+			wantResponseBody: largeBody,
+			wantErr:          assert.NoError,
+		},
+		{
+			name: "success/with the small response",
+			args: args{
+				ctx: context.Background(),
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestCompressedRequest(test, options.Codecs[1], largeBody)
+				},
+			},
+			responseBody:     "small",
+			wantCodecName:    mo.None[string](),
+			wantResponseBody: "small",
+			wantErr:          assert.NoError,
+		},
+		{
+			name: "success/with the negotiated codec",
+			args: args{
+				ctx: tcpServer.ContextWithHandshakeResult(
+					context.Background(),
+					tcpServer.HandshakeResult{
+						Version:     "v1",
+						Compression: mo.Some(tcpServer.GzipCodecName),
+					},
+				),
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestCompressedRequest(test, options.Codecs[1], largeBody)
+				},
+			},
+			responseBody:     largeBody,
+			wantCodecName:    mo.Some(tcpServer.GzipCodecName),
+			wantResponseBody: largeBody,
+			wantErr:          assert.NoError,
+		},
+		{
+			name: "error/with the unknown codec",
+			args: args{
+				ctx: context.Background(),
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					request, err := addRequestHeader(
+						newTestRequest(test, "dummy", largeBody),
+						CompressionHeaderKey,
+						"unknown",
+					)
+					require.NoError(test, err)
+
+					return request
+				},
+			},
+			responseBody:     largeBody,
+			wantCodecName:    mo.None[string](),
+			wantResponseBody: "",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrUnknownCompressionCodec)
+			},
+		},
+		{
+			name: "error/with the compression bomb",
+			args: args{
+				ctx: context.Background(),
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestCompressedRequest(
+						test,
+						options.Codecs[0],
+						strings.Repeat("data", 1000),
+					)
+				},
+			},
+			responseBody:     largeBody,
+			wantCodecName:    mo.None[string](),
+			wantResponseBody: "",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrDecompressedSizeLimitIsExceeded)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			handler := NewCompressionMiddleware(options)(
+				tcpServer.RequestHandlerFunc[
+					defaultProtocolModels.Request,
+					defaultProtocolModels.Response,
+				](func(
+					ctx context.Context,
+					request defaultProtocolModels.Request,
+				) (defaultProtocolModels.Response, error) {
+					assert.False(test, IsCompressedRequest(request))
+					assert.Equal(
+						test,
+						largeBody,
+						string(request.Body().OrEmpty().ToBytes()),
+					)
+
+					return newTestResponse(test, "dummy", data.responseBody), nil
+				}),
+			)
+
+			response, err :=
+				handler.HandleRequest(data.args.ctx, data.args.request(test))
+
+			gotCodecName := headerValue(response.Headers(), CompressionHeaderKey)
+			assert.Equal(test, data.wantCodecName, mo.TupleToOption(
+				string(gotCodecName.OrEmpty()),
+				gotCodecName.IsPresent(),
+			))
+			if err == nil {
+				response, err = DecompressResponse(response, options)
+				require.NoError(test, err)
+
+				assert.Equal(
+					test,
+					data.wantResponseBody,
+					string(response.Body().OrEmpty().ToBytes()),
+				)
+			}
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestCompressingClientProtocol(test *testing.T) {
+	largeBody := strings.Repeat("data", 100)
+	codec := tcpServer.NewGzipCodec(tcpServer.CompressionCodecOptions{})
+
+	for _, data := range []struct {
+		name             string
+		requestBody      string
+		wantIsCompressed bool
+	}{
+		{
+			name:             "with the large body",
+			requestBody:      largeBody,
+			wantIsCompressed: true,
+		},
+		{
+			name:             "with the small body",
+			requestBody:      "small",
+			wantIsCompressed: false,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			baseProtocol := &testClientProtocol{
+				response: newTestCompressedResponse(test, codec, data.requestBody),
+			}
+			protocol := NewCompressingClientProtocol(
+				baseProtocol,
+				CompressionOptions{
+					Codecs:    []tcpServer.CompressionCodec{codec},
+					Threshold: mo.Some(100),
+				},
+			)
+
+			_, err := protocol.MarshalRequest(
+				newTestRequest(test, "dummy", data.requestBody),
+			)
+			require.NoError(test, err)
+
+			assert.Equal(
+				test,
+				data.wantIsCompressed,
+				IsCompressedRequest(baseProtocol.request),
+			)
+
+			response, err := protocol.ParseResponse(nil)
+			require.NoError(test, err)
+
+			assert.False(test, IsCompressedResponse(response))
+			assert.Equal(
+				test,
+				data.requestBody,
+				string(response.Body().OrEmpty().ToBytes()),
+			)
+		})
+	}
+}
+
+type testClientProtocol struct {
+	tcpServer.ClientProtocol[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	]
+
+	request  defaultProtocolModels.Request
+	response defaultProtocolModels.Response
+}
+
+func (protocol *testClientProtocol) MarshalRequest(
+	request defaultProtocolModels.Request,
+) ([]byte, error) {
+	protocol.request = request
+	return nil, nil
+}
+
+func (protocol *testClientProtocol) ParseResponse(
+	data []byte,
+) (defaultProtocolModels.Response, error) {
+	return protocol.response, nil
+}
+
+func newTestCompressedRequest(
+	test *testing.T,
+	codec tcpServer.CompressionCodec,
+	body string,
+) defaultProtocolModels.Request {
+	request, err := rebuildRequest(
+		newTestRequest(test, "dummy", ""),
+		mo.Some(addHeader(
+			mo.None[defaultProtocolModelValueTypes.Headers](),
+			CompressionHeaderKey,
+			codec.Name(),
+		)),
+		defaultProtocolModelValueTypes.NewBody(compressTestBody(test, codec, body)),
+	)
+	require.NoError(test, err)
+
+	return request
+}
+
+func newTestCompressedResponse(
+	test *testing.T,
+	codec tcpServer.CompressionCodec,
+	body string,
+) defaultProtocolModels.Response {
+	response, err := rebuildResponse(
+		newTestResponse(test, "dummy", ""),
+		mo.Some(addHeader(
+			mo.None[defaultProtocolModelValueTypes.Headers](),
+			CompressionHeaderKey,
+			codec.Name(),
+		)),
+		defaultProtocolModelValueTypes.NewBody(compressTestBody(test, codec, body)),
+	)
+	require.NoError(test, err)
+
+	return response
+}
+
+func compressTestBody(
+	test *testing.T,
+	codec tcpServer.CompressionCodec,
+	body string,
+) []byte {
+	var buffer bytes.Buffer
+	writer, err := codec.NewWriter(&buffer)
+	require.NoError(test, err)
+
+	_, err = writer.Write([]byte(body))
+	require.NoError(test, err)
+	require.NoError(test, writer.Close())
+
+	return buffer.Bytes()
+}
@@ -0,0 +1,130 @@
+package defaultProtocol
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/samber/mo"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+func hasHeader(
+	headers mo.Option[defaultProtocolModelValueTypes.Headers],
+	key string,
+) bool {
+	return headerValue(headers, key).IsPresent()
+}
+
+func headerValue(
+	headers mo.Option[defaultProtocolModelValueTypes.Headers],
+	key string,
+) mo.Option[[]byte] {
+	value, isPresent := headers.OrEmpty().ToMap()[defaultProtocolModelValueTypes.
+		MustNewHeaderKey([]byte(key))]
+	if !isPresent {
+		return mo.None[[]byte]()
+	}
+
+	return mo.Some(value.ToBytes())
+}
+
+func addHeader(
+	headers mo.Option[defaultProtocolModelValueTypes.Headers],
+	key string,
+	value string,
+) defaultProtocolModelValueTypes.Headers {
+	rawHeaders :=
+		map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue{} //nolint:lll
+	maps.Copy(rawHeaders, headers.OrEmpty().ToMap())
+	rawHeaders[defaultProtocolModelValueTypes.MustNewHeaderKey([]byte(key))] =
+		defaultProtocolModelValueTypes.MustNewHeaderValue([]byte(value))
+
+	return defaultProtocolModelValueTypes.NewHeaders(rawHeaders)
+}
+
+func removeHeader(
+	headers mo.Option[defaultProtocolModelValueTypes.Headers],
+	key string,
+) mo.Option[defaultProtocolModelValueTypes.Headers] {
+	rawHeaders := maps.Clone(headers.OrEmpty().ToMap())
+	delete(
+		rawHeaders,
+		defaultProtocolModelValueTypes.MustNewHeaderKey([]byte(key)),
+	)
+	if len(rawHeaders) == 0 {
+		return mo.None[defaultProtocolModelValueTypes.Headers]()
+	}
+
+	return mo.Some(defaultProtocolModelValueTypes.NewHeaders(rawHeaders))
+}
+
+func addRequestHeader(
+	request defaultProtocolModels.Request,
+	key string,
+	value string,
+) (defaultProtocolModels.Request, error) {
+	return rebuildRequest(
+		request,
+		mo.Some(addHeader(request.Headers(), key, value)),
+		request.Body().OrEmpty(),
+	)
+}
+
+func addResponseHeader(
+	response defaultProtocolModels.Response,
+	key string,
+	value string,
+) (defaultProtocolModels.Response, error) {
+	return rebuildResponse(
+		response,
+		mo.Some(addHeader(response.Headers(), key, value)),
+		response.Body().OrEmpty(),
+	)
+}
+
+func rebuildRequest(
+	request defaultProtocolModels.Request,
+	headers mo.Option[defaultProtocolModelValueTypes.Headers],
+	body defaultProtocolModelValueTypes.Body,
+) (defaultProtocolModels.Request, error) {
+	requestBuilder := defaultProtocolModels.NewRequestBuilder().
+		SetAction(request.Action()).
+		SetBody(body)
+	if headers, isPresent := headers.Get(); isPresent {
+		requestBuilder.SetHeaders(headers)
+	}
+
+	modifiedRequest, err := requestBuilder.Build()
+	if err != nil {
+		return defaultProtocolModels.Request{}, fmt.Errorf(
+			"unable to build the request: %w",
+			err,
+		)
+	}
+
+	return modifiedRequest, nil
+}
+
+func rebuildResponse(
+	response defaultProtocolModels.Response,
+	headers mo.Option[defaultProtocolModelValueTypes.Headers],
+	body defaultProtocolModelValueTypes.Body,
+) (defaultProtocolModels.Response, error) {
+	responseBuilder := defaultProtocolModels.NewResponseBuilder().
+		SetStatus(response.Status()).
+		SetBody(body)
+	if headers, isPresent := headers.Get(); isPresent {
+		responseBuilder.SetHeaders(headers)
+	}
+
+	modifiedResponse, err := responseBuilder.Build()
+	if err != nil {
+		return defaultProtocolModels.Response{}, fmt.Errorf(
+			"unable to build the response: %w",
+			err,
+		)
+	}
+
+	return modifiedResponse, nil
+}
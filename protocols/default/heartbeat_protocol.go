@@ -0,0 +1,82 @@
+package defaultProtocol
+
+import (
+	"bytes"
+	"fmt"
+
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+const (
+	PingAction = "__ping__"
+	PongStatus = "__pong__"
+)
+
+type HeartbeatProtocol struct{}
+
+func NewHeartbeatProtocol() HeartbeatProtocol {
+	return HeartbeatProtocol{}
+}
+
+func (protocol HeartbeatProtocol) NewPingRequest() (
+	defaultProtocolModels.Request,
+	error,
+) {
+	action, err := defaultProtocolModelValueTypes.NewAction([]byte(PingAction))
+	if err != nil {
+		return defaultProtocolModels.Request{}, fmt.Errorf(
+			"unable to construct the action: %w",
+			err,
+		)
+	}
+
+	request, err := defaultProtocolModels.NewRequestBuilder().
+		SetAction(action).
+		Build()
+	if err != nil {
+		return defaultProtocolModels.Request{}, fmt.Errorf(
+			"unable to build the request: %w",
+			err,
+		)
+	}
+
+	return request, nil
+}
+
+func (protocol HeartbeatProtocol) IsPingRequest(
+	request defaultProtocolModels.Request,
+) bool {
+	return bytes.Equal(request.Action().ToBytes(), []byte(PingAction))
+}
+
+func (protocol HeartbeatProtocol) NewPongResponse() (
+	defaultProtocolModels.Response,
+	error,
+) {
+	status, err := defaultProtocolModelValueTypes.NewStatus([]byte(PongStatus))
+	if err != nil {
+		return defaultProtocolModels.Response{}, fmt.Errorf(
+			"unable to construct the status: %w",
+			err,
+		)
+	}
+
+	response, err := defaultProtocolModels.NewResponseBuilder().
+		SetStatus(status).
+		Build()
+	if err != nil {
+		return defaultProtocolModels.Response{}, fmt.Errorf(
+			"unable to build the response: %w",
+			err,
+		)
+	}
+
+	return response, nil
+}
+
+func (protocol HeartbeatProtocol) IsPongResponse(
+	response defaultProtocolModels.Response,
+) bool {
+	return bytes.Equal(response.Status().ToBytes(), []byte(PongStatus))
+}
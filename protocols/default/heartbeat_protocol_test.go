@@ -0,0 +1,62 @@
+package defaultProtocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+func TestHeartbeatProtocol_interface(test *testing.T) {
+	assert.Implements(
+		test,
+		(*tcpServer.HeartbeatProtocol[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		])(nil),
+		HeartbeatProtocol{},
+	)
+}
+
+func TestHeartbeatProtocol_ping(test *testing.T) {
+	protocol := NewHeartbeatProtocol()
+
+	pingRequest, err := protocol.NewPingRequest()
+	require.NoError(test, err)
+
+	assert.Equal(test, []byte(PingAction), pingRequest.Action().ToBytes())
+	assert.True(test, protocol.IsPingRequest(pingRequest))
+
+	action, err := defaultProtocolModelValueTypes.NewAction([]byte("action"))
+	require.NoError(test, err)
+
+	otherRequest, err := defaultProtocolModels.NewRequestBuilder().
+		SetAction(action).
+		Build()
+	require.NoError(test, err)
+
+	assert.False(test, protocol.IsPingRequest(otherRequest))
+}
+
+func TestHeartbeatProtocol_pong(test *testing.T) {
+	protocol := NewHeartbeatProtocol()
+
+	pongResponse, err := protocol.NewPongResponse()
+	require.NoError(test, err)
+
+	assert.Equal(test, []byte(PongStatus), pongResponse.Status().ToBytes())
+	assert.True(test, protocol.IsPongResponse(pongResponse))
+
+	status, err := defaultProtocolModelValueTypes.NewStatus([]byte("status"))
+	require.NoError(test, err)
+
+	otherResponse, err := defaultProtocolModels.NewResponseBuilder().
+		SetStatus(status).
+		Build()
+	require.NoError(test, err)
+
+	assert.False(test, protocol.IsPongResponse(otherResponse))
+}
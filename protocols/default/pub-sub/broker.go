@@ -0,0 +1,330 @@
+package defaultProtocolPubSub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/samber/mo"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+const (
+	DefaultQueueSize = 64
+)
+
+type SlowConsumerPolicy string
+
+const (
+	// DropMessagePolicy drops the messages that don't fit in the queue
+	DropMessagePolicy SlowConsumerPolicy = "drop-message"
+	// DisconnectPolicy closes the connection of the subscriber,
+	// whose queue is full
+	DisconnectPolicy SlowConsumerPolicy = "disconnect"
+)
+
+type BrokerOptions struct {
+	QueueSize          mo.Option[int]
+	SlowConsumerPolicy mo.Option[SlowConsumerPolicy]
+}
+
+type BrokerMetrics struct {
+	DroppedMessageCount         int64
+	DisconnectedSubscriberCount int64
+}
+
+type session = tcpServer.Session[
+	defaultProtocolModels.Request,
+	defaultProtocolModels.Response,
+]
+
+// Broker delivers the published messages to the subscribed connections
+// via the session pushes, so the session protocol is required
+type Broker struct {
+	options                     BrokerOptions
+	lock                        sync.Mutex
+	subscribers                 map[*session]*subscriber
+	droppedMessageCount         atomic.Int64
+	disconnectedSubscriberCount atomic.Int64
+}
+
+func NewBroker(options BrokerOptions) *Broker {
+	return &Broker{
+		options:     options,
+		subscribers: make(map[*session]*subscriber),
+	}
+}
+
+func (broker *Broker) Metrics() BrokerMetrics {
+	return BrokerMetrics{
+		DroppedMessageCount:         broker.droppedMessageCount.Load(),
+		DisconnectedSubscriberCount: broker.disconnectedSubscriberCount.Load(),
+	}
+}
+
+// Publish returns the number of the subscribers the message is queued for
+func (broker *Broker) Publish(message Message) (int, error) {
+	if err := ValidateTopic(message.Topic); err != nil {
+		return 0, fmt.Errorf("invalid topic: %w", err)
+	}
+
+	headers, err := newTopicHeaders(message.Topic)
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := newResponse(MessageStatus, mo.Some(headers), message.Body)
+	if err != nil {
+		return 0, fmt.Errorf("unable to construct the message: %w", err)
+	}
+
+	broker.lock.Lock()
+	defer broker.lock.Unlock()
+
+	queuedCount := 0
+	for _, subscriber := range broker.subscribers {
+		if !subscriber.isMatched(message.Topic) {
+			continue
+		}
+
+		select {
+		case subscriber.queue <- response:
+			queuedCount++
+			continue
+
+		default:
+		}
+
+		broker.droppedMessageCount.Add(1)
+		if broker.options.SlowConsumerPolicy.OrElse(DropMessagePolicy) ==
+			DisconnectPolicy {
+			broker.removeSubscriberWithoutLock(subscriber)
+			broker.disconnectedSubscriberCount.Add(1)
+
+			subscriber.session.Close() //nolint:errcheck
+		}
+	}
+
+	return queuedCount, nil
+}
+
+func (broker *Broker) Middleware() tcpServer.RequestMiddleware[
+	defaultProtocolModels.Request,
+	defaultProtocolModels.Response,
+] {
+	return func(
+		handler tcpServer.RequestHandler[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		],
+	) tcpServer.RequestHandler[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	] {
+		return tcpServer.RequestHandlerFunc[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		](func(
+			ctx context.Context,
+			request defaultProtocolModels.Request,
+		) (defaultProtocolModels.Response, error) {
+			var handlingErr error
+			switch string(request.Action().ToBytes()) {
+			case SubscribeAction:
+				handlingErr = broker.handleSubscription(ctx, request)
+			case UnsubscribeAction:
+				handlingErr = broker.handleUnsubscription(ctx, request)
+			case PublishAction:
+				handlingErr = broker.handlePublication(request)
+			default:
+				return handler.HandleRequest(ctx, request)
+			}
+			if handlingErr != nil {
+				return newResponse(
+					ErrorStatus,
+					mo.None[defaultProtocolModelValueTypes.Headers](),
+					[]byte(handlingErr.Error()),
+				)
+			}
+
+			return newResponse(
+				OKStatus,
+				mo.None[defaultProtocolModelValueTypes.Headers](),
+				nil,
+			)
+		})
+	}
+}
+
+func (broker *Broker) handleSubscription(
+	ctx context.Context,
+	request defaultProtocolModels.Request,
+) error {
+	pattern, err := patternFromRequest(request)
+	if err != nil {
+		return err
+	}
+
+	session, isPresent := tcpServer.SessionFromContext[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	](ctx)
+	if !isPresent {
+		return errors.New("session is missed")
+	}
+	// otherwise, the messages would be silently lost
+	if !session.IsPushingAvailable() {
+		return fmt.Errorf(
+			"unable to deliver the messages: %w",
+			tcpServer.ErrSessionProtocolIsMissed,
+		)
+	}
+
+	broker.lock.Lock()
+	defer broker.lock.Unlock()
+
+	currentSubscriber, isPresent := broker.subscribers[session]
+	if !isPresent {
+		currentSubscriber = newSubscriber(
+			session,
+			broker.options.QueueSize.OrElse(DefaultQueueSize),
+		)
+		broker.subscribers[session] = currentSubscriber
+
+		go broker.runSubscriber(currentSubscriber)
+	}
+
+	currentSubscriber.patterns[pattern] = struct{}{}
+	return nil
+}
+
+func (broker *Broker) handleUnsubscription(
+	ctx context.Context,
+	request defaultProtocolModels.Request,
+) error {
+	pattern, err := patternFromRequest(request)
+	if err != nil {
+		return err
+	}
+
+	session, isPresent := tcpServer.SessionFromContext[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	](ctx)
+	if !isPresent {
+		return errors.New("session is missed")
+	}
+
+	broker.lock.Lock()
+	defer broker.lock.Unlock()
+
+	currentSubscriber, isPresent := broker.subscribers[session]
+	if !isPresent {
+		return nil
+	}
+
+	delete(currentSubscriber.patterns, pattern)
+	if len(currentSubscriber.patterns) == 0 {
+		broker.removeSubscriberWithoutLock(currentSubscriber)
+	}
+
+	return nil
+}
+
+func (broker *Broker) handlePublication(
+	request defaultProtocolModels.Request,
+) error {
+	topic, isPresent := topicFromHeaders(request.Headers()).Get()
+	if !isPresent {
+		return errors.New("topic is missed")
+	}
+
+	if _, err := broker.Publish(Message{
+		Topic: topic,
+		Body:  request.Body().OrEmpty().ToBytes(),
+	}); err != nil {
+		return fmt.Errorf("unable to publish the message: %w", err)
+	}
+
+	return nil
+}
+
+func (broker *Broker) runSubscriber(subscriber *subscriber) {
+	for {
+		select {
+		case message := <-subscriber.queue:
+			if err := subscriber.session.Push(message); err != nil {
+				broker.removeSubscriber(subscriber)
+				return
+			}
+
+		case <-subscriber.session.Done():
+			broker.removeSubscriber(subscriber)
+			return
+
+		case <-subscriber.removed:
+			return
+		}
+	}
+}
+
+func (broker *Broker) removeSubscriber(subscriber *subscriber) {
+	broker.lock.Lock()
+	defer broker.lock.Unlock()
+
+	broker.removeSubscriberWithoutLock(subscriber)
+}
+
+func (broker *Broker) removeSubscriberWithoutLock(subscriber *subscriber) {
+	if broker.subscribers[subscriber.session] != subscriber {
+		return
+	}
+
+	delete(broker.subscribers, subscriber.session)
+	close(subscriber.removed)
+}
+
+type subscriber struct {
+	session  *session
+	patterns map[string]struct{}
+	queue    chan defaultProtocolModels.Response
+	removed  chan struct{}
+}
+
+func newSubscriber(session *session, queueSize int) *subscriber {
+	return &subscriber{
+		session:  session,
+		patterns: make(map[string]struct{}),
+		queue:    make(chan defaultProtocolModels.Response, queueSize),
+		removed:  make(chan struct{}),
+	}
+}
+
+func (subscriber *subscriber) isMatched(topic string) bool {
+	for pattern := range subscriber.patterns {
+		if MatchTopic(pattern, topic) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func patternFromRequest(
+	request defaultProtocolModels.Request,
+) (string, error) {
+	pattern, isPresent := topicFromHeaders(request.Headers()).Get()
+	if !isPresent {
+		return "", errors.New("topic pattern is missed")
+	}
+
+	if err := ValidateTopicPattern(pattern); err != nil {
+		return "", fmt.Errorf("invalid topic pattern: %w", err)
+	}
+
+	return pattern, nil
+}
@@ -0,0 +1,293 @@
+package defaultProtocolPubSub
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocol "github.com/thewizardplusplus/go-tcp-server/protocols/default"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	netstringProtocol "github.com/thewizardplusplus/go-tcp-server/protocols/netstring"
+)
+
+func TestBroker(test *testing.T) {
+	broker := NewBroker(BrokerOptions{})
+
+	firstSubscriber := NewSubscriber(SubscriberOptions{})
+	firstClient := newTestSessionClient(test, broker, firstSubscriber)
+	require.NoError(test, Subscribe(firstClient, "news/+/sport"))
+	require.NoError(test, Subscribe(firstClient, "weather/#"))
+
+	secondSubscriber := NewSubscriber(SubscriberOptions{})
+	secondClient := newTestSessionClient(test, broker, secondSubscriber)
+	require.NoError(test, Subscribe(secondClient, "news/#"))
+
+	for _, message := range []Message{
+		{Topic: "news/local/sport", Body: []byte("one")},
+		{Topic: "news/local/politics", Body: []byte("two")},
+		{Topic: "weather", Body: []byte("three")},
+	} {
+		require.NoError(test, Publish(secondClient, message))
+	}
+
+	assert.Equal(
+		test,
+		[]Message{
+			{Topic: "news/local/sport", Body: []byte("one")},
+			{Topic: "weather", Body: []byte("three")},
+		},
+		receiveTestMessages(test, firstSubscriber, 2),
+	)
+	assert.Equal(
+		test,
+		[]Message{
+			{Topic: "news/local/sport", Body: []byte("one")},
+			{Topic: "news/local/politics", Body: []byte("two")},
+		},
+		receiveTestMessages(test, secondSubscriber, 2),
+	)
+
+	require.NoError(test, Unsubscribe(firstClient, "news/+/sport"))
+	require.NoError(test, Unsubscribe(firstClient, "weather/#"))
+
+	queuedCount, err := broker.Publish(Message{
+		Topic: "news/local/sport",
+		Body:  []byte("four"),
+	})
+	require.NoError(test, err)
+	assert.Equal(test, 1, queuedCount)
+
+	assert.Equal(
+		test,
+		[]Message{{Topic: "news/local/sport", Body: []byte("four")}},
+		receiveTestMessages(test, secondSubscriber, 1),
+	)
+	assert.Empty(test, firstSubscriber.Messages())
+	assert.Equal(test, BrokerMetrics{}, broker.Metrics())
+}
+
+func TestBroker_withErrors(test *testing.T) {
+	broker := NewBroker(BrokerOptions{})
+	subscriber := NewSubscriber(SubscriberOptions{})
+	client := newTestSessionClient(test, broker, subscriber)
+
+	for _, data := range []struct {
+		name      string
+		rawAction string
+		topic     string
+		wantErr   string
+	}{
+		{
+			name:      "subscription/invalid topic pattern",
+			rawAction: SubscribeAction,
+			topic:     "news/#/sport",
+			wantErr:   "invalid topic pattern",
+		},
+		{
+			name:      "unsubscription/invalid topic pattern",
+			rawAction: UnsubscribeAction,
+			topic:     "news/spo+rt",
+			wantErr:   "invalid topic pattern",
+		},
+		{
+			name:      "publication/topic with wildcards",
+			rawAction: PublishAction,
+			topic:     "news/+",
+			wantErr:   "invalid topic",
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			err := sendRequest(client, data.rawAction, data.topic, nil)
+
+			assert.ErrorContains(test, err, data.wantErr)
+		})
+	}
+}
+
+func TestBroker_withoutSessionProtocol(test *testing.T) {
+	broker := NewBroker(BrokerOptions{})
+	client := tcpServer.NewTCPClientFromConnection(
+		newTestServerConnection(test, broker, false),
+		tcpServer.TCPClientOptions[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		]{
+			ClientProtocol: netstringProtocol.NewProtocol(),
+		},
+	)
+	test.Cleanup(func() { client.Close() }) //nolint:errcheck
+
+	err := Subscribe(client, "news")
+	assert.ErrorContains(test, err, tcpServer.ErrSessionProtocolIsMissed.Error())
+
+	queuedCount, err := broker.Publish(Message{Topic: "news"})
+	require.NoError(test, err)
+
+	assert.Equal(test, 0, queuedCount)
+}
+
+func TestBroker_withSlowConsumer(test *testing.T) {
+	for _, data := range []struct {
+		name        string
+		options     BrokerOptions
+		wantMetrics func(test *testing.T, metrics BrokerMetrics)
+	}{
+		{
+			name: "success/drop the messages",
+			options: BrokerOptions{
+				QueueSize:          mo.Some(1),
+				SlowConsumerPolicy: mo.Some(DropMessagePolicy),
+			},
+			wantMetrics: func(test *testing.T, metrics BrokerMetrics) {
+				assert.GreaterOrEqual(test, metrics.DroppedMessageCount, int64(8))
+				assert.Equal(test, int64(0), metrics.DisconnectedSubscriberCount)
+			},
+		},
+		{
+			name: "success/disconnect the subscriber",
+			options: BrokerOptions{
+				QueueSize:          mo.Some(1),
+				SlowConsumerPolicy: mo.Some(DisconnectPolicy),
+			},
+			wantMetrics: func(test *testing.T, metrics BrokerMetrics) {
+				assert.Equal(test, int64(1), metrics.DroppedMessageCount)
+				assert.Equal(test, int64(1), metrics.DisconnectedSubscriberCount)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			broker := NewBroker(data.options)
+
+			// without the session, the client doesn't read the pushes
+			client := tcpServer.NewTCPClientFromConnection(
+				newTestServerConnection(test, broker, true),
+				tcpServer.TCPClientOptions[
+					defaultProtocolModels.Request,
+					defaultProtocolModels.Response,
+				]{
+					ClientProtocol: netstringProtocol.NewProtocol(),
+				},
+			)
+			test.Cleanup(func() { client.Close() }) //nolint:errcheck
+
+			require.NoError(test, Subscribe(client, "news"))
+
+			for range 10 {
+				_, err := broker.Publish(Message{Topic: "news", Body: []byte("body")})
+				require.NoError(test, err)
+			}
+
+			data.wantMetrics(test, broker.Metrics())
+		})
+	}
+}
+
+func newTestSessionClient(
+	test *testing.T,
+	broker *Broker,
+	subscriber *Subscriber,
+) tcpServer.TCPClient[
+	defaultProtocolModels.Request,
+	defaultProtocolModels.Response,
+] {
+	client := tcpServer.NewTCPClientFromConnection(
+		newTestServerConnection(test, broker, true),
+		tcpServer.TCPClientOptions[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		]{
+			Session: mo.Some(tcpServer.ClientSessionOptions[
+				defaultProtocolModels.Request,
+				defaultProtocolModels.Response,
+			]{
+				Protocol:    defaultProtocol.NewSessionProtocol(),
+				PushHandler: mo.Some(subscriber.PushHandler()),
+			}),
+			ClientProtocol: netstringProtocol.NewProtocol(),
+		},
+	)
+	test.Cleanup(func() { client.Close() }) //nolint:errcheck
+
+	return client
+}
+
+func newTestServerConnection(
+	test *testing.T,
+	broker *Broker,
+	isSessionProtocolUsed bool,
+) net.Conn {
+	serverConnection, clientConnection := net.Pipe()
+
+	var sessionProtocol mo.Option[tcpServer.SessionProtocol[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	]]
+	if isSessionProtocolUsed {
+		sessionProtocol = mo.Some[tcpServer.SessionProtocol[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		]](defaultProtocol.NewSessionProtocol())
+	}
+
+	handler := tcpServer.NewDefaultConnectionHandler(
+		tcpServer.DefaultConnectionHandlerOptions[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		]{
+			SessionProtocol: sessionProtocol,
+			ServerProtocol:  netstringProtocol.NewProtocol(),
+			RequestHandler: broker.Middleware()(
+				tcpServer.RequestHandlerFunc[
+					defaultProtocolModels.Request,
+					defaultProtocolModels.Response,
+				](func(
+					ctx context.Context,
+					request defaultProtocolModels.Request,
+				) (defaultProtocolModels.Response, error) {
+					return newResponse(ErrorStatus, request.Headers(), []byte("unknown"))
+				}),
+			),
+		},
+	)
+
+	handlingDone := make(chan struct{})
+	go func() {
+		defer close(handlingDone)
+		defer serverConnection.Close()
+
+		handler.HandleConnection( //nolint:errcheck
+			context.Background(),
+			serverConnection,
+		)
+	}()
+	test.Cleanup(func() {
+		clientConnection.Close() //nolint:errcheck
+		<-handlingDone
+	})
+
+	return clientConnection
+}
+
+func receiveTestMessages(
+	test *testing.T,
+	subscriber *Subscriber,
+	count int,
+) []Message {
+	var messages []Message
+	for range count {
+		select {
+		case message := <-subscriber.Messages():
+			messages = append(messages, message)
+
+		case <-time.After(time.Second):
+			require.FailNow(test, "messages aren't received in time")
+		}
+	}
+
+	return messages
+}
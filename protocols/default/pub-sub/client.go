@@ -0,0 +1,127 @@
+package defaultProtocolPubSub
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/samber/mo"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+)
+
+const (
+	DefaultBufferSize = 64
+)
+
+type RequestSender interface {
+	SendRequest(
+		request defaultProtocolModels.Request,
+	) (defaultProtocolModels.Response, error)
+}
+
+func Subscribe(sender RequestSender, pattern string) error {
+	if err := ValidateTopicPattern(pattern); err != nil {
+		return fmt.Errorf("invalid topic pattern: %w", err)
+	}
+
+	return sendRequest(sender, SubscribeAction, pattern, nil)
+}
+
+func Unsubscribe(sender RequestSender, pattern string) error {
+	if err := ValidateTopicPattern(pattern); err != nil {
+		return fmt.Errorf("invalid topic pattern: %w", err)
+	}
+
+	return sendRequest(sender, UnsubscribeAction, pattern, nil)
+}
+
+func Publish(sender RequestSender, message Message) error {
+	if err := ValidateTopic(message.Topic); err != nil {
+		return fmt.Errorf("invalid topic: %w", err)
+	}
+
+	return sendRequest(sender, PublishAction, message.Topic, message.Body)
+}
+
+type SubscriberOptions struct {
+	BufferSize mo.Option[int]
+}
+
+// Subscriber receives the messages pushed by the broker;
+// its push handler should be passed to the client session options
+type Subscriber struct {
+	messages            chan Message
+	droppedMessageCount atomic.Int64
+}
+
+func NewSubscriber(options SubscriberOptions) *Subscriber {
+	return &Subscriber{
+		messages: make(chan Message, options.BufferSize.OrElse(DefaultBufferSize)),
+	}
+}
+
+func (subscriber *Subscriber) Messages() <-chan Message {
+	return subscriber.messages
+}
+
+func (subscriber *Subscriber) DroppedMessageCount() int64 {
+	return subscriber.droppedMessageCount.Load()
+}
+
+// PushHandler ignores the pushes that aren't messages
+// and drops the messages that don't fit in the buffer,
+// so it never blocks the reading of the connection
+func (subscriber *Subscriber) PushHandler() (
+	pushHandler tcpServer.PushHandler[defaultProtocolModels.Response],
+) {
+	return func(push defaultProtocolModels.Response) {
+		if string(push.Status().ToBytes()) != MessageStatus {
+			return
+		}
+
+		topic, isPresent := topicFromHeaders(push.Headers()).Get()
+		if !isPresent {
+			return
+		}
+
+		message := Message{
+			Topic: topic,
+			Body:  push.Body().OrEmpty().ToBytes(),
+		}
+		select {
+		case subscriber.messages <- message:
+		default:
+			subscriber.droppedMessageCount.Add(1)
+		}
+	}
+}
+
+func sendRequest(
+	sender RequestSender,
+	rawAction string,
+	topic string,
+	body []byte,
+) error {
+	request, err := newRequest(rawAction, topic, body)
+	if err != nil {
+		return fmt.Errorf("unable to construct the request: %w", err)
+	}
+
+	response, err := sender.SendRequest(request)
+	if err != nil {
+		return fmt.Errorf("unable to send the request: %w", err)
+	}
+
+	switch string(response.Status().ToBytes()) {
+	case OKStatus:
+		return nil
+	case ErrorStatus:
+		return errors.New(string(response.Body().OrEmpty().ToBytes()))
+	default:
+		return fmt.Errorf(
+			"unexpected response status %q",
+			response.Status().ToBytes(),
+		)
+	}
+}
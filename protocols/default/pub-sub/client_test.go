@@ -0,0 +1,110 @@
+package defaultProtocolPubSub
+
+import (
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+func TestSubscriber_PushHandler(test *testing.T) {
+	for _, data := range []struct {
+		name             string
+		options          SubscriberOptions
+		pushes           func(test *testing.T) []defaultProtocolModels.Response
+		wantMessages     []Message
+		wantDroppedCount int64
+	}{
+		{
+			name:    "success/messages",
+			options: SubscriberOptions{},
+			pushes: func(test *testing.T) []defaultProtocolModels.Response {
+				return []defaultProtocolModels.Response{
+					newTestMessage(test, "news", "one"),
+					newTestMessage(test, "weather", "two"),
+				}
+			},
+			wantMessages: []Message{
+				{Topic: "news", Body: []byte("one")},
+				{Topic: "weather", Body: []byte("two")},
+			},
+			wantDroppedCount: 0,
+		},
+		{
+			name:    "success/other pushes",
+			options: SubscriberOptions{},
+			pushes: func(test *testing.T) []defaultProtocolModels.Response {
+				response, err := newResponse(
+					OKStatus,
+					mo.None[defaultProtocolModelValueTypes.Headers](),
+					[]byte("one"),
+				)
+				require.NoError(test, err)
+
+				messageWithoutTopic, err := newResponse(
+					MessageStatus,
+					mo.None[defaultProtocolModelValueTypes.Headers](),
+					[]byte("two"),
+				)
+				require.NoError(test, err)
+
+				return []defaultProtocolModels.Response{response, messageWithoutTopic}
+			},
+			wantMessages:     nil,
+			wantDroppedCount: 0,
+		},
+		{
+			name: "success/full buffer",
+			options: SubscriberOptions{
+				BufferSize: mo.Some(1),
+			},
+			pushes: func(test *testing.T) []defaultProtocolModels.Response {
+				return []defaultProtocolModels.Response{
+					newTestMessage(test, "news", "one"),
+					newTestMessage(test, "news", "two"),
+					newTestMessage(test, "news", "three"),
+				}
+			},
+			wantMessages:     []Message{{Topic: "news", Body: []byte("one")}},
+			wantDroppedCount: 2,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			subscriber := NewSubscriber(data.options)
+
+			pushHandler := subscriber.PushHandler()
+			for _, push := range data.pushes(test) {
+				pushHandler(push)
+			}
+
+			var gotMessages []Message
+			for len(subscriber.Messages()) > 0 {
+				gotMessages = append(gotMessages, <-subscriber.Messages())
+			}
+
+			assert.Equal(test, data.wantMessages, gotMessages)
+			assert.Equal(
+				test,
+				data.wantDroppedCount,
+				subscriber.DroppedMessageCount(),
+			)
+		})
+	}
+}
+
+func newTestMessage(
+	test *testing.T,
+	topic string,
+	body string,
+) defaultProtocolModels.Response {
+	headers, err := newTopicHeaders(topic)
+	require.NoError(test, err)
+
+	message, err := newResponse(MessageStatus, mo.Some(headers), []byte(body))
+	require.NoError(test, err)
+
+	return message
+}
@@ -0,0 +1,120 @@
+package defaultProtocolPubSub
+
+import (
+	"fmt"
+
+	"github.com/samber/mo"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+const (
+	SubscribeAction   = "__subscribe__"
+	UnsubscribeAction = "__unsubscribe__"
+	PublishAction     = "__publish__"
+	OKStatus          = "__ok__"
+	ErrorStatus       = "__error__"
+	MessageStatus     = "__message__"
+	TopicHeaderKey    = "__topic__"
+)
+
+type Message struct {
+	Topic string
+	Body  []byte
+}
+
+func topicFromHeaders(
+	headers mo.Option[defaultProtocolModelValueTypes.Headers],
+) mo.Option[string] {
+	topic, isPresent := headers.OrEmpty().ToMap()[defaultProtocolModelValueTypes.
+		MustNewHeaderKey([]byte(TopicHeaderKey))]
+	if !isPresent {
+		return mo.None[string]()
+	}
+
+	return mo.Some(string(topic.ToBytes()))
+}
+
+func newTopicHeaders(
+	topic string,
+) (defaultProtocolModelValueTypes.Headers, error) {
+	topicHeaderValue, err :=
+		defaultProtocolModelValueTypes.NewHeaderValue([]byte(topic))
+	if err != nil {
+		return defaultProtocolModelValueTypes.Headers{}, fmt.Errorf(
+			"unable to construct the topic header value: %w",
+			err,
+		)
+	}
+
+	headers := defaultProtocolModelValueTypes.NewHeaders(
+		map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue{ //nolint:lll
+			defaultProtocolModelValueTypes.MustNewHeaderKey([]byte(TopicHeaderKey)): topicHeaderValue, //nolint:lll
+		},
+	)
+	return headers, nil
+}
+
+func newRequest(
+	rawAction string,
+	topic string,
+	body []byte,
+) (defaultProtocolModels.Request, error) {
+	action, err := defaultProtocolModelValueTypes.NewAction([]byte(rawAction))
+	if err != nil {
+		return defaultProtocolModels.Request{}, fmt.Errorf(
+			"unable to construct the action: %w",
+			err,
+		)
+	}
+
+	headers, err := newTopicHeaders(topic)
+	if err != nil {
+		return defaultProtocolModels.Request{}, err
+	}
+
+	request, err := defaultProtocolModels.NewRequestBuilder().
+		SetAction(action).
+		SetHeaders(headers).
+		SetBody(defaultProtocolModelValueTypes.NewBody(body)).
+		Build()
+	if err != nil {
+		return defaultProtocolModels.Request{}, fmt.Errorf(
+			"unable to build the request: %w",
+			err,
+		)
+	}
+
+	return request, nil
+}
+
+func newResponse(
+	rawStatus string,
+	headers mo.Option[defaultProtocolModelValueTypes.Headers],
+	body []byte,
+) (defaultProtocolModels.Response, error) {
+	status, err := defaultProtocolModelValueTypes.NewStatus([]byte(rawStatus))
+	if err != nil {
+		return defaultProtocolModels.Response{}, fmt.Errorf(
+			"unable to construct the status: %w",
+			err,
+		)
+	}
+
+	responseBuilder := defaultProtocolModels.NewResponseBuilder().
+		SetStatus(status).
+		SetBody(defaultProtocolModelValueTypes.NewBody(body))
+	if headers, isPresent := headers.Get(); isPresent {
+		responseBuilder.SetHeaders(headers)
+	}
+
+	response, err := responseBuilder.Build()
+	if err != nil {
+		return defaultProtocolModels.Response{}, fmt.Errorf(
+			"unable to build the response: %w",
+			err,
+		)
+	}
+
+	return response, nil
+}
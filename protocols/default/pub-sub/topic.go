@@ -0,0 +1,78 @@
+package defaultProtocolPubSub
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// the topics consist of levels separated by slashes;
+// in the patterns, a single-level wildcard matches exactly one level
+// and a multi-level wildcard matches the rest of the levels (even none),
+// so it's allowed only as the last level
+
+const (
+	TopicLevelSeparator = "/"
+	SingleLevelWildcard = "+"
+	MultiLevelWildcard  = "#"
+)
+
+func ValidateTopic(topic string) error {
+	if topic == "" {
+		return errors.New("topic cannot be empty")
+	}
+
+	for _, level := range strings.Split(topic, TopicLevelSeparator) {
+		if strings.Contains(level, SingleLevelWildcard) ||
+			strings.Contains(level, MultiLevelWildcard) {
+			return fmt.Errorf("topic level %q contains a wildcard", level)
+		}
+	}
+
+	return nil
+}
+
+func ValidateTopicPattern(pattern string) error {
+	if pattern == "" {
+		return errors.New("topic pattern cannot be empty")
+	}
+
+	levels := strings.Split(pattern, TopicLevelSeparator)
+	for index, level := range levels {
+		switch {
+		case level == MultiLevelWildcard:
+			if index != len(levels)-1 {
+				return errors.New("multi-level wildcard must be the last level")
+			}
+
+		case level == SingleLevelWildcard:
+
+		case strings.Contains(level, SingleLevelWildcard) ||
+			strings.Contains(level, MultiLevelWildcard):
+			return fmt.Errorf("wildcard must occupy the whole level %q", level)
+		}
+	}
+
+	return nil
+}
+
+// MatchTopic expects both arguments to be valid
+func MatchTopic(pattern string, topic string) bool {
+	patternLevels := strings.Split(pattern, TopicLevelSeparator)
+	topicLevels := strings.Split(topic, TopicLevelSeparator)
+	for index, patternLevel := range patternLevels {
+		if patternLevel == MultiLevelWildcard {
+			return true
+		}
+		if index >= len(topicLevels) {
+			return false
+		}
+
+		if patternLevel != SingleLevelWildcard &&
+			patternLevel != topicLevels[index] {
+			return false
+		}
+	}
+
+	return len(patternLevels) == len(topicLevels)
+}
@@ -0,0 +1,189 @@
+package defaultProtocolPubSub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTopic(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		topic   string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "success/single level",
+			topic:   "news",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "success/several levels",
+			topic:   "news/sport/football",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "error/empty topic",
+			topic:   "",
+			wantErr: assert.Error,
+		},
+		{
+			name:    "error/single-level wildcard",
+			topic:   "news/+",
+			wantErr: assert.Error,
+		},
+		{
+			name:    "error/multi-level wildcard",
+			topic:   "news/#",
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			err := ValidateTopic(data.topic)
+
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestValidateTopicPattern(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		pattern string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "success/without wildcards",
+			pattern: "news/sport",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "success/with the single-level wildcards",
+			pattern: "+/sport/+",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "success/with the multi-level wildcard",
+			pattern: "news/#",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "success/with the only multi-level wildcard",
+			pattern: "#",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "error/empty pattern",
+			pattern: "",
+			wantErr: assert.Error,
+		},
+		{
+			name:    "error/multi-level wildcard not in the end",
+			pattern: "news/#/football",
+			wantErr: assert.Error,
+		},
+		{
+			name:    "error/wildcard in the part of the level",
+			pattern: "news/sp+",
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			err := ValidateTopicPattern(data.pattern)
+
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestMatchTopic(test *testing.T) {
+	type args struct {
+		pattern string
+		topic   string
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "success/equal",
+			args: args{
+				pattern: "news/sport",
+				topic:   "news/sport",
+			},
+			want: true,
+		},
+		{
+			name: "success/not equal",
+			args: args{
+				pattern: "news/sport",
+				topic:   "news/weather",
+			},
+			want: false,
+		},
+		{
+			name: "success/single-level wildcard",
+			args: args{
+				pattern: "news/+/today",
+				topic:   "news/sport/today",
+			},
+			want: true,
+		},
+		{
+			name: "success/single-level wildcard with the missed level",
+			args: args{
+				pattern: "news/+",
+				topic:   "news",
+			},
+			want: false,
+		},
+		{
+			name: "success/single-level wildcard with the extra level",
+			args: args{
+				pattern: "news/+",
+				topic:   "news/sport/today",
+			},
+			want: false,
+		},
+		{
+			name: "success/multi-level wildcard",
+			args: args{
+				pattern: "news/#",
+				topic:   "news/sport/today",
+			},
+			want: true,
+		},
+		{
+			name: "success/multi-level wildcard with the parent level",
+			args: args{
+				pattern: "news/#",
+				topic:   "news",
+			},
+			want: true,
+		},
+		{
+			name: "success/only multi-level wildcard",
+			args: args{
+				pattern: "#",
+				topic:   "news/sport",
+			},
+			want: true,
+		},
+		{
+			name: "success/shorter topic",
+			args: args{
+				pattern: "news/sport",
+				topic:   "news",
+			},
+			want: false,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := MatchTopic(data.args.pattern, data.args.topic)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
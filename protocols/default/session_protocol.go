@@ -0,0 +1,87 @@
+package defaultProtocol
+
+import (
+	"strconv"
+
+	"github.com/samber/mo"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+const (
+	PushHeaderKey        = "__push__"
+	PushHeaderValue      = "true"
+	CallIDHeaderKey      = "__call_id__"
+	CallReplyIDHeaderKey = "__call_reply_id__"
+)
+
+type SessionProtocol struct{}
+
+func NewSessionProtocol() SessionProtocol {
+	return SessionProtocol{}
+}
+
+func (protocol SessionProtocol) MarkAsPush(
+	message defaultProtocolModels.Response,
+) (defaultProtocolModels.Response, error) {
+	return addResponseHeader(message, PushHeaderKey, PushHeaderValue)
+}
+
+func (protocol SessionProtocol) IsPush(
+	message defaultProtocolModels.Response,
+) bool {
+	return hasHeader(message.Headers(), PushHeaderKey)
+}
+
+func (protocol SessionProtocol) MarkAsCall(
+	message defaultProtocolModels.Response,
+	callID uint64,
+) (defaultProtocolModels.Response, error) {
+	return addResponseHeader(
+		message,
+		CallIDHeaderKey,
+		strconv.FormatUint(callID, 10),
+	)
+}
+
+func (protocol SessionProtocol) CallID(
+	message defaultProtocolModels.Response,
+) mo.Option[uint64] {
+	return parseCallID(message.Headers(), CallIDHeaderKey)
+}
+
+func (protocol SessionProtocol) MarkAsCallReply(
+	reply defaultProtocolModels.Request,
+	callID uint64,
+) (defaultProtocolModels.Request, error) {
+	return addRequestHeader(
+		reply,
+		CallReplyIDHeaderKey,
+		strconv.FormatUint(callID, 10),
+	)
+}
+
+func (protocol SessionProtocol) CallReplyID(
+	request defaultProtocolModels.Request,
+) mo.Option[uint64] {
+	return parseCallID(request.Headers(), CallReplyIDHeaderKey)
+}
+
+func parseCallID(
+	headers mo.Option[defaultProtocolModelValueTypes.Headers],
+	key string,
+) mo.Option[uint64] {
+	rawCallID, isPresent := headerValue(headers, key).Get()
+	if !isPresent {
+		return mo.None[uint64]()
+	}
+
+	// the invalid identifiers are treated as absent,
+	// so such messages are processed as the regular ones
+	callID, err := strconv.ParseUint(string(rawCallID), 10, 64)
+	if err != nil {
+		return mo.None[uint64]()
+	}
+
+	return mo.Some(callID)
+}
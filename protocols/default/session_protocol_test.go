@@ -0,0 +1,71 @@
+package defaultProtocol
+
+import (
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+)
+
+func TestSessionProtocol_interface(test *testing.T) {
+	assert.Implements(
+		test,
+		(*tcpServer.SessionProtocol[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		])(nil),
+		SessionProtocol{},
+	)
+}
+
+func TestSessionProtocol_push(test *testing.T) {
+	protocol := NewSessionProtocol()
+
+	message := newTestResponse(test, "dummy", "body")
+	assert.False(test, protocol.IsPush(message))
+
+	pushMessage, err := protocol.MarkAsPush(message)
+	require.NoError(test, err)
+
+	assert.True(test, protocol.IsPush(pushMessage))
+	assert.Equal(test, message.Status(), pushMessage.Status())
+	assert.Equal(test, message.Body(), pushMessage.Body())
+	assert.Equal(test, mo.None[uint64](), protocol.CallID(pushMessage))
+}
+
+func TestSessionProtocol_call(test *testing.T) {
+	protocol := NewSessionProtocol()
+
+	message := newTestResponse(test, "dummy", "body")
+	assert.Equal(test, mo.None[uint64](), protocol.CallID(message))
+
+	callMessage, err := protocol.MarkAsCall(message, 23)
+	require.NoError(test, err)
+
+	assert.Equal(test, mo.Some[uint64](23), protocol.CallID(callMessage))
+	assert.False(test, protocol.IsPush(callMessage))
+	assert.Equal(test, message.Body(), callMessage.Body())
+
+	invalidCallMessage, err :=
+		addResponseHeader(message, CallIDHeaderKey, "invalid")
+	require.NoError(test, err)
+
+	assert.Equal(test, mo.None[uint64](), protocol.CallID(invalidCallMessage))
+}
+
+func TestSessionProtocol_callReply(test *testing.T) {
+	protocol := NewSessionProtocol()
+
+	reply := newTestRequest(test, "dummy", "body")
+	assert.Equal(test, mo.None[uint64](), protocol.CallReplyID(reply))
+
+	markedReply, err := protocol.MarkAsCallReply(reply, 42)
+	require.NoError(test, err)
+
+	assert.Equal(test, mo.Some[uint64](42), protocol.CallReplyID(markedReply))
+	assert.Equal(test, reply.Action(), markedReply.Action())
+	assert.Equal(test, reply.Body(), markedReply.Body())
+}
@@ -0,0 +1,398 @@
+package defaultProtocol
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samber/mo"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+)
+
+const (
+	SignatureKeyIDHeaderKey     = "__signature_key_id__"
+	SignatureTimestampHeaderKey = "__signature_timestamp__"
+	SignatureNonceHeaderKey     = "__signature_nonce__"
+	SignatureHeaderKey          = "__signature__"
+	DefaultMaxClockSkew         = 5 * time.Minute
+	DefaultNonceStoreSize       = 64 * 1024
+
+	signatureNonceSize = 16
+)
+
+var (
+	ErrSigningKeyIDIsEmpty = errors.New("signing key ID is empty")
+	ErrRequestIsNotSigned  = errors.New("request is not signed")
+	ErrUnknownSigningKey   = errors.New("unknown signing key")
+	ErrSignatureIsInvalid  = errors.New("signature is invalid")
+	ErrClockSkewIsExceeded = errors.New("clock skew is exceeded")
+	ErrNonceIsReplayed     = errors.New("nonce is replayed")
+	ErrNonceStoreIsFull    = errors.New("nonce store is full")
+)
+
+type SigningOptions struct {
+	KeyID string
+	Key   []byte
+}
+
+// SignatureVerificationOptions configures the verification middleware;
+// the nonces are remembered for the double max clock skew,
+// because the older requests are rejected by their timestamps anyway;
+// if the store is full of the unexpired nonces, the requests are rejected,
+// so the middleware accepts at most NonceStoreSize requests
+// per the double max clock skew (about 109 requests per second by default)
+type SignatureVerificationOptions struct {
+	// Keyring maps the key IDs to the keys
+	Keyring        map[string][]byte
+	MaxClockSkew   mo.Option[time.Duration]
+	NonceStoreSize mo.Option[int]
+}
+
+// NewSignatureVerificationMiddleware verifies the HMAC-SHA256 signatures
+// of the requests and strips the signature headers before the handler;
+// the signature covers the action, all the other headers and the body,
+// so the middleware should be applied before the ones
+// that modify the requests (e.g. the compression one)
+func NewSignatureVerificationMiddleware(
+	options SignatureVerificationOptions,
+) tcpServer.RequestMiddleware[
+	defaultProtocolModels.Request,
+	defaultProtocolModels.Response,
+] {
+	nonceStore := newNonceStore(
+		options.NonceStoreSize.OrElse(DefaultNonceStoreSize),
+		2*options.MaxClockSkew.OrElse(DefaultMaxClockSkew),
+	)
+
+	return func(
+		handler tcpServer.RequestHandler[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		],
+	) tcpServer.RequestHandler[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	] {
+		return tcpServer.RequestHandlerFunc[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		](func(
+			ctx context.Context,
+			request defaultProtocolModels.Request,
+		) (defaultProtocolModels.Response, error) {
+			request, err := verifyRequestSignature(
+				request,
+				options,
+				nonceStore,
+				time.Now(),
+			)
+			if err != nil {
+				return defaultProtocolModels.Response{}, err
+			}
+
+			return handler.HandleRequest(ctx, request)
+		})
+	}
+}
+
+// SigningClientProtocol is the client counterpart
+// of the verification middleware
+type SigningClientProtocol struct {
+	tcpServer.ClientProtocol[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	]
+
+	options SigningOptions
+}
+
+func NewSigningClientProtocol(
+	protocol tcpServer.ClientProtocol[
+		defaultProtocolModels.Request,
+		defaultProtocolModels.Response,
+	],
+	options SigningOptions,
+) SigningClientProtocol {
+	return SigningClientProtocol{
+		ClientProtocol: protocol,
+		options:        options,
+	}
+}
+
+func (protocol SigningClientProtocol) MarshalRequest(
+	request defaultProtocolModels.Request,
+) ([]byte, error) {
+	request, err := SignRequest(request, protocol.options)
+	if err != nil {
+		return nil, err
+	}
+
+	return protocol.ClientProtocol.MarshalRequest(request)
+}
+
+func IsSignedRequest(request defaultProtocolModels.Request) bool {
+	return hasHeader(request.Headers(), SignatureHeaderKey)
+}
+
+func SignRequest(
+	request defaultProtocolModels.Request,
+	options SigningOptions,
+) (defaultProtocolModels.Request, error) {
+	nonce := make([]byte, signatureNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return defaultProtocolModels.Request{}, fmt.Errorf(
+			"unable to generate the nonce: %w",
+			err,
+		)
+	}
+
+	return signRequest(request, options, time.Now(), hex.EncodeToString(nonce))
+}
+
+func signRequest(
+	request defaultProtocolModels.Request,
+	options SigningOptions,
+	timestamp time.Time,
+	nonce string,
+) (defaultProtocolModels.Request, error) {
+	if options.KeyID == "" {
+		return defaultProtocolModels.Request{}, ErrSigningKeyIDIsEmpty
+	}
+
+	headers := removeHeader(request.Headers(), SignatureHeaderKey)
+	for _, header := range [][2]string{
+		{SignatureKeyIDHeaderKey, options.KeyID},
+		{SignatureTimestampHeaderKey, strconv.FormatInt(timestamp.Unix(), 10)},
+		{SignatureNonceHeaderKey, nonce},
+	} {
+		headers = mo.Some(addHeader(headers, header[0], header[1]))
+	}
+
+	request, err := rebuildRequest(request, headers, request.Body().OrEmpty())
+	if err != nil {
+		return defaultProtocolModels.Request{}, err
+	}
+
+	signature, err := computeSignature(request, options.Key)
+	if err != nil {
+		return defaultProtocolModels.Request{}, err
+	}
+
+	return addRequestHeader(
+		request,
+		SignatureHeaderKey,
+		hex.EncodeToString(signature),
+	)
+}
+
+func verifyRequestSignature(
+	request defaultProtocolModels.Request,
+	options SignatureVerificationOptions,
+	nonceStore *nonceStore,
+	now time.Time,
+) (defaultProtocolModels.Request, error) {
+	if !IsSignedRequest(request) {
+		return defaultProtocolModels.Request{}, ErrRequestIsNotSigned
+	}
+
+	keyID := string(headerValue(request.Headers(), SignatureKeyIDHeaderKey).
+		OrEmpty())
+	key, isFound := options.Keyring[keyID]
+	if !isFound {
+		return defaultProtocolModels.Request{}, fmt.Errorf(
+			"%w: %q",
+			ErrUnknownSigningKey,
+			keyID,
+		)
+	}
+
+	signature, err := hex.DecodeString(
+		string(headerValue(request.Headers(), SignatureHeaderKey).OrEmpty()),
+	)
+	if err != nil {
+		return defaultProtocolModels.Request{}, fmt.Errorf(
+			"%w: unable to decode the signature: %w",
+			ErrSignatureIsInvalid,
+			err,
+		)
+	}
+
+	unsignedRequest, err := rebuildRequest(
+		request,
+		removeHeader(request.Headers(), SignatureHeaderKey),
+		request.Body().OrEmpty(),
+	)
+	if err != nil {
+		return defaultProtocolModels.Request{}, err
+	}
+
+	expectedSignature, err := computeSignature(unsignedRequest, key)
+	if err != nil {
+		return defaultProtocolModels.Request{}, err
+	}
+	if !hmac.Equal(signature, expectedSignature) {
+		return defaultProtocolModels.Request{}, ErrSignatureIsInvalid
+	}
+
+	timestamp, err := strconv.ParseInt(
+		string(headerValue(request.Headers(), SignatureTimestampHeaderKey).
+			OrEmpty()),
+		10,
+		64,
+	)
+	if err != nil {
+		return defaultProtocolModels.Request{}, fmt.Errorf(
+			"%w: unable to parse the timestamp: %w",
+			ErrSignatureIsInvalid,
+			err,
+		)
+	}
+
+	clockSkew := now.Sub(time.Unix(timestamp, 0)).Abs()
+	maxClockSkew := options.MaxClockSkew.OrElse(DefaultMaxClockSkew)
+	if clockSkew > maxClockSkew {
+		return defaultProtocolModels.Request{}, fmt.Errorf(
+			"%w: %s > %s",
+			ErrClockSkewIsExceeded,
+			clockSkew,
+			maxClockSkew,
+		)
+	}
+
+	nonce := string(headerValue(request.Headers(), SignatureNonceHeaderKey).
+		OrEmpty())
+	if err := nonceStore.add(keyID+":"+nonce, now); err != nil {
+		return defaultProtocolModels.Request{}, err
+	}
+
+	headers := request.Headers()
+	for _, key := range []string{
+		SignatureKeyIDHeaderKey,
+		SignatureTimestampHeaderKey,
+		SignatureNonceHeaderKey,
+		SignatureHeaderKey,
+	} {
+		headers = removeHeader(headers, key)
+	}
+
+	return rebuildRequest(request, headers, request.Body().OrEmpty())
+}
+
+// computeSignature signs the length-prefixed action, the headers
+// sorted by their keys and the body, so the parts can't be shifted
+func computeSignature(
+	request defaultProtocolModels.Request,
+	key []byte,
+) ([]byte, error) {
+	type header struct {
+		key   []byte
+		value []byte
+	}
+
+	var headers []header
+	for key, value := range request.Headers().OrEmpty().ToMap() {
+		rawKey, err := key.ToBytes()
+		if err != nil {
+			return nil, err
+		}
+
+		headers = append(headers, header{key: rawKey, value: value.ToBytes()})
+	}
+	slices.SortFunc(headers, func(header header, another header) int {
+		return strings.Compare(string(header.key), string(another.key))
+	})
+
+	mac := hmac.New(sha256.New, key)
+	writeSignedPart(mac, request.Action().ToBytes())
+	writeSignedPart(mac, binary.BigEndian.AppendUint32(nil, uint32(len(headers))))
+	for _, header := range headers {
+		writeSignedPart(mac, header.key)
+		writeSignedPart(mac, header.value)
+	}
+	writeSignedPart(mac, request.Body().OrEmpty().ToBytes())
+
+	return mac.Sum(nil), nil
+}
+
+func writeSignedPart(mac hash.Hash, part []byte) {
+	lengthPrefix := binary.BigEndian.AppendUint32(nil, uint32(len(part)))
+	mac.Write(lengthPrefix) //nolint:errcheck
+	mac.Write(part)         //nolint:errcheck
+}
+
+type nonceStore struct {
+	lock     sync.Mutex
+	size     int
+	lifetime time.Duration
+	times    map[string]time.Time
+	// the queue is ordered by the adding times;
+	// its entries are stale if the map has a later time for the same nonce
+	queue []nonceEntry
+}
+
+type nonceEntry struct {
+	nonce      string
+	addingTime time.Time
+}
+
+func newNonceStore(size int, lifetime time.Duration) *nonceStore {
+	return &nonceStore{
+		size:     max(size, 1),
+		lifetime: lifetime,
+		times:    make(map[string]time.Time),
+	}
+}
+
+// add fails if the nonce has been already seen
+// or if the store is full of the unexpired nonces
+func (store *nonceStore) add(nonce string, now time.Time) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.evictExpired(now)
+
+	addingTime, isFound := store.times[nonce]
+	if isFound && now.Sub(addingTime) < store.lifetime {
+		return ErrNonceIsReplayed
+	}
+
+	// evicting an unexpired nonce would allow to replay it,
+	// so fail closed instead
+	if !isFound && len(store.times) >= store.size {
+		return ErrNonceStoreIsFull
+	}
+
+	// the expired nonce is moved to the back of the queue,
+	// its previous entry becomes stale
+
+	store.times[nonce] = now
+	store.queue = append(store.queue, nonceEntry{nonce: nonce, addingTime: now})
+
+	return nil
+}
+
+func (store *nonceStore) evictExpired(now time.Time) {
+	for len(store.queue) > 0 &&
+		now.Sub(store.queue[0].addingTime) >= store.lifetime {
+		entry := store.queue[0]
+		if addingTime, isFound := store.times[entry.nonce]; isFound &&
+			addingTime.Equal(entry.addingTime) {
+			delete(store.times, entry.nonce)
+		}
+
+		store.queue[0] = nonceEntry{} // release the nonce for the collector
+		store.queue = store.queue[1:]
+	}
+}
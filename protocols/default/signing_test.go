@@ -0,0 +1,285 @@
+package defaultProtocol
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+func TestVerifyRequestSignature(test *testing.T) {
+	now := time.Now()
+	signingOptions := SigningOptions{KeyID: "key-id", Key: []byte("key")}
+	verificationOptions := SignatureVerificationOptions{
+		Keyring: map[string][]byte{"key-id": []byte("key")},
+	}
+
+	type args struct {
+		request func(test *testing.T) defaultProtocolModels.Request
+	}
+
+	for _, data := range []struct {
+		name        string
+		args        args
+		seenNonces  []string
+		wantHeaders mo.Option[defaultProtocolModelValueTypes.Headers]
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					request, err := addRequestHeader(
+						newTestRequest(test, "dummy", "body"),
+						"key",
+						"value",
+					)
+					require.NoError(test, err)
+
+					return newTestSignedRequest(test, request, signingOptions, now)
+				},
+			},
+			seenNonces: []string{"key-id:another-nonce"},
+			wantHeaders: mo.Some(addHeader(
+				mo.None[defaultProtocolModelValueTypes.Headers](),
+				"key",
+				"value",
+			)),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/without the signature",
+			args: args{
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestRequest(test, "dummy", "body")
+				},
+			},
+			seenNonces:  nil,
+			wantHeaders: mo.None[defaultProtocolModelValueTypes.Headers](),
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrRequestIsNotSigned)
+			},
+		},
+		{
+			name: "error/with the unknown key",
+			args: args{
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestSignedRequest(
+						test,
+						newTestRequest(test, "dummy", "body"),
+						SigningOptions{KeyID: "unknown", Key: []byte("key")},
+						now,
+					)
+				},
+			},
+			seenNonces:  nil,
+			wantHeaders: mo.None[defaultProtocolModelValueTypes.Headers](),
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrUnknownSigningKey)
+			},
+		},
+		{
+			name: "error/with the wrong key",
+			args: args{
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestSignedRequest(
+						test,
+						newTestRequest(test, "dummy", "body"),
+						SigningOptions{KeyID: "key-id", Key: []byte("wrong key")},
+						now,
+					)
+				},
+			},
+			seenNonces:  nil,
+			wantHeaders: mo.None[defaultProtocolModelValueTypes.Headers](),
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrSignatureIsInvalid)
+			},
+		},
+		{
+			name: "error/with the tampered body",
+			args: args{
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					request := newTestSignedRequest(
+						test,
+						newTestRequest(test, "dummy", "body"),
+						signingOptions,
+						now,
+					)
+
+					request, err := rebuildRequest(
+						request,
+						request.Headers(),
+						defaultProtocolModelValueTypes.NewBody([]byte("tampered")),
+					)
+					require.NoError(test, err)
+
+					return request
+				},
+			},
+			seenNonces:  nil,
+			wantHeaders: mo.None[defaultProtocolModelValueTypes.Headers](),
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrSignatureIsInvalid)
+			},
+		},
+		{
+			name: "error/with the added header",
+			args: args{
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					request, err := addRequestHeader(
+						newTestSignedRequest(
+							test,
+							newTestRequest(test, "dummy", "body"),
+							signingOptions,
+							now,
+						),
+						"key",
+						"value",
+					)
+					require.NoError(test, err)
+
+					return request
+				},
+			},
+			seenNonces:  nil,
+			wantHeaders: mo.None[defaultProtocolModelValueTypes.Headers](),
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrSignatureIsInvalid)
+			},
+		},
+		{
+			name: "error/with the exceeded clock skew",
+			args: args{
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestSignedRequest(
+						test,
+						newTestRequest(test, "dummy", "body"),
+						signingOptions,
+						now.Add(-2*DefaultMaxClockSkew),
+					)
+				},
+			},
+			seenNonces:  nil,
+			wantHeaders: mo.None[defaultProtocolModelValueTypes.Headers](),
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrClockSkewIsExceeded)
+			},
+		},
+		{
+			name: "error/with the replayed nonce",
+			args: args{
+				request: func(test *testing.T) defaultProtocolModels.Request {
+					return newTestSignedRequest(
+						test,
+						newTestRequest(test, "dummy", "body"),
+						signingOptions,
+						now,
+					)
+				},
+			},
+			seenNonces:  []string{"key-id:nonce"},
+			wantHeaders: mo.None[defaultProtocolModelValueTypes.Headers](),
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrNonceIsReplayed)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			nonceStore := newNonceStore(10, 2*DefaultMaxClockSkew)
+			for _, nonce := range data.seenNonces {
+				require.NoError(test, nonceStore.add(nonce, now))
+			}
+
+			got, err := verifyRequestSignature(
+				data.args.request(test),
+				verificationOptions,
+				nonceStore,
+				now,
+			)
+
+			assert.Equal(test, data.wantHeaders, got.Headers())
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestNewSignatureVerificationMiddleware(test *testing.T) {
+	handler := NewSignatureVerificationMiddleware(SignatureVerificationOptions{
+		Keyring: map[string][]byte{"key-id": []byte("key")},
+	})(
+		tcpServer.RequestHandlerFunc[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		](func(
+			ctx context.Context,
+			request defaultProtocolModels.Request,
+		) (defaultProtocolModels.Response, error) {
+			assert.False(test, IsSignedRequest(request))
+			return newTestResponse(test, "dummy", "body"), nil
+		}),
+	)
+
+	baseProtocol := &testClientProtocol{}
+	protocol := NewSigningClientProtocol(
+		baseProtocol,
+		SigningOptions{KeyID: "key-id", Key: []byte("key")},
+	)
+
+	_, err := protocol.MarshalRequest(newTestRequest(test, "dummy", "body"))
+	require.NoError(test, err)
+	require.True(test, IsSignedRequest(baseProtocol.request))
+
+	response, err :=
+		handler.HandleRequest(context.Background(), baseProtocol.request)
+	require.NoError(test, err)
+	assert.Equal(test, newTestResponse(test, "dummy", "body"), response)
+
+	_, err = handler.HandleRequest(context.Background(), baseProtocol.request)
+	assert.ErrorIs(test, err, ErrNonceIsReplayed)
+}
+
+func TestNonceStore(test *testing.T) {
+	now := time.Now()
+	store := newNonceStore(2, time.Minute)
+
+	assert.NoError(test, store.add("one", now))
+	assert.NoError(test, store.add("two", now.Add(time.Second)))
+	assert.ErrorIs(test, store.add("one", now), ErrNonceIsReplayed)
+
+	// the unexpired nonces are not evicted
+	assert.ErrorIs(test, store.add("three", now), ErrNonceStoreIsFull)
+	assert.ErrorIs(test, store.add("one", now), ErrNonceIsReplayed)
+
+	// the expired nonce is evicted
+	now = now.Add(time.Minute)
+	assert.NoError(test, store.add("three", now))
+	assert.ErrorIs(test, store.add("two", now), ErrNonceIsReplayed)
+
+	// the expired nonce is accepted again and moved to the back of the queue
+	now = now.Add(time.Second)
+	assert.NoError(test, store.add("two", now))
+	assert.ErrorIs(test, store.add("one", now), ErrNonceStoreIsFull)
+
+	now = now.Add(time.Minute - time.Second)
+	assert.NoError(test, store.add("one", now))
+	assert.ErrorIs(test, store.add("two", now), ErrNonceIsReplayed)
+}
+
+func newTestSignedRequest(
+	test *testing.T,
+	request defaultProtocolModels.Request,
+	options SigningOptions,
+	timestamp time.Time,
+) defaultProtocolModels.Request {
+	request, err := signRequest(request, options, timestamp, "nonce")
+	require.NoError(test, err)
+
+	return request
+}
@@ -0,0 +1,66 @@
+package defaultProtocol
+
+import (
+	"bytes"
+	"fmt"
+
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+const (
+	StreamingHeaderKey   = "__streaming__"
+	StreamingHeaderValue = "true"
+	EndOfStreamStatus    = "__end_of_stream__"
+)
+
+type StreamingProtocol struct{}
+
+func NewStreamingProtocol() StreamingProtocol {
+	return StreamingProtocol{}
+}
+
+// MarkRequestAsStreaming requests the streaming of the responses
+func (protocol StreamingProtocol) MarkRequestAsStreaming(
+	request defaultProtocolModels.Request,
+) (defaultProtocolModels.Request, error) {
+	return addRequestHeader(request, StreamingHeaderKey, StreamingHeaderValue)
+}
+
+func (protocol StreamingProtocol) IsStreamingRequest(
+	request defaultProtocolModels.Request,
+) bool {
+	return hasHeader(request.Headers(), StreamingHeaderKey)
+}
+
+func (protocol StreamingProtocol) NewEndOfStreamResponse() (
+	defaultProtocolModels.Response,
+	error,
+) {
+	status, err :=
+		defaultProtocolModelValueTypes.NewStatus([]byte(EndOfStreamStatus))
+	if err != nil {
+		return defaultProtocolModels.Response{}, fmt.Errorf(
+			"unable to construct the status: %w",
+			err,
+		)
+	}
+
+	response, err := defaultProtocolModels.NewResponseBuilder().
+		SetStatus(status).
+		Build()
+	if err != nil {
+		return defaultProtocolModels.Response{}, fmt.Errorf(
+			"unable to build the response: %w",
+			err,
+		)
+	}
+
+	return response, nil
+}
+
+func (protocol StreamingProtocol) IsEndOfStreamResponse(
+	response defaultProtocolModels.Response,
+) bool {
+	return bytes.Equal(response.Status().ToBytes(), []byte(EndOfStreamStatus))
+}
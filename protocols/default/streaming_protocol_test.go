@@ -0,0 +1,82 @@
+package defaultProtocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+func TestStreamingProtocol_interface(test *testing.T) {
+	assert.Implements(
+		test,
+		(*tcpServer.StreamingProtocol[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		])(nil),
+		StreamingProtocol{},
+	)
+}
+
+func TestStreamingProtocol_streamingRequest(test *testing.T) {
+	protocol := NewStreamingProtocol()
+
+	action, err := defaultProtocolModelValueTypes.NewAction([]byte("action"))
+	require.NoError(test, err)
+
+	request, err := defaultProtocolModels.NewRequestBuilder().
+		SetAction(action).
+		SetHeaders(defaultProtocolModelValueTypes.NewHeaders(
+			map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue{ //nolint:lll
+				defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("key")): defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("value")), //nolint:lll
+			},
+		)).
+		SetBody(defaultProtocolModelValueTypes.NewBody([]byte("body"))).
+		Build()
+	require.NoError(test, err)
+
+	assert.False(test, protocol.IsStreamingRequest(request))
+
+	streamingRequest, err := protocol.MarkRequestAsStreaming(request)
+	require.NoError(test, err)
+
+	assert.True(test, protocol.IsStreamingRequest(streamingRequest))
+	assert.Equal(test, request.Action(), streamingRequest.Action())
+	assert.Equal(test, request.Body(), streamingRequest.Body())
+	assert.Equal(
+		test,
+		map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue{ //nolint:lll
+			defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("key")):              defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("value")),              //nolint:lll
+			defaultProtocolModelValueTypes.MustNewHeaderKey([]byte(StreamingHeaderKey)): defaultProtocolModelValueTypes.MustNewHeaderValue([]byte(StreamingHeaderValue)), //nolint:lll
+		},
+		streamingRequest.Headers().MustGet().ToMap(),
+	)
+	assert.Len(test, request.Headers().MustGet().ToMap(), 1)
+}
+
+func TestStreamingProtocol_endOfStream(test *testing.T) {
+	protocol := NewStreamingProtocol()
+
+	endOfStreamResponse, err := protocol.NewEndOfStreamResponse()
+	require.NoError(test, err)
+
+	assert.Equal(
+		test,
+		[]byte(EndOfStreamStatus),
+		endOfStreamResponse.Status().ToBytes(),
+	)
+	assert.True(test, protocol.IsEndOfStreamResponse(endOfStreamResponse))
+
+	status, err := defaultProtocolModelValueTypes.NewStatus([]byte("status"))
+	require.NoError(test, err)
+
+	otherResponse, err := defaultProtocolModels.NewResponseBuilder().
+		SetStatus(status).
+		Build()
+	require.NoError(test, err)
+
+	assert.False(test, protocol.IsEndOfStreamResponse(otherResponse))
+}
@@ -0,0 +1,105 @@
+package defaultProtocol
+
+import (
+	"bytes"
+	"fmt"
+
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+const (
+	TLSUpgradeAction         = "__start_tls__"
+	TLSUpgradeAcceptedStatus = "__tls_upgrade_accepted__"
+	TLSUpgradeRejectedStatus = "__tls_upgrade_rejected__"
+)
+
+type TLSUpgradeProtocol struct{}
+
+func NewTLSUpgradeProtocol() TLSUpgradeProtocol {
+	return TLSUpgradeProtocol{}
+}
+
+func (protocol TLSUpgradeProtocol) NewUpgradeRequest() (
+	defaultProtocolModels.Request,
+	error,
+) {
+	action, err :=
+		defaultProtocolModelValueTypes.NewAction([]byte(TLSUpgradeAction))
+	if err != nil {
+		return defaultProtocolModels.Request{}, fmt.Errorf(
+			"unable to construct the action: %w",
+			err,
+		)
+	}
+
+	request, err := defaultProtocolModels.NewRequestBuilder().
+		SetAction(action).
+		Build()
+	if err != nil {
+		return defaultProtocolModels.Request{}, fmt.Errorf(
+			"unable to build the request: %w",
+			err,
+		)
+	}
+
+	return request, nil
+}
+
+func (protocol TLSUpgradeProtocol) IsUpgradeRequest(
+	request defaultProtocolModels.Request,
+) bool {
+	return bytes.Equal(request.Action().ToBytes(), []byte(TLSUpgradeAction))
+}
+
+func (protocol TLSUpgradeProtocol) NewUpgradeAcceptanceResponse() (
+	defaultProtocolModels.Response,
+	error,
+) {
+	return newTLSUpgradeResponse(TLSUpgradeAcceptedStatus, "")
+}
+
+func (protocol TLSUpgradeProtocol) NewUpgradeRejectionResponse(
+	reason string,
+) (defaultProtocolModels.Response, error) {
+	return newTLSUpgradeResponse(TLSUpgradeRejectedStatus, reason)
+}
+
+func (protocol TLSUpgradeProtocol) IsUpgradeAcceptanceResponse(
+	response defaultProtocolModels.Response,
+) bool {
+	return bytes.Equal(
+		response.Status().ToBytes(),
+		[]byte(TLSUpgradeAcceptedStatus),
+	)
+}
+
+func newTLSUpgradeResponse(
+	rawStatus string,
+	reason string,
+) (defaultProtocolModels.Response, error) {
+	status, err := defaultProtocolModelValueTypes.NewStatus([]byte(rawStatus))
+	if err != nil {
+		return defaultProtocolModels.Response{}, fmt.Errorf(
+			"unable to construct the status: %w",
+			err,
+		)
+	}
+
+	responseBuilder := defaultProtocolModels.NewResponseBuilder().
+		SetStatus(status)
+	if reason != "" {
+		responseBuilder.
+			SetBody(defaultProtocolModelValueTypes.NewBody([]byte(reason)))
+	}
+
+	response, err := responseBuilder.Build()
+	if err != nil {
+		return defaultProtocolModels.Response{}, fmt.Errorf(
+			"unable to build the response: %w",
+			err,
+		)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,99 @@
+package defaultProtocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+)
+
+func TestTLSUpgradeProtocol_interface(test *testing.T) {
+	assert.Implements(
+		test,
+		(*tcpServer.TLSUpgradeProtocol[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		])(nil),
+		TLSUpgradeProtocol{},
+	)
+}
+
+func TestTLSUpgradeProtocol_request(test *testing.T) {
+	protocol := NewTLSUpgradeProtocol()
+
+	upgradeRequest, err := protocol.NewUpgradeRequest()
+	require.NoError(test, err)
+
+	assert.Equal(test, []byte(TLSUpgradeAction), upgradeRequest.Action().ToBytes())
+	assert.True(test, protocol.IsUpgradeRequest(upgradeRequest))
+	assert.False(
+		test,
+		protocol.IsUpgradeRequest(newTestRequest(test, "dummy", "body")),
+	)
+}
+
+func TestTLSUpgradeProtocol_response(test *testing.T) {
+	protocol := NewTLSUpgradeProtocol()
+
+	for _, data := range []struct {
+		name           string
+		newResponse    func() (defaultProtocolModels.Response, error)
+		wantStatus     string
+		wantBody       string
+		wantIsAccepted bool
+	}{
+		{
+			name:           "acceptance",
+			newResponse:    protocol.NewUpgradeAcceptanceResponse,
+			wantStatus:     TLSUpgradeAcceptedStatus,
+			wantBody:       "",
+			wantIsAccepted: true,
+		},
+		{
+			name: "rejection",
+			newResponse: func() (defaultProtocolModels.Response, error) {
+				return protocol.NewUpgradeRejectionResponse("reason")
+			},
+			wantStatus:     TLSUpgradeRejectedStatus,
+			wantBody:       "reason",
+			wantIsAccepted: false,
+		},
+		{
+			name: "rejection without the reason",
+			newResponse: func() (defaultProtocolModels.Response, error) {
+				return protocol.NewUpgradeRejectionResponse("")
+			},
+			wantStatus:     TLSUpgradeRejectedStatus,
+			wantBody:       "",
+			wantIsAccepted: false,
+		},
+		{
+			name: "other",
+			newResponse: func() (defaultProtocolModels.Response, error) {
+				return newTestResponse(test, "dummy", "body"), nil
+			},
+			wantStatus:     "dummy",
+			wantBody:       "body",
+			wantIsAccepted: false,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			response, err := data.newResponse()
+			require.NoError(test, err)
+
+			assert.Equal(test, []byte(data.wantStatus), response.Status().ToBytes())
+			assert.Equal(
+				test,
+				data.wantBody,
+				string(response.Body().OrEmpty().ToBytes()),
+			)
+			assert.Equal(
+				test,
+				data.wantIsAccepted,
+				protocol.IsUpgradeAcceptanceResponse(response),
+			)
+		})
+	}
+}
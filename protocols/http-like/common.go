@@ -0,0 +1,60 @@
+package httpLikeProtocol
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const (
+	ContentLengthHeaderKey = "Content-Length"
+)
+
+var (
+	lineSeparator           = []byte("\r\n")
+	headerKeyValueSeparator = []byte(":")
+)
+
+// ScanHead scans the start line and the header lines up to the blank line;
+// the lines may be terminated by either CRLF or a single LF
+func ScanHead(data []byte) (lines [][]byte, size int, isComplete bool) {
+	for {
+		lineEnd := bytes.IndexByte(data[size:], '\n')
+		if lineEnd == -1 {
+			return nil, 0, false
+		}
+
+		line := bytes.TrimSuffix(data[size:size+lineEnd], []byte("\r"))
+		size += lineEnd + 1
+
+		if len(line) == 0 {
+			return lines, size, true
+		}
+
+		lines = append(lines, line)
+	}
+}
+
+// ParseContentLength returns zero if there is no `Content-Length` header
+func ParseContentLength(headerLines [][]byte) (int, error) {
+	for _, headerLine := range headerLines {
+		key, value, isFound := bytes.Cut(headerLine, headerKeyValueSeparator)
+		if !isFound ||
+			!bytes.EqualFold(bytes.TrimSpace(key), []byte(ContentLengthHeaderKey)) {
+			continue
+		}
+
+		contentLength, err := strconv.Atoi(string(bytes.TrimSpace(value)))
+		if err != nil {
+			return 0, fmt.Errorf("unable to parse the content length: %w", err)
+		}
+		if contentLength < 0 {
+			return 0, errors.New("content length cannot be negative")
+		}
+
+		return contentLength, nil
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,178 @@
+package httpLikeProtocol
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+// MessageFormat manages the `Content-Length` header itself: it's removed
+// from the parsed headers and is set on the marshalling of a nonempty body
+type MessageFormat struct{}
+
+func NewMessageFormat() MessageFormat {
+	return MessageFormat{}
+}
+
+func (format MessageFormat) ParseMessage(
+	data []byte,
+) (defaultProtocolModels.Message, error) {
+	lines, headSize, isComplete := ScanHead(data)
+	if !isComplete {
+		return defaultProtocolModels.Message{},
+			errors.New("message head is incomplete")
+	}
+	if len(lines) == 0 {
+		return defaultProtocolModels.Message{},
+			errors.New("message start line is missed")
+	}
+
+	introduction, err := defaultProtocolModelValueTypes.NewIntroduction(lines[0])
+	if err != nil {
+		return defaultProtocolModels.Message{}, fmt.Errorf(
+			"unable to construct the introduction: %w",
+			err,
+		)
+	}
+
+	rawHeaders :=
+		make(map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue) //nolint:lll
+	for headerLineIndex, headerLine := range lines[1:] {
+		rawHeaderKey, rawHeaderValue, isFound :=
+			bytes.Cut(headerLine, headerKeyValueSeparator)
+		if !isFound {
+			return defaultProtocolModels.Message{}, fmt.Errorf(
+				"header #%d has no key-value separator",
+				headerLineIndex,
+			)
+		}
+
+		rawHeaderKey = bytes.TrimSpace(rawHeaderKey)
+		if bytes.EqualFold(rawHeaderKey, []byte(ContentLengthHeaderKey)) {
+			continue
+		}
+
+		headerKey, err := defaultProtocolModelValueTypes.NewHeaderKey(rawHeaderKey)
+		if err != nil {
+			return defaultProtocolModels.Message{}, fmt.Errorf(
+				"unable to construct the header key: %w",
+				err,
+			)
+		}
+
+		rawHeaderValue = bytes.TrimSpace(rawHeaderValue)
+		headerValue, err :=
+			defaultProtocolModelValueTypes.NewHeaderValue(rawHeaderValue)
+		if err != nil {
+			return defaultProtocolModels.Message{}, fmt.Errorf(
+				"unable to construct the header value: %w",
+				err,
+			)
+		}
+
+		rawHeaders[headerKey] = headerValue
+	}
+
+	contentLength, err := ParseContentLength(lines[1:])
+	if err != nil {
+		return defaultProtocolModels.Message{}, fmt.Errorf(
+			"unable to get the content length: %w",
+			err,
+		)
+	}
+
+	rawBody := data[headSize:]
+	if len(rawBody) != contentLength {
+		return defaultProtocolModels.Message{}, fmt.Errorf(
+			"body length %d doesn't match the content length %d",
+			len(rawBody),
+			contentLength,
+		)
+	}
+
+	message, err := defaultProtocolModels.NewMessageBuilder().
+		SetIntroduction(introduction).
+		SetHeaders(defaultProtocolModelValueTypes.NewHeaders(rawHeaders)).
+		SetBody(defaultProtocolModelValueTypes.NewBody(rawBody)).
+		Build()
+	if err != nil {
+		return defaultProtocolModels.Message{}, fmt.Errorf(
+			"unable to build the message: %w",
+			err,
+		)
+	}
+
+	return message, nil
+}
+
+func (format MessageFormat) MarshalMessage(
+	message defaultProtocolModels.Message,
+) ([]byte, error) {
+	introduction := message.Introduction().ToBytes()
+	if err := checkLineBreaks(introduction); err != nil {
+		return nil, fmt.Errorf("invalid introduction: %w", err)
+	}
+
+	headerLines :=
+		make([][]byte, 0, len(message.Headers().OrEmpty().ToMap()))
+	for headerKey, headerValue := range message.Headers().OrEmpty().ToMap() {
+		rawHeaderKey, err := headerKey.ToBytes()
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert the header key to bytes: %w", err)
+		}
+		if err := checkLineBreaks(rawHeaderKey); err != nil {
+			return nil, fmt.Errorf("invalid header key: %w", err)
+		}
+		if bytes.Contains(rawHeaderKey, headerKeyValueSeparator) {
+			return nil, errors.New("header key cannot contain a colon")
+		}
+		if bytes.EqualFold(rawHeaderKey, []byte(ContentLengthHeaderKey)) {
+			continue
+		}
+
+		rawHeaderValue := headerValue.ToBytes()
+		if err := checkLineBreaks(rawHeaderValue); err != nil {
+			return nil, fmt.Errorf("invalid header value: %w", err)
+		}
+
+		headerLines = append(headerLines, bytes.Join(
+			[][]byte{rawHeaderKey, rawHeaderValue},
+			[]byte(": "),
+		))
+	}
+	slices.SortStableFunc(headerLines, func(a []byte, b []byte) int {
+		return bytes.Compare(a, b)
+	})
+
+	rawBody := message.Body().OrEmpty().ToBytes()
+	if len(rawBody) != 0 {
+		headerLines = append(
+			headerLines,
+			fmt.Appendf(nil, "%s: %d", ContentLengthHeaderKey, len(rawBody)),
+		)
+	}
+
+	var buffer bytes.Buffer
+	buffer.Write(introduction)
+	buffer.Write(lineSeparator)
+	for _, headerLine := range headerLines {
+		buffer.Write(headerLine)
+		buffer.Write(lineSeparator)
+	}
+	buffer.Write(lineSeparator)
+	buffer.Write(rawBody)
+
+	return buffer.Bytes(), nil
+}
+
+func checkLineBreaks(data []byte) error {
+	if bytes.ContainsAny(data, "\r\n") {
+		return errors.New("line breaks are not allowed")
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package httpLikeProtocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	defaultProtocol "github.com/thewizardplusplus/go-tcp-server/protocols/default"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+func TestMessageFormat_interface(test *testing.T) {
+	assert.Implements(test, (*defaultProtocol.MessageFormat)(nil), MessageFormat{})
+}
+
+func TestMessageFormat_ParseMessage(test *testing.T) {
+	type args struct {
+		data []byte
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    defaultProtocolModels.Message
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/minimal message",
+			args: args{
+				data: []byte("GET /dummy\r\n\r\n"),
+			},
+			want: func() defaultProtocolModels.Message {
+				introduction, err :=
+					defaultProtocolModelValueTypes.NewIntroduction([]byte("GET /dummy"))
+				require.NoError(test, err)
+
+				message, err := defaultProtocolModels.NewMessageBuilder().
+					SetIntroduction(introduction).
+					Build()
+				require.NoError(test, err)
+
+				return message
+			}(),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/full message",
+			args: args{
+				data: []byte(
+					"POST /dummy\r\n" +
+						"One: two\r\n" +
+						"three:four \r\n" +
+						"content-length: 6\r\n" +
+						"\r\n" +
+						"b\r\nody",
+				),
+			},
+			want: func() defaultProtocolModels.Message {
+				introduction, err :=
+					defaultProtocolModelValueTypes.NewIntroduction([]byte("POST /dummy"))
+				require.NoError(test, err)
+
+				message, err := defaultProtocolModels.NewMessageBuilder().
+					SetIntroduction(introduction).
+					SetHeaders(defaultProtocolModelValueTypes.NewHeaders(
+						map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue{ //nolint:lll
+							defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("One")):   defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("two")),  //nolint:lll
+							defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("three")): defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("four")), //nolint:lll
+						},
+					)).
+					SetBody(defaultProtocolModelValueTypes.NewBody([]byte("b\r\nody"))).
+					Build()
+				require.NoError(test, err)
+
+				return message
+			}(),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/with LF line endings",
+			args: args{
+				data: []byte("GET /dummy\nOne: two\n\n"),
+			},
+			want: func() defaultProtocolModels.Message {
+				introduction, err :=
+					defaultProtocolModelValueTypes.NewIntroduction([]byte("GET /dummy"))
+				require.NoError(test, err)
+
+				message, err := defaultProtocolModels.NewMessageBuilder().
+					SetIntroduction(introduction).
+					SetHeaders(defaultProtocolModelValueTypes.NewHeaders(
+						map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue{ //nolint:lll
+							defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("One")): defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("two")), //nolint:lll
+						},
+					)).
+					Build()
+				require.NoError(test, err)
+
+				return message
+			}(),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/incomplete head",
+			args: args{
+				data: []byte("GET /dummy\r\n"),
+			},
+			want:    defaultProtocolModels.Message{},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/header without a separator",
+			args: args{
+				data: []byte("GET /dummy\r\ndummy\r\n\r\n"),
+			},
+			want:    defaultProtocolModels.Message{},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/invalid content length",
+			args: args{
+				data: []byte("GET /dummy\r\nContent-Length: dummy\r\n\r\n"),
+			},
+			want:    defaultProtocolModels.Message{},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/body length mismatch",
+			args: args{
+				data: []byte("GET /dummy\r\nContent-Length: 5\r\n\r\nbody"),
+			},
+			want:    defaultProtocolModels.Message{},
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := MessageFormat{}.ParseMessage(data.args.data)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestMessageFormat_MarshalMessage(test *testing.T) {
+	type args struct {
+		message func(test *testing.T) defaultProtocolModels.Message
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    []byte
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/minimal message",
+			args: args{
+				message: func(test *testing.T) defaultProtocolModels.Message {
+					introduction, err :=
+						defaultProtocolModelValueTypes.NewIntroduction([]byte("GET /dummy"))
+					require.NoError(test, err)
+
+					message, err := defaultProtocolModels.NewMessageBuilder().
+						SetIntroduction(introduction).
+						Build()
+					require.NoError(test, err)
+
+					return message
+				},
+			},
+			want:    []byte("GET /dummy\r\n\r\n"),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/full message",
+			args: args{
+				message: func(test *testing.T) defaultProtocolModels.Message {
+					introduction, err :=
+						defaultProtocolModelValueTypes.NewIntroduction([]byte("POST /dummy"))
+					require.NoError(test, err)
+
+					message, err := defaultProtocolModels.NewMessageBuilder().
+						SetIntroduction(introduction).
+						SetHeaders(defaultProtocolModelValueTypes.NewHeaders(
+							map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue{ //nolint:lll
+								defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("three")):          defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("four")), //nolint:lll
+								defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("One")):            defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("two")),  //nolint:lll
+								defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("Content-Length")): defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("23")),   //nolint:lll
+							},
+						)).
+						SetBody(defaultProtocolModelValueTypes.NewBody([]byte("b\r\nody"))).
+						Build()
+					require.NoError(test, err)
+
+					return message
+				},
+			},
+			want: []byte(
+				"POST /dummy\r\n" +
+					"One: two\r\n" +
+					"three: four\r\n" +
+					"Content-Length: 6\r\n" +
+					"\r\n" +
+					"b\r\nody",
+			),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/line break in the introduction",
+			args: args{
+				message: func(test *testing.T) defaultProtocolModels.Message {
+					introduction, err :=
+						defaultProtocolModelValueTypes.NewIntroduction([]byte("GET\r\n/dummy"))
+					require.NoError(test, err)
+
+					message, err := defaultProtocolModels.NewMessageBuilder().
+						SetIntroduction(introduction).
+						Build()
+					require.NoError(test, err)
+
+					return message
+				},
+			},
+			want:    nil,
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/colon in the header key",
+			args: args{
+				message: func(test *testing.T) defaultProtocolModels.Message {
+					introduction, err :=
+						defaultProtocolModelValueTypes.NewIntroduction([]byte("GET /dummy"))
+					require.NoError(test, err)
+
+					message, err := defaultProtocolModels.NewMessageBuilder().
+						SetIntroduction(introduction).
+						SetHeaders(defaultProtocolModelValueTypes.NewHeaders(
+							map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue{ //nolint:lll
+								defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("o:ne")): defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("two")), //nolint:lll
+							},
+						)).
+						Build()
+					require.NoError(test, err)
+
+					return message
+				},
+			},
+			want:    nil,
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := MessageFormat{}.MarshalMessage(data.args.message(test))
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package httpLikeProtocol
+
+import (
+	"fmt"
+	"io"
+
+	defaultProtocol "github.com/thewizardplusplus/go-tcp-server/protocols/default"
+)
+
+type Protocol struct {
+	defaultProtocol.BaseProtocol
+}
+
+func NewProtocol() Protocol {
+	return Protocol{
+		BaseProtocol: defaultProtocol.NewBaseProtocol(
+			defaultProtocol.BaseProtocolOptions{
+				MessageFormat: NewMessageFormat(),
+			},
+		),
+	}
+}
+
+// ExtractToken extracts the whole message including its head;
+// the blank lines before the start line are skipped
+func (protocol Protocol) ExtractToken(
+	data []byte,
+	isLatestData bool,
+) (offsetToNextToken int, token []byte, err error) {
+	lines, headSize, isComplete := ScanHead(data)
+	if isComplete && len(lines) == 0 {
+		return headSize, nil, nil // skip the blank line
+	}
+	if !isComplete {
+		return protocol.handleIncompleteData(data, isLatestData)
+	}
+
+	contentLength, err := ParseContentLength(lines[1:])
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to get the content length: %w", err)
+	}
+	if len(data)-headSize < contentLength {
+		return protocol.handleIncompleteData(data, isLatestData)
+	}
+
+	messageSize := headSize + contentLength
+	return messageSize, data[:messageSize], nil
+}
+
+func (protocol Protocol) handleIncompleteData(
+	data []byte,
+	isLatestData bool,
+) (offsetToNextToken int, token []byte, err error) {
+	if !isLatestData || len(data) == 0 {
+		return 0, nil, nil // request more data or stop on the latest data
+	}
+
+	return 0, nil, fmt.Errorf(
+		"unable to extract the message: %w",
+		io.ErrUnexpectedEOF,
+	)
+}
@@ -0,0 +1,111 @@
+package httpLikeProtocol
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+)
+
+func TestProtocol_interface(test *testing.T) {
+	assert.Implements(
+		test,
+		(*tcpServer.ServerProtocol[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		])(nil),
+		Protocol{},
+	)
+	assert.Implements(
+		test,
+		(*tcpServer.ClientProtocol[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		])(nil),
+		Protocol{},
+	)
+}
+
+func TestProtocol_ExtractToken(test *testing.T) {
+	type args struct {
+		data []byte
+	}
+
+	for _, data := range []struct {
+		name       string
+		args       args
+		wantTokens [][]byte
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/several messages",
+			args: args{
+				data: []byte(
+					"\r\nGET /one\r\n\r\n" +
+						"POST /two\r\nContent-Length: 4\r\n\r\nbody" +
+						"GET /three\nOne: two\n\n",
+				),
+			},
+			wantTokens: [][]byte{
+				[]byte("GET /one\r\n\r\n"),
+				[]byte("POST /two\r\nContent-Length: 4\r\n\r\nbody"),
+				[]byte("GET /three\nOne: two\n\n"),
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/empty data",
+			args: args{
+				data: nil,
+			},
+			wantTokens: nil,
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "error/incomplete head",
+			args: args{
+				data: []byte("GET /one\r\n\r\nGET /two\r\n"),
+			},
+			wantTokens: [][]byte{[]byte("GET /one\r\n\r\n")},
+			wantErr:    assert.Error,
+		},
+		{
+			name: "error/incomplete body",
+			args: args{
+				data: []byte("POST /one\r\nContent-Length: 5\r\n\r\nbody"),
+			},
+			wantTokens: nil,
+			wantErr:    assert.Error,
+		},
+		{
+			name: "error/invalid content length",
+			args: args{
+				data: []byte("POST /one\r\nContent-Length: -1\r\n\r\n"),
+			},
+			wantTokens: nil,
+			wantErr:    assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			scanner := tcpServer.InitializeScanner(
+				tcpServer.InitializeScannerParams[
+					defaultProtocolModels.Request,
+					defaultProtocolModels.Response,
+				]{
+					Reader:       bytes.NewReader(data.args.data),
+					BaseProtocol: NewProtocol(),
+				},
+			)
+
+			var gotTokens [][]byte
+			for scanner.Scan() {
+				gotTokens = append(gotTokens, bytes.Clone(scanner.Bytes()))
+			}
+
+			assert.Equal(test, data.wantTokens, gotTokens)
+			data.wantErr(test, scanner.Err())
+		})
+	}
+}
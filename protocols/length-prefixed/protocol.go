@@ -0,0 +1,114 @@
+package lengthPrefixedProtocol
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/samber/mo"
+	defaultProtocol "github.com/thewizardplusplus/go-tcp-server/protocols/default"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+)
+
+const (
+	LengthPrefixSize = 4
+)
+
+func AppendLengthPrefixed(buffer []byte, data []byte) []byte {
+	buffer = binary.BigEndian.AppendUint32(buffer, uint32(len(data)))
+	buffer = append(buffer, data...)
+
+	return buffer
+}
+
+type ProtocolOptions struct {
+	MessageFormat defaultProtocol.MessageFormat
+	// MaxTokenSize includes the length prefix
+	MaxTokenSize mo.Option[int]
+}
+
+// Protocol prefixes each message with its length
+// as a 4-byte big-endian unsigned integer
+type Protocol struct {
+	defaultProtocol.BaseProtocol
+}
+
+func NewProtocol(options ProtocolOptions) Protocol {
+	return Protocol{
+		BaseProtocol: defaultProtocol.NewBaseProtocol(
+			defaultProtocol.BaseProtocolOptions{
+				MessageFormat: options.MessageFormat,
+				MaxTokenSize:  options.MaxTokenSize,
+			},
+		),
+	}
+}
+
+func (protocol Protocol) ExtractToken(
+	data []byte,
+	isLatestData bool,
+) (offsetToNextToken int, token []byte, err error) {
+	if len(data) >= LengthPrefixSize {
+		length := int(binary.BigEndian.Uint32(data))
+		maxLength := protocol.MaxTokenSize() - LengthPrefixSize
+		if length > maxLength {
+			return 0, nil, fmt.Errorf(
+				"message length %d exceeds the maximum %d",
+				length,
+				maxLength,
+			)
+		}
+
+		messageSize := LengthPrefixSize + length
+		if len(data) >= messageSize {
+			return messageSize, data[LengthPrefixSize:messageSize], nil
+		}
+	}
+
+	if !isLatestData || len(data) == 0 {
+		return 0, nil, nil // request more data or stop on the latest data
+	}
+
+	return 0, nil, fmt.Errorf(
+		"unable to extract the message: %w",
+		io.ErrUnexpectedEOF,
+	)
+}
+
+func (protocol Protocol) MarshalRequest(
+	request defaultProtocolModels.Request,
+) ([]byte, error) {
+	marshalledRequest, err := protocol.BaseProtocol.MarshalRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// the length prefix is a part of the token
+	if err := defaultProtocol.CheckMessageSize(
+		marshalledRequest,
+		protocol.MaxTokenSize()-LengthPrefixSize,
+	); err != nil {
+		return nil, err
+	}
+
+	return AppendLengthPrefixed(nil, marshalledRequest), nil
+}
+
+func (protocol Protocol) MarshalResponse(
+	response defaultProtocolModels.Response,
+) ([]byte, error) {
+	marshalledResponse, err := protocol.BaseProtocol.MarshalResponse(response)
+	if err != nil {
+		return nil, err
+	}
+
+	// the length prefix is a part of the token
+	if err := defaultProtocol.CheckMessageSize(
+		marshalledResponse,
+		protocol.MaxTokenSize()-LengthPrefixSize,
+	); err != nil {
+		return nil, err
+	}
+
+	return AppendLengthPrefixed(nil, marshalledResponse), nil
+}
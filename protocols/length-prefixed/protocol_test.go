@@ -0,0 +1,146 @@
+package lengthPrefixedProtocol
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+	separatorBasedProtocol "github.com/thewizardplusplus/go-tcp-server/protocols/separator-based"
+)
+
+func TestProtocol_interface(test *testing.T) {
+	assert.Implements(
+		test,
+		(*tcpServer.ServerProtocol[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		])(nil),
+		Protocol{},
+	)
+	assert.Implements(
+		test,
+		(*tcpServer.ClientProtocol[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		])(nil),
+		Protocol{},
+	)
+}
+
+func TestAppendLengthPrefixed(test *testing.T) {
+	got := AppendLengthPrefixed([]byte("prefix"), []byte("dummy"))
+
+	assert.Equal(test, []byte("prefix\x00\x00\x00\x05dummy"), got)
+}
+
+func TestProtocol_ExtractToken(test *testing.T) {
+	type args struct {
+		data []byte
+	}
+
+	for _, data := range []struct {
+		name       string
+		args       args
+		wantTokens [][]byte
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/several tokens",
+			args: args{
+				data: []byte("\x00\x00\x00\x03one\x00\x00\x00\x00\x00\x00\x00\x05three"),
+			},
+			wantTokens: [][]byte{[]byte("one"), {}, []byte("three")},
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "success/empty data",
+			args: args{
+				data: nil,
+			},
+			wantTokens: nil,
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "error/incomplete length",
+			args: args{
+				data: []byte("\x00\x00\x00\x03one\x00\x00"),
+			},
+			wantTokens: [][]byte{[]byte("one")},
+			wantErr:    assert.Error,
+		},
+		{
+			name: "error/incomplete message",
+			args: args{
+				data: []byte("\x00\x00\x00\x05thr"),
+			},
+			wantTokens: nil,
+			wantErr:    assert.Error,
+		},
+		{
+			name: "error/too long message",
+			args: args{
+				data: []byte("\x7f\x00\x00\x00"),
+			},
+			wantTokens: nil,
+			wantErr:    assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			scanner := tcpServer.InitializeScanner(
+				tcpServer.InitializeScannerParams[
+					defaultProtocolModels.Request,
+					defaultProtocolModels.Response,
+				]{
+					Reader:       bytes.NewReader(data.args.data),
+					BaseProtocol: NewProtocol(ProtocolOptions{}),
+				},
+			)
+
+			var gotTokens [][]byte
+			for scanner.Scan() {
+				gotTokens = append(gotTokens, bytes.Clone(scanner.Bytes()))
+			}
+
+			assert.Equal(test, data.wantTokens, gotTokens)
+			data.wantErr(test, scanner.Err())
+		})
+	}
+}
+
+func TestProtocol_MarshalRequest(test *testing.T) {
+	action, err := defaultProtocolModelValueTypes.NewAction([]byte("dummy"))
+	require.NoError(test, err)
+
+	request, err := defaultProtocolModels.NewRequestBuilder().
+		SetAction(action).
+		SetBody(defaultProtocolModelValueTypes.NewBody([]byte("body"))).
+		Build()
+	require.NoError(test, err)
+
+	protocol := NewProtocol(ProtocolOptions{
+		MessageFormat: separatorBasedProtocol.NewMessageFormat(
+			separatorBasedProtocol.SeparationParams{
+				MessageSeparator:        []byte("\n"),
+				MessagePartSeparator:    []byte("|"),
+				HeaderSeparator:         []byte("&"),
+				HeaderKeyValueSeparator: []byte("="),
+			},
+		),
+	})
+	got, err := protocol.MarshalRequest(request)
+	require.NoError(test, err)
+
+	assert.Equal(test, []byte("\x00\x00\x00\x0bdummy||body"), got)
+
+	offsetToNextToken, token, err := protocol.ExtractToken(got, true)
+	require.NoError(test, err)
+	assert.Equal(test, len(got), offsetToNextToken)
+
+	gotRequest, err := protocol.ParseRequest(token)
+	require.NoError(test, err)
+	assert.Equal(test, request, gotRequest)
+}
@@ -0,0 +1,241 @@
+package mapBasedProtocol
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+const (
+	IntroductionKey = "introduction"
+	HeadersKey      = "headers"
+	BodyKey         = "body"
+)
+
+// Encoding writes and reads the values of the specific binary format;
+// the keys are written as strings and the other values as binaries
+type Encoding interface {
+	AppendMapHeader(buffer []byte, length int) []byte
+	AppendString(buffer []byte, text string) []byte
+	AppendBinary(buffer []byte, data []byte) []byte
+	NewDecoder(data []byte) Decoder
+}
+
+type Decoder interface {
+	IsEmpty() bool
+	ReadMapHeader() (int, error)
+	// ReadBytes reads both the strings and the binaries
+	ReadBytes() ([]byte, error)
+}
+
+// MessageFormat represents the message as a map with string keys;
+// the introduction and the body are binaries and the headers are a map
+// with binary keys and values; the headers and the body may be omitted
+type MessageFormat struct {
+	encoding Encoding
+}
+
+func NewMessageFormat(encoding Encoding) MessageFormat {
+	return MessageFormat{
+		encoding: encoding,
+	}
+}
+
+func (format MessageFormat) ParseMessage(
+	data []byte,
+) (defaultProtocolModels.Message, error) {
+	decoder := format.encoding.NewDecoder(data)
+	messageBuilder := defaultProtocolModels.NewMessageBuilder()
+
+	fieldCount, err := decoder.ReadMapHeader()
+	if err != nil {
+		return defaultProtocolModels.Message{}, fmt.Errorf(
+			"unable to read the message map: %w",
+			err,
+		)
+	}
+
+	for fieldIndex := range fieldCount {
+		fieldKey, err := decoder.ReadBytes()
+		if err != nil {
+			return defaultProtocolModels.Message{}, fmt.Errorf(
+				"unable to read the key of the field #%d: %w",
+				fieldIndex,
+				err,
+			)
+		}
+
+		switch string(fieldKey) {
+		case IntroductionKey:
+			rawIntroduction, err := decoder.ReadBytes()
+			if err != nil {
+				return defaultProtocolModels.Message{}, fmt.Errorf(
+					"unable to read the introduction: %w",
+					err,
+				)
+			}
+
+			introduction, err :=
+				defaultProtocolModelValueTypes.NewIntroduction(rawIntroduction)
+			if err != nil {
+				return defaultProtocolModels.Message{}, fmt.Errorf(
+					"unable to construct the introduction: %w",
+					err,
+				)
+			}
+
+			messageBuilder.SetIntroduction(introduction)
+		case HeadersKey:
+			headers, err := parseHeaders(decoder)
+			if err != nil {
+				return defaultProtocolModels.Message{}, fmt.Errorf(
+					"unable to parse the headers: %w",
+					err,
+				)
+			}
+
+			messageBuilder.SetHeaders(headers)
+		case BodyKey:
+			rawBody, err := decoder.ReadBytes()
+			if err != nil {
+				return defaultProtocolModels.Message{}, fmt.Errorf(
+					"unable to read the body: %w",
+					err,
+				)
+			}
+
+			messageBuilder.SetBody(defaultProtocolModelValueTypes.NewBody(rawBody))
+		default:
+			return defaultProtocolModels.Message{}, fmt.Errorf(
+				"unknown field %q",
+				fieldKey,
+			)
+		}
+	}
+	if !decoder.IsEmpty() {
+		return defaultProtocolModels.Message{},
+			errors.New("message has extra data")
+	}
+
+	message, err := messageBuilder.Build()
+	if err != nil {
+		return defaultProtocolModels.Message{}, fmt.Errorf(
+			"unable to build the message: %w",
+			err,
+		)
+	}
+
+	return message, nil
+}
+
+func (format MessageFormat) MarshalMessage(
+	message defaultProtocolModels.Message,
+) ([]byte, error) {
+	headers, hasHeaders := message.Headers().Get()
+	body, hasBody := message.Body().Get()
+
+	fieldCount := 1
+	if hasHeaders {
+		fieldCount++
+	}
+	if hasBody {
+		fieldCount++
+	}
+
+	encoding := format.encoding
+	marshalledMessage := encoding.AppendMapHeader(nil, fieldCount)
+	marshalledMessage = encoding.AppendString(marshalledMessage, IntroductionKey)
+	marshalledMessage =
+		encoding.AppendBinary(marshalledMessage, message.Introduction().ToBytes())
+
+	if hasHeaders {
+		marshalledHeaders := make([][]byte, 0, len(headers.ToMap()))
+		for headerKey, headerValue := range headers.ToMap() {
+			rawHeaderKey, err := headerKey.ToBytes()
+			if err != nil {
+				return nil, fmt.Errorf("unable to convert the header key to bytes: %w", err)
+			}
+
+			marshalledHeader := encoding.AppendBinary(nil, rawHeaderKey)
+			marshalledHeader =
+				encoding.AppendBinary(marshalledHeader, headerValue.ToBytes())
+			marshalledHeaders = append(marshalledHeaders, marshalledHeader)
+		}
+		slices.SortStableFunc(marshalledHeaders, func(a []byte, b []byte) int {
+			return bytes.Compare(a, b)
+		})
+
+		marshalledMessage = encoding.AppendString(marshalledMessage, HeadersKey)
+		marshalledMessage =
+			encoding.AppendMapHeader(marshalledMessage, len(marshalledHeaders))
+		for _, marshalledHeader := range marshalledHeaders {
+			marshalledMessage = append(marshalledMessage, marshalledHeader...)
+		}
+	}
+
+	if hasBody {
+		marshalledMessage = encoding.AppendString(marshalledMessage, BodyKey)
+		marshalledMessage = encoding.AppendBinary(marshalledMessage, body.ToBytes())
+	}
+
+	return marshalledMessage, nil
+}
+
+func parseHeaders(
+	decoder Decoder,
+) (defaultProtocolModelValueTypes.Headers, error) {
+	headerCount, err := decoder.ReadMapHeader()
+	if err != nil {
+		return defaultProtocolModelValueTypes.Headers{}, fmt.Errorf(
+			"unable to read the headers map: %w",
+			err,
+		)
+	}
+
+	rawHeaders :=
+		make(map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue, headerCount) //nolint:lll
+	for headerIndex := range headerCount {
+		rawHeaderKey, err := decoder.ReadBytes()
+		if err != nil {
+			return defaultProtocolModelValueTypes.Headers{}, fmt.Errorf(
+				"unable to read the key of the header #%d: %w",
+				headerIndex,
+				err,
+			)
+		}
+
+		headerKey, err := defaultProtocolModelValueTypes.NewHeaderKey(rawHeaderKey)
+		if err != nil {
+			return defaultProtocolModelValueTypes.Headers{}, fmt.Errorf(
+				"unable to construct the header key: %w",
+				err,
+			)
+		}
+
+		rawHeaderValue, err := decoder.ReadBytes()
+		if err != nil {
+			return defaultProtocolModelValueTypes.Headers{}, fmt.Errorf(
+				"unable to read the value of the header #%d: %w",
+				headerIndex,
+				err,
+			)
+		}
+
+		headerValue, err :=
+			defaultProtocolModelValueTypes.NewHeaderValue(rawHeaderValue)
+		if err != nil {
+			return defaultProtocolModelValueTypes.Headers{}, fmt.Errorf(
+				"unable to construct the header value: %w",
+				err,
+			)
+		}
+
+		rawHeaders[headerKey] = headerValue
+	}
+
+	return defaultProtocolModelValueTypes.NewHeaders(rawHeaders), nil
+}
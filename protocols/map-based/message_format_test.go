@@ -0,0 +1,221 @@
+package mapBasedProtocol
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	defaultProtocol "github.com/thewizardplusplus/go-tcp-server/protocols/default"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+func TestMessageFormat_interface(test *testing.T) {
+	assert.Implements(
+		test,
+		(*defaultProtocol.MessageFormat)(nil),
+		NewMessageFormat(testEncoding{}),
+	)
+}
+
+func TestMessageFormat_MarshalMessage(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		message func(test *testing.T) defaultProtocolModels.Message
+		want    []byte
+	}{
+		{
+			name: "success/minimal message",
+			message: func(test *testing.T) defaultProtocolModels.Message {
+				introduction, err :=
+					defaultProtocolModelValueTypes.NewIntroduction([]byte("dummy"))
+				require.NoError(test, err)
+
+				message, err := defaultProtocolModels.NewMessageBuilder().
+					SetIntroduction(introduction).
+					Build()
+				require.NoError(test, err)
+
+				return message
+			},
+			want: []byte("m\x01s\x0cintroductionb\x05dummy"),
+		},
+		{
+			name: "success/full message",
+			message: func(test *testing.T) defaultProtocolModels.Message {
+				introduction, err :=
+					defaultProtocolModelValueTypes.NewIntroduction([]byte("dummy"))
+				require.NoError(test, err)
+
+				message, err := defaultProtocolModels.NewMessageBuilder().
+					SetIntroduction(introduction).
+					SetHeaders(defaultProtocolModelValueTypes.NewHeaders(
+						map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue{ //nolint:lll
+							defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("two")): defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("2")), //nolint:lll
+							defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("one")): defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("1")), //nolint:lll
+						},
+					)).
+					SetBody(defaultProtocolModelValueTypes.NewBody([]byte("b\x00dy"))).
+					Build()
+				require.NoError(test, err)
+
+				return message
+			},
+			want: []byte(
+				"m\x03" +
+					"s\x0cintroductionb\x05dummy" +
+					"s\x07headersm\x02b\x03oneb\x011b\x03twob\x012" +
+					"s\x04bodyb\x04b\x00dy",
+			),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			format := NewMessageFormat(testEncoding{})
+			message := data.message(test)
+
+			got, err := format.MarshalMessage(message)
+			require.NoError(test, err)
+			assert.Equal(test, data.want, got)
+
+			gotMessage, err := format.ParseMessage(got)
+			require.NoError(test, err)
+			assert.Equal(test, message, gotMessage)
+		})
+	}
+}
+
+func TestMessageFormat_ParseMessage_errors(test *testing.T) {
+	for _, data := range []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "error/empty data",
+			data: nil,
+		},
+		{
+			name: "error/not a map",
+			data: []byte("s\x05dummy"),
+		},
+		{
+			name: "error/incomplete map",
+			data: []byte("m\x02s\x0cintroductionb\x05dummy"),
+		},
+		{
+			name: "error/incomplete field",
+			data: []byte("m\x01s\x0cintroductionb\x05dum"),
+		},
+		{
+			name: "error/unknown field",
+			data: []byte("m\x01s\x05dummyb\x05dummy"),
+		},
+		{
+			name: "error/missed introduction",
+			data: []byte("m\x01s\x04bodyb\x04body"),
+		},
+		{
+			name: "error/empty introduction",
+			data: []byte("m\x01s\x0cintroductionb\x00"),
+		},
+		{
+			name: "error/headers are not a map",
+			data: []byte(
+				"m\x02s\x0cintroductionb\x05dummys\x07headersb\x03one",
+			),
+		},
+		{
+			name: "error/empty header key",
+			data: []byte(
+				"m\x02s\x0cintroductionb\x05dummys\x07headersm\x01b\x00b\x011",
+			),
+		},
+		{
+			name: "error/missed header value",
+			data: []byte(
+				"m\x02s\x0cintroductionb\x05dummys\x07headersm\x01b\x03one",
+			),
+		},
+		{
+			name: "error/extra data",
+			data: []byte("m\x01s\x0cintroductionb\x05dummyb\x00"),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			_, err := NewMessageFormat(testEncoding{}).ParseMessage(data.data)
+
+			assert.Error(test, err)
+		})
+	}
+}
+
+// testEncoding prefixes each value with its type tag
+// and its length (or the item count for the maps) in a single byte
+type testEncoding struct{}
+
+func (testEncoding) AppendMapHeader(buffer []byte, length int) []byte {
+	return append(buffer, 'm', byte(length))
+}
+
+func (testEncoding) AppendString(buffer []byte, text string) []byte {
+	buffer = append(buffer, 's', byte(len(text)))
+	return append(buffer, text...)
+}
+
+func (testEncoding) AppendBinary(buffer []byte, data []byte) []byte {
+	buffer = append(buffer, 'b', byte(len(data)))
+	return append(buffer, data...)
+}
+
+func (testEncoding) NewDecoder(data []byte) Decoder {
+	return &testDecoder{data: data}
+}
+
+type testDecoder struct {
+	data []byte
+}
+
+func (decoder *testDecoder) IsEmpty() bool {
+	return len(decoder.data) == 0
+}
+
+func (decoder *testDecoder) ReadMapHeader() (int, error) {
+	tag, length, err := decoder.readHeader()
+	if err != nil {
+		return 0, err
+	}
+	if tag != 'm' {
+		return 0, errors.New("not a map")
+	}
+
+	return length, nil
+}
+
+func (decoder *testDecoder) ReadBytes() ([]byte, error) {
+	tag, length, err := decoder.readHeader()
+	if err != nil {
+		return nil, err
+	}
+	if tag != 's' && tag != 'b' {
+		return nil, errors.New("neither a string nor a binary")
+	}
+	if len(decoder.data) < length {
+		return nil, errors.New("incomplete data")
+	}
+
+	data := decoder.data[:length]
+	decoder.data = decoder.data[length:]
+
+	return data, nil
+}
+
+func (decoder *testDecoder) readHeader() (tag byte, length int, err error) {
+	if len(decoder.data) < 2 {
+		return 0, 0, errors.New("incomplete header")
+	}
+
+	tag, length = decoder.data[0], int(decoder.data[1])
+	decoder.data = decoder.data[2:]
+
+	return tag, length, nil
+}
@@ -0,0 +1,172 @@
+package msgpackProtocol
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// only the subset of MessagePack used by the message format is supported:
+// maps, strings and binaries
+
+const (
+	fixMapMask      = 0x80
+	fixMapMaxLength = 0x0f
+	map16Prefix     = 0xde
+	map32Prefix     = 0xdf
+
+	fixStrMask      = 0xa0
+	fixStrMaxLength = 0x1f
+	str8Prefix      = 0xd9
+	str16Prefix     = 0xda
+	str32Prefix     = 0xdb
+
+	bin8Prefix  = 0xc4
+	bin16Prefix = 0xc5
+	bin32Prefix = 0xc6
+)
+
+func AppendMapHeader(buffer []byte, length int) []byte {
+	switch {
+	case length <= fixMapMaxLength:
+		return append(buffer, fixMapMask|byte(length))
+	case length <= math.MaxUint16:
+		buffer = append(buffer, map16Prefix)
+		return binary.BigEndian.AppendUint16(buffer, uint16(length))
+	default:
+		buffer = append(buffer, map32Prefix)
+		return binary.BigEndian.AppendUint32(buffer, uint32(length))
+	}
+}
+
+func AppendString(buffer []byte, text string) []byte {
+	switch length := len(text); {
+	case length <= fixStrMaxLength:
+		buffer = append(buffer, fixStrMask|byte(length))
+	case length <= math.MaxUint8:
+		buffer = append(buffer, str8Prefix, byte(length))
+	case length <= math.MaxUint16:
+		buffer = append(buffer, str16Prefix)
+		buffer = binary.BigEndian.AppendUint16(buffer, uint16(length))
+	default:
+		buffer = append(buffer, str32Prefix)
+		buffer = binary.BigEndian.AppendUint32(buffer, uint32(length))
+	}
+
+	return append(buffer, text...)
+}
+
+func AppendBinary(buffer []byte, data []byte) []byte {
+	switch length := len(data); {
+	case length <= math.MaxUint8:
+		buffer = append(buffer, bin8Prefix, byte(length))
+	case length <= math.MaxUint16:
+		buffer = append(buffer, bin16Prefix)
+		buffer = binary.BigEndian.AppendUint16(buffer, uint16(length))
+	default:
+		buffer = append(buffer, bin32Prefix)
+		buffer = binary.BigEndian.AppendUint32(buffer, uint32(length))
+	}
+
+	return append(buffer, data...)
+}
+
+type Decoder struct {
+	data []byte
+}
+
+func NewDecoder(data []byte) *Decoder {
+	return &Decoder{
+		data: data,
+	}
+}
+
+func (decoder *Decoder) IsEmpty() bool {
+	return len(decoder.data) == 0
+}
+
+func (decoder *Decoder) ReadMapHeader() (int, error) {
+	prefix, err := decoder.readByte()
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case prefix&^fixMapMaxLength == fixMapMask:
+		return int(prefix & fixMapMaxLength), nil
+	case prefix == map16Prefix:
+		return decoder.readLength(2)
+	case prefix == map32Prefix:
+		return decoder.readLength(4)
+	default:
+		return 0, fmt.Errorf("expected a map, got the prefix 0x%02x", prefix)
+	}
+}
+
+// ReadBytes reads either a string or a binary
+func (decoder *Decoder) ReadBytes() ([]byte, error) {
+	prefix, err := decoder.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	var length int
+	switch {
+	case prefix&^fixStrMaxLength == fixStrMask:
+		length = int(prefix & fixStrMaxLength)
+	case prefix == str8Prefix || prefix == bin8Prefix:
+		length, err = decoder.readLength(1)
+	case prefix == str16Prefix || prefix == bin16Prefix:
+		length, err = decoder.readLength(2)
+	case prefix == str32Prefix || prefix == bin32Prefix:
+		length, err = decoder.readLength(4)
+	default:
+		return nil, fmt.Errorf(
+			"expected a string or a binary, got the prefix 0x%02x",
+			prefix,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decoder.readData(length)
+}
+
+func (decoder *Decoder) readByte() (byte, error) {
+	data, err := decoder.readData(1)
+	if err != nil {
+		return 0, err
+	}
+
+	return data[0], nil
+}
+
+func (decoder *Decoder) readLength(size int) (int, error) {
+	data, err := decoder.readData(size)
+	if err != nil {
+		return 0, err
+	}
+
+	var length uint64
+	for _, dataByte := range data {
+		length = length<<8 | uint64(dataByte)
+	}
+	if length > uint64(len(decoder.data)) {
+		return 0, fmt.Errorf("length %d exceeds the remaining data", length)
+	}
+
+	return int(length), nil
+}
+
+func (decoder *Decoder) readData(size int) ([]byte, error) {
+	if len(decoder.data) < size {
+		return nil, errors.New("data is incomplete")
+	}
+
+	data := decoder.data[:size]
+	decoder.data = decoder.data[size:]
+
+	return data, nil
+}
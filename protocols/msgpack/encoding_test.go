@@ -0,0 +1,168 @@
+package msgpackProtocol
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendMapHeader(test *testing.T) {
+	for _, data := range []struct {
+		name   string
+		length int
+		want   []byte
+	}{
+		{
+			name:   "success/fixmap",
+			length: 3,
+			want:   []byte{0x83},
+		},
+		{
+			name:   "success/map 16",
+			length: 16,
+			want:   []byte{0xde, 0x00, 0x10},
+		},
+		{
+			name:   "success/map 32",
+			length: 0x10000,
+			want:   []byte{0xdf, 0x00, 0x01, 0x00, 0x00},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := AppendMapHeader(nil, data.length)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestAppendString(test *testing.T) {
+	for _, data := range []struct {
+		name       string
+		text       string
+		wantPrefix []byte
+	}{
+		{
+			name:       "success/fixstr",
+			text:       "dummy",
+			wantPrefix: []byte{0xa5},
+		},
+		{
+			name:       "success/str 8",
+			text:       strings.Repeat("d", 32),
+			wantPrefix: []byte{0xd9, 0x20},
+		},
+		{
+			name:       "success/str 16",
+			text:       strings.Repeat("d", 256),
+			wantPrefix: []byte{0xda, 0x01, 0x00},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := AppendString(nil, data.text)
+
+			assert.Equal(test, append(data.wantPrefix, data.text...), got)
+		})
+	}
+}
+
+func TestAppendBinary(test *testing.T) {
+	for _, data := range []struct {
+		name       string
+		data       []byte
+		wantPrefix []byte
+	}{
+		{
+			name:       "success/bin 8",
+			data:       []byte("dummy"),
+			wantPrefix: []byte{0xc4, 0x05},
+		},
+		{
+			name:       "success/bin 16",
+			data:       []byte(strings.Repeat("d", 256)),
+			wantPrefix: []byte{0xc5, 0x01, 0x00},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := AppendBinary(nil, data.data)
+
+			assert.Equal(test, append(data.wantPrefix, data.data...), got)
+		})
+	}
+}
+
+func TestDecoder(test *testing.T) {
+	data := AppendMapHeader(nil, 2)
+	data = AppendString(data, "one")
+	data = AppendBinary(data, []byte(strings.Repeat("d", 300)))
+	data = AppendString(data, strings.Repeat("t", 40))
+	data = AppendBinary(data, nil)
+
+	decoder := NewDecoder(data)
+
+	length, err := decoder.ReadMapHeader()
+	require.NoError(test, err)
+	assert.Equal(test, 2, length)
+
+	for _, want := range [][]byte{
+		[]byte("one"),
+		[]byte(strings.Repeat("d", 300)),
+		[]byte(strings.Repeat("t", 40)),
+		{},
+	} {
+		got, err := decoder.ReadBytes()
+		require.NoError(test, err)
+		assert.Equal(test, want, got)
+	}
+
+	assert.True(test, decoder.IsEmpty())
+}
+
+func TestDecoder_errors(test *testing.T) {
+	for _, data := range []struct {
+		name string
+		data []byte
+		read func(decoder *Decoder) error
+	}{
+		{
+			name: "error/not a map",
+			data: []byte{0xa1, 'a'},
+			read: func(decoder *Decoder) error {
+				_, err := decoder.ReadMapHeader()
+				return err
+			},
+		},
+		{
+			name: "error/not a string",
+			data: []byte{0x81},
+			read: func(decoder *Decoder) error {
+				_, err := decoder.ReadBytes()
+				return err
+			},
+		},
+		{
+			name: "error/incomplete data",
+			data: []byte{0xc4, 0x05, 'd'},
+			read: func(decoder *Decoder) error {
+				_, err := decoder.ReadBytes()
+				return err
+			},
+		},
+		{
+			name: "error/empty data",
+			data: nil,
+			read: func(decoder *Decoder) error {
+				_, err := decoder.ReadMapHeader()
+				return err
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			err := data.read(NewDecoder(data.data))
+
+			assert.Error(test, err)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package msgpackProtocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	defaultProtocol "github.com/thewizardplusplus/go-tcp-server/protocols/default"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+func TestMessageFormat_interface(test *testing.T) {
+	assert.Implements(test, (*defaultProtocol.MessageFormat)(nil), MessageFormat{})
+}
+
+func TestMessageFormat_MarshalMessage(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		message func(test *testing.T) defaultProtocolModels.Message
+		want    []byte
+	}{
+		{
+			name: "success/minimal message",
+			message: func(test *testing.T) defaultProtocolModels.Message {
+				introduction, err :=
+					defaultProtocolModelValueTypes.NewIntroduction([]byte("dummy"))
+				require.NoError(test, err)
+
+				message, err := defaultProtocolModels.NewMessageBuilder().
+					SetIntroduction(introduction).
+					Build()
+				require.NoError(test, err)
+
+				return message
+			},
+			want: []byte("\x81\xacintroduction\xc4\x05dummy"),
+		},
+		{
+			name: "success/full message",
+			message: func(test *testing.T) defaultProtocolModels.Message {
+				introduction, err :=
+					defaultProtocolModelValueTypes.NewIntroduction([]byte("dummy"))
+				require.NoError(test, err)
+
+				message, err := defaultProtocolModels.NewMessageBuilder().
+					SetIntroduction(introduction).
+					SetHeaders(defaultProtocolModelValueTypes.NewHeaders(
+						map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue{ //nolint:lll
+							defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("two")): defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("2")), //nolint:lll
+							defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("one")): defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("1")), //nolint:lll
+						},
+					)).
+					SetBody(defaultProtocolModelValueTypes.NewBody([]byte("b\x00dy"))).
+					Build()
+				require.NoError(test, err)
+
+				return message
+			},
+			want: []byte(
+				"\x83" +
+					"\xacintroduction\xc4\x05dummy" +
+					"\xa7headers\x82\xc4\x03one\xc4\x011\xc4\x03two\xc4\x012" +
+					"\xa4body\xc4\x04b\x00dy",
+			),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			message := data.message(test)
+
+			got, err := MessageFormat{}.MarshalMessage(message)
+			require.NoError(test, err)
+			assert.Equal(test, data.want, got)
+
+			gotMessage, err := MessageFormat{}.ParseMessage(got)
+			require.NoError(test, err)
+			assert.Equal(test, message, gotMessage)
+		})
+	}
+}
+
+func TestMessageFormat_ParseMessage_errors(test *testing.T) {
+	for _, data := range []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "error/not a map",
+			data: []byte("\xa5dummy"),
+		},
+		{
+			name: "error/unknown field",
+			data: []byte("\x81\xa5dummy\xc4\x05dummy"),
+		},
+		{
+			name: "error/missed introduction",
+			data: []byte("\x81\xa4body\xc4\x04body"),
+		},
+		{
+			name: "error/empty header key",
+			data: []byte(
+				"\x82\xacintroduction\xc4\x05dummy\xa7headers\x81\xc4\x00\xc4\x011",
+			),
+		},
+		{
+			name: "error/extra data",
+			data: []byte("\x81\xacintroduction\xc4\x05dummy\xc0"),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			_, err := MessageFormat{}.ParseMessage(data.data)
+
+			assert.Error(test, err)
+		})
+	}
+}
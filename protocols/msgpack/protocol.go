@@ -0,0 +1,13 @@
+package msgpackProtocol
+
+import (
+	lengthPrefixedProtocol "github.com/thewizardplusplus/go-tcp-server/protocols/length-prefixed"
+)
+
+func NewProtocol() lengthPrefixedProtocol.Protocol {
+	return lengthPrefixedProtocol.NewProtocol(
+		lengthPrefixedProtocol.ProtocolOptions{
+			MessageFormat: NewMessageFormat(),
+		},
+	)
+}
@@ -0,0 +1,142 @@
+package netstringProtocol
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+const (
+	messagePartCount = 3
+	headerPartCount  = 2
+)
+
+// MessageFormat represents the message as three netstrings:
+// the introduction, the headers and the body;
+// the headers are a concatenation of netstrings,
+// each of which holds the netstrings of the header key and value
+type MessageFormat struct{}
+
+func NewMessageFormat() MessageFormat {
+	return MessageFormat{}
+}
+
+func (format MessageFormat) ParseMessage(
+	data []byte,
+) (defaultProtocolModels.Message, error) {
+	messageParts, err := SplitNetstrings(data)
+	if err != nil {
+		return defaultProtocolModels.Message{}, fmt.Errorf(
+			"unable to split the message parts: %w",
+			err,
+		)
+	}
+	if len(messageParts) != messagePartCount {
+		return defaultProtocolModels.Message{}, fmt.Errorf(
+			"invalid message part count: %d",
+			len(messageParts),
+		)
+	}
+
+	introduction, err :=
+		defaultProtocolModelValueTypes.NewIntroduction(messageParts[0])
+	if err != nil {
+		return defaultProtocolModels.Message{}, fmt.Errorf(
+			"unable to construct the introduction: %w",
+			err,
+		)
+	}
+
+	marshalledHeaders, err := SplitNetstrings(messageParts[1])
+	if err != nil {
+		return defaultProtocolModels.Message{}, fmt.Errorf(
+			"unable to split the headers: %w",
+			err,
+		)
+	}
+
+	rawHeaders :=
+		make(map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue) //nolint:lll
+	for marshalledHeaderIndex, marshalledHeader := range marshalledHeaders {
+		headerParts, err := SplitNetstrings(marshalledHeader)
+		if err != nil {
+			return defaultProtocolModels.Message{}, fmt.Errorf(
+				"unable to split the header #%d: %w",
+				marshalledHeaderIndex,
+				err,
+			)
+		}
+		if len(headerParts) != headerPartCount {
+			return defaultProtocolModels.Message{}, fmt.Errorf(
+				"header #%d has invalid part count: %d",
+				marshalledHeaderIndex,
+				len(headerParts),
+			)
+		}
+
+		headerKey, err := defaultProtocolModelValueTypes.NewHeaderKey(headerParts[0])
+		if err != nil {
+			return defaultProtocolModels.Message{}, fmt.Errorf(
+				"unable to construct the header key: %w",
+				err,
+			)
+		}
+
+		headerValue, err :=
+			defaultProtocolModelValueTypes.NewHeaderValue(headerParts[1])
+		if err != nil {
+			return defaultProtocolModels.Message{}, fmt.Errorf(
+				"unable to construct the header value: %w",
+				err,
+			)
+		}
+
+		rawHeaders[headerKey] = headerValue
+	}
+
+	message, err := defaultProtocolModels.NewMessageBuilder().
+		SetIntroduction(introduction).
+		SetHeaders(defaultProtocolModelValueTypes.NewHeaders(rawHeaders)).
+		SetBody(defaultProtocolModelValueTypes.NewBody(messageParts[2])).
+		Build()
+	if err != nil {
+		return defaultProtocolModels.Message{}, fmt.Errorf(
+			"unable to build the message: %w",
+			err,
+		)
+	}
+
+	return message, nil
+}
+
+func (format MessageFormat) MarshalMessage(
+	message defaultProtocolModels.Message,
+) ([]byte, error) {
+	marshalledHeaders :=
+		make([][]byte, 0, len(message.Headers().OrEmpty().ToMap()))
+	for headerKey, headerValue := range message.Headers().OrEmpty().ToMap() {
+		rawHeaderKey, err := headerKey.ToBytes()
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert the header key to bytes: %w", err)
+		}
+
+		marshalledHeader := AppendNetstring(nil, rawHeaderKey)
+		marshalledHeader = AppendNetstring(marshalledHeader, headerValue.ToBytes())
+		marshalledHeaders =
+			append(marshalledHeaders, AppendNetstring(nil, marshalledHeader))
+	}
+	slices.SortStableFunc(marshalledHeaders, func(a []byte, b []byte) int {
+		return bytes.Compare(a, b)
+	})
+
+	marshalledMessage :=
+		AppendNetstring(nil, message.Introduction().ToBytes())
+	marshalledMessage =
+		AppendNetstring(marshalledMessage, bytes.Join(marshalledHeaders, nil))
+	marshalledMessage =
+		AppendNetstring(marshalledMessage, message.Body().OrEmpty().ToBytes())
+	return marshalledMessage, nil
+}
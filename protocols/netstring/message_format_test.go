@@ -0,0 +1,147 @@
+package netstringProtocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	defaultProtocol "github.com/thewizardplusplus/go-tcp-server/protocols/default"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+func TestMessageFormat_interface(test *testing.T) {
+	assert.Implements(test, (*defaultProtocol.MessageFormat)(nil), MessageFormat{})
+}
+
+func TestMessageFormat_ParseMessage(test *testing.T) {
+	type args struct {
+		data []byte
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    defaultProtocolModels.Message
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/minimal message",
+			args: args{
+				data: []byte("5:dummy,0:,0:,"),
+			},
+			want: func() defaultProtocolModels.Message {
+				introduction, err :=
+					defaultProtocolModelValueTypes.NewIntroduction([]byte("dummy"))
+				require.NoError(test, err)
+
+				message, err := defaultProtocolModels.NewMessageBuilder().
+					SetIntroduction(introduction).
+					Build()
+				require.NoError(test, err)
+
+				return message
+			}(),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/full message with binary data",
+			args: args{
+				data: []byte(
+					"13:intro,duction,35:12:3:one,3:t:o,,15:5:three,4:f\x00ur,,,4:b\ndy,",
+				),
+			},
+			want: func() defaultProtocolModels.Message {
+				introduction, err :=
+					defaultProtocolModelValueTypes.NewIntroduction([]byte("intro,duction"))
+				require.NoError(test, err)
+
+				message, err := defaultProtocolModels.NewMessageBuilder().
+					SetIntroduction(introduction).
+					SetHeaders(defaultProtocolModelValueTypes.NewHeaders(
+						map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue{ //nolint:lll
+							defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("one")):   defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("t:o")),     //nolint:lll
+							defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("three")): defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("f\x00ur")), //nolint:lll
+						},
+					)).
+					SetBody(defaultProtocolModelValueTypes.NewBody([]byte("b\ndy"))).
+					Build()
+				require.NoError(test, err)
+
+				return message
+			}(),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/invalid message part count",
+			args: args{
+				data: []byte("5:dummy,0:,"),
+			},
+			want:    defaultProtocolModels.Message{},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/empty introduction",
+			args: args{
+				data: []byte("0:,0:,0:,"),
+			},
+			want:    defaultProtocolModels.Message{},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/invalid header part count",
+			args: args{
+				data: []byte("5:dummy,8:5:3:one,,,0:,"),
+			},
+			want:    defaultProtocolModels.Message{},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/empty header key",
+			args: args{
+				data: []byte("5:dummy,10:7:0:,2:on,,0:,"),
+			},
+			want:    defaultProtocolModels.Message{},
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := MessageFormat{}.ParseMessage(data.args.data)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestMessageFormat_MarshalMessage(test *testing.T) {
+	introduction, err :=
+		defaultProtocolModelValueTypes.NewIntroduction([]byte("intro,duction"))
+	require.NoError(test, err)
+
+	message, err := defaultProtocolModels.NewMessageBuilder().
+		SetIntroduction(introduction).
+		SetHeaders(defaultProtocolModelValueTypes.NewHeaders(
+			map[defaultProtocolModelValueTypes.HeaderKey]defaultProtocolModelValueTypes.HeaderValue{ //nolint:lll
+				defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("three")): defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("f\x00ur")), //nolint:lll
+				defaultProtocolModelValueTypes.MustNewHeaderKey([]byte("one")):   defaultProtocolModelValueTypes.MustNewHeaderValue([]byte("t:o")),     //nolint:lll
+			},
+		)).
+		SetBody(defaultProtocolModelValueTypes.NewBody([]byte("b\ndy"))).
+		Build()
+	require.NoError(test, err)
+
+	got, err := MessageFormat{}.MarshalMessage(message)
+	require.NoError(test, err)
+
+	assert.Equal(
+		test,
+		[]byte("13:intro,duction,35:12:3:one,3:t:o,,15:5:three,4:f\x00ur,,,4:b\ndy,"),
+		got,
+	)
+
+	gotMessage, err := MessageFormat{}.ParseMessage(got)
+	require.NoError(test, err)
+
+	assert.Equal(test, message, gotMessage)
+}
@@ -0,0 +1,107 @@
+package netstringProtocol
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const (
+	lengthSeparator = ':'
+	netstringEnd    = ','
+	// enough for any length that fits in a token
+	maxLengthDigitCount = 10
+)
+
+func AppendNetstring(buffer []byte, data []byte) []byte {
+	buffer = strconv.AppendInt(buffer, int64(len(data)), 10)
+	buffer = append(buffer, lengthSeparator)
+	buffer = append(buffer, data...)
+	buffer = append(buffer, netstringEnd)
+
+	return buffer
+}
+
+// ScanNetstring returns `false` if the data is incomplete yet
+func ScanNetstring(
+	data []byte,
+) (payload []byte, size int, isComplete bool, err error) {
+	separatorIndex := bytes.IndexByte(data, lengthSeparator)
+	if separatorIndex == -1 {
+		if err := checkLengthDigits(data); err != nil {
+			return nil, 0, false, err
+		}
+
+		return nil, 0, false, nil
+	}
+
+	rawLength := data[:separatorIndex]
+	if len(rawLength) == 0 {
+		return nil, 0, false, errors.New("netstring length cannot be empty")
+	}
+	if err := checkLengthDigits(rawLength); err != nil {
+		return nil, 0, false, err
+	}
+	if len(rawLength) > 1 && rawLength[0] == '0' {
+		return nil, 0, false,
+			errors.New("netstring length cannot have leading zeros")
+	}
+
+	length, err := strconv.Atoi(string(rawLength))
+	if err != nil {
+		return nil, 0, false,
+			fmt.Errorf("unable to parse the netstring length: %w", err)
+	}
+
+	payloadStart := separatorIndex + 1
+	if len(data)-payloadStart <= length {
+		return nil, 0, false, nil
+	}
+	if data[payloadStart+length] != netstringEnd {
+		return nil, 0, false, fmt.Errorf(
+			"netstring must end with %q",
+			netstringEnd,
+		)
+	}
+
+	payloadEnd := payloadStart + length
+	return data[payloadStart:payloadEnd], payloadEnd + 1, true, nil
+}
+
+// SplitNetstrings splits the concatenation of complete netstrings
+func SplitNetstrings(data []byte) ([][]byte, error) {
+	var payloads [][]byte
+	for netstringIndex := 0; len(data) != 0; netstringIndex++ {
+		payload, size, isComplete, err := ScanNetstring(data)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"unable to scan the netstring #%d: %w",
+				netstringIndex,
+				err,
+			)
+		}
+		if !isComplete {
+			return nil, fmt.Errorf("netstring #%d is incomplete", netstringIndex)
+		}
+
+		payloads = append(payloads, payload)
+		data = data[size:]
+	}
+
+	return payloads, nil
+}
+
+func checkLengthDigits(rawLength []byte) error {
+	if len(rawLength) > maxLengthDigitCount {
+		return errors.New("netstring length is too long")
+	}
+
+	for _, symbol := range rawLength {
+		if symbol < '0' || symbol > '9' {
+			return fmt.Errorf("netstring length has invalid symbol %q", symbol)
+		}
+	}
+
+	return nil
+}
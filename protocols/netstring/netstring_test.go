@@ -0,0 +1,212 @@
+package netstringProtocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendNetstring(test *testing.T) {
+	type args struct {
+		buffer []byte
+		data   []byte
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want []byte
+	}{
+		{
+			name: "success/empty data",
+			args: args{
+				buffer: nil,
+				data:   nil,
+			},
+			want: []byte("0:,"),
+		},
+		{
+			name: "success/nonempty data",
+			args: args{
+				buffer: []byte("3:one,"),
+				data:   []byte("two:,\n"),
+			},
+			want: []byte("3:one,6:two:,\n,"),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := AppendNetstring(data.args.buffer, data.args.data)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestScanNetstring(test *testing.T) {
+	type args struct {
+		data []byte
+	}
+
+	for _, data := range []struct {
+		name           string
+		args           args
+		wantPayload    []byte
+		wantSize       int
+		wantIsComplete bool
+		wantErr        assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/complete netstring",
+			args: args{
+				data: []byte("5:dummy,rest"),
+			},
+			wantPayload:    []byte("dummy"),
+			wantSize:       8,
+			wantIsComplete: true,
+			wantErr:        assert.NoError,
+		},
+		{
+			name: "success/empty netstring",
+			args: args{
+				data: []byte("0:,"),
+			},
+			wantPayload:    []byte{},
+			wantSize:       3,
+			wantIsComplete: true,
+			wantErr:        assert.NoError,
+		},
+		{
+			name: "success/incomplete length",
+			args: args{
+				data: []byte("12"),
+			},
+			wantPayload:    nil,
+			wantSize:       0,
+			wantIsComplete: false,
+			wantErr:        assert.NoError,
+		},
+		{
+			name: "success/incomplete payload",
+			args: args{
+				data: []byte("5:dummy"),
+			},
+			wantPayload:    nil,
+			wantSize:       0,
+			wantIsComplete: false,
+			wantErr:        assert.NoError,
+		},
+		{
+			name: "error/invalid length symbol",
+			args: args{
+				data: []byte("1x"),
+			},
+			wantPayload:    nil,
+			wantSize:       0,
+			wantIsComplete: false,
+			wantErr:        assert.Error,
+		},
+		{
+			name: "error/empty length",
+			args: args{
+				data: []byte(":,"),
+			},
+			wantPayload:    nil,
+			wantSize:       0,
+			wantIsComplete: false,
+			wantErr:        assert.Error,
+		},
+		{
+			name: "error/leading zeros",
+			args: args{
+				data: []byte("05:dummy,"),
+			},
+			wantPayload:    nil,
+			wantSize:       0,
+			wantIsComplete: false,
+			wantErr:        assert.Error,
+		},
+		{
+			name: "error/too long length",
+			args: args{
+				data: []byte("12345678901"),
+			},
+			wantPayload:    nil,
+			wantSize:       0,
+			wantIsComplete: false,
+			wantErr:        assert.Error,
+		},
+		{
+			name: "error/invalid end",
+			args: args{
+				data: []byte("3:dummy,"),
+			},
+			wantPayload:    nil,
+			wantSize:       0,
+			wantIsComplete: false,
+			wantErr:        assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotPayload, gotSize, gotIsComplete, err :=
+				ScanNetstring(data.args.data)
+
+			assert.Equal(test, data.wantPayload, gotPayload)
+			assert.Equal(test, data.wantSize, gotSize)
+			assert.Equal(test, data.wantIsComplete, gotIsComplete)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestSplitNetstrings(test *testing.T) {
+	type args struct {
+		data []byte
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    [][]byte
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/empty data",
+			args: args{
+				data: nil,
+			},
+			want:    nil,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/several netstrings",
+			args: args{
+				data: []byte("3:one,0:,5:three,"),
+			},
+			want:    [][]byte{[]byte("one"), {}, []byte("three")},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/incomplete netstring",
+			args: args{
+				data: []byte("3:one,5:thr"),
+			},
+			want:    nil,
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/invalid netstring",
+			args: args{
+				data: []byte("3:one,x"),
+			},
+			want:    nil,
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := SplitNetstrings(data.args.data)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
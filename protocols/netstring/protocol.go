@@ -0,0 +1,69 @@
+package netstringProtocol
+
+import (
+	"fmt"
+	"io"
+
+	defaultProtocol "github.com/thewizardplusplus/go-tcp-server/protocols/default"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+)
+
+// Protocol frames each message with an outer netstring,
+// so the tokens are the payloads of these netstrings
+type Protocol struct {
+	defaultProtocol.BaseProtocol
+}
+
+func NewProtocol() Protocol {
+	return Protocol{
+		BaseProtocol: defaultProtocol.NewBaseProtocol(
+			defaultProtocol.BaseProtocolOptions{
+				MessageFormat: NewMessageFormat(),
+			},
+		),
+	}
+}
+
+func (protocol Protocol) ExtractToken(
+	data []byte,
+	isLatestData bool,
+) (offsetToNextToken int, token []byte, err error) {
+	payload, size, isComplete, err := ScanNetstring(data)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to scan the netstring: %w", err)
+	}
+	if !isComplete {
+		if !isLatestData || len(data) == 0 {
+			return 0, nil, nil // request more data or stop on the latest data
+		}
+
+		return 0, nil, fmt.Errorf(
+			"unable to scan the netstring: %w",
+			io.ErrUnexpectedEOF,
+		)
+	}
+
+	return size, payload, nil
+}
+
+func (protocol Protocol) MarshalRequest(
+	request defaultProtocolModels.Request,
+) ([]byte, error) {
+	marshalledRequest, err := protocol.BaseProtocol.MarshalRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return AppendNetstring(nil, marshalledRequest), nil
+}
+
+func (protocol Protocol) MarshalResponse(
+	response defaultProtocolModels.Response,
+) ([]byte, error) {
+	marshalledResponse, err := protocol.BaseProtocol.MarshalResponse(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return AppendNetstring(nil, marshalledResponse), nil
+}
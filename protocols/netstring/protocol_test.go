@@ -0,0 +1,146 @@
+package netstringProtocol
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
+)
+
+func TestProtocol_interface(test *testing.T) {
+	assert.Implements(
+		test,
+		(*tcpServer.ServerProtocol[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		])(nil),
+		Protocol{},
+	)
+	assert.Implements(
+		test,
+		(*tcpServer.ClientProtocol[
+			defaultProtocolModels.Request,
+			defaultProtocolModels.Response,
+		])(nil),
+		Protocol{},
+	)
+}
+
+func TestProtocol_ExtractToken(test *testing.T) {
+	type args struct {
+		data []byte
+	}
+
+	for _, data := range []struct {
+		name       string
+		args       args
+		wantTokens [][]byte
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/several tokens",
+			args: args{
+				data: []byte("14:5:dummy,0:,0:,,3:a,b,"),
+			},
+			wantTokens: [][]byte{[]byte("5:dummy,0:,0:,"), []byte("a,b")},
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "success/empty data",
+			args: args{
+				data: nil,
+			},
+			wantTokens: nil,
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "error/incomplete token",
+			args: args{
+				data: []byte("3:a,b,5:dum"),
+			},
+			wantTokens: [][]byte{[]byte("a,b")},
+			wantErr:    assert.Error,
+		},
+		{
+			name: "error/invalid token",
+			args: args{
+				data: []byte("3:abcd,"),
+			},
+			wantTokens: nil,
+			wantErr:    assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			scanner := tcpServer.InitializeScanner(
+				tcpServer.InitializeScannerParams[
+					defaultProtocolModels.Request,
+					defaultProtocolModels.Response,
+				]{
+					Reader:       bytes.NewReader(data.args.data),
+					BaseProtocol: NewProtocol(),
+				},
+			)
+
+			var gotTokens [][]byte
+			for scanner.Scan() {
+				gotTokens = append(gotTokens, bytes.Clone(scanner.Bytes()))
+			}
+
+			assert.Equal(test, data.wantTokens, gotTokens)
+			data.wantErr(test, scanner.Err())
+		})
+	}
+}
+
+func TestProtocol_MarshalRequest(test *testing.T) {
+	action, err := defaultProtocolModelValueTypes.NewAction([]byte("dummy"))
+	require.NoError(test, err)
+
+	request, err := defaultProtocolModels.NewRequestBuilder().
+		SetAction(action).
+		SetBody(defaultProtocolModelValueTypes.NewBody([]byte("body"))).
+		Build()
+	require.NoError(test, err)
+
+	protocol := NewProtocol()
+	got, err := protocol.MarshalRequest(request)
+	require.NoError(test, err)
+
+	assert.Equal(test, []byte("18:5:dummy,0:,4:body,,"), got)
+
+	offsetToNextToken, token, err := protocol.ExtractToken(got, true)
+	require.NoError(test, err)
+	assert.Equal(test, len(got), offsetToNextToken)
+
+	gotRequest, err := protocol.ParseRequest(token)
+	require.NoError(test, err)
+	assert.Equal(test, request, gotRequest)
+}
+
+func TestProtocol_MarshalResponse(test *testing.T) {
+	status, err := defaultProtocolModelValueTypes.NewStatus([]byte("ok"))
+	require.NoError(test, err)
+
+	response, err := defaultProtocolModels.NewResponseBuilder().
+		SetStatus(status).
+		Build()
+	require.NoError(test, err)
+
+	protocol := NewProtocol()
+	got, err := protocol.MarshalResponse(response)
+	require.NoError(test, err)
+
+	assert.Equal(test, []byte("11:2:ok,0:,0:,,"), got)
+
+	offsetToNextToken, token, err := protocol.ExtractToken(got, true)
+	require.NoError(test, err)
+	assert.Equal(test, len(got), offsetToNextToken)
+
+	gotResponse, err := protocol.ParseResponse(token)
+	require.NoError(test, err)
+	assert.Equal(test, response, gotResponse)
+}
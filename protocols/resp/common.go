@@ -0,0 +1,22 @@
+package respProtocol
+
+type Version int
+
+const (
+	Version2 Version = 2
+	Version3 Version = 3
+)
+
+const (
+	simpleStringPrefix = '+'
+	errorPrefix        = '-'
+	integerPrefix      = ':'
+	bulkStringPrefix   = '$'
+	arrayPrefix        = '*'
+	mapPrefix          = '%'
+	nullPrefix         = '_'
+)
+
+var (
+	lineSeparator = []byte("\r\n")
+)
@@ -0,0 +1,72 @@
+package respProtocolModels
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Request struct {
+	command   []byte
+	arguments [][]byte
+}
+
+func NewRequest(command []byte, arguments [][]byte) (Request, error) {
+	if len(command) == 0 {
+		return Request{}, errors.New("command cannot be empty")
+	}
+
+	model := Request{
+		command:   command,
+		arguments: arguments,
+	}
+	return model, nil
+}
+
+func NewRequestFromValue(value Value) (Request, error) {
+	elements, isPresent := value.Elements().Get()
+	if !isPresent {
+		return Request{}, fmt.Errorf("request must be an array, not %s", value.Kind())
+	}
+	if len(elements) == 0 {
+		return Request{}, errors.New("request array cannot be empty")
+	}
+
+	rawRequest := make([][]byte, 0, len(elements))
+	for elementIndex, element := range elements {
+		if element.Kind() != BulkStringValueKind &&
+			element.Kind() != SimpleStringValueKind {
+			return Request{}, fmt.Errorf(
+				"request element #%d must be a string, not %s",
+				elementIndex,
+				element.Kind(),
+			)
+		}
+
+		rawRequest = append(rawRequest, element.Text().MustGet())
+	}
+
+	model, err := NewRequest(rawRequest[0], rawRequest[1:])
+	if err != nil {
+		return Request{}, fmt.Errorf("unable to construct the request: %w", err)
+	}
+
+	return model, nil
+}
+
+func (model Request) Command() []byte {
+	return model.command
+}
+
+func (model Request) Arguments() [][]byte {
+	return model.arguments
+}
+
+func (model Request) ToValue() Value {
+	elements := make([]Value, 0, len(model.arguments)+1)
+	elements = append(elements, NewBulkString(model.command))
+	for _, argument := range model.arguments {
+		elements = append(elements, NewBulkString(argument))
+	}
+
+	return NewArray(elements)
+}
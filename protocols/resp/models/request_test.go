@@ -0,0 +1,111 @@
+package respProtocolModels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRequestFromValue(test *testing.T) {
+	type args struct {
+		value Value
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    Request
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/with arguments",
+			args: args{
+				value: NewArray([]Value{
+					NewBulkString([]byte("SET")),
+					NewBulkString([]byte("key")),
+					func() Value {
+						value, err := NewSimpleString([]byte("value"))
+						require.NoError(test, err)
+
+						return value
+					}(),
+				}),
+			},
+			want: Request{
+				command:   []byte("SET"),
+				arguments: [][]byte{[]byte("key"), []byte("value")},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/without arguments",
+			args: args{
+				value: NewArray([]Value{NewBulkString([]byte("PING"))}),
+			},
+			want: Request{
+				command:   []byte("PING"),
+				arguments: [][]byte{},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/not an array",
+			args: args{
+				value: NewBulkString([]byte("PING")),
+			},
+			want:    Request{},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/empty array",
+			args: args{
+				value: NewArray(nil),
+			},
+			want:    Request{},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/not a string element",
+			args: args{
+				value: NewArray([]Value{
+					NewBulkString([]byte("GET")),
+					NewInteger(23),
+				}),
+			},
+			want:    Request{},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/empty command",
+			args: args{
+				value: NewArray([]Value{NewBulkString(nil)}),
+			},
+			want:    Request{},
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := NewRequestFromValue(data.args.value)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestRequest_ToValue(test *testing.T) {
+	request, err := NewRequest(
+		[]byte("SET"),
+		[][]byte{[]byte("key"), []byte("value")},
+	)
+	require.NoError(test, err)
+
+	got := request.ToValue()
+
+	assert.Equal(test, NewArray([]Value{
+		NewBulkString([]byte("SET")),
+		NewBulkString([]byte("key")),
+		NewBulkString([]byte("value")),
+	}), got)
+}
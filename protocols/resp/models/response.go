@@ -0,0 +1,19 @@
+package respProtocolModels
+
+type Response struct {
+	value Value
+}
+
+func NewResponse(value Value) Response {
+	return Response{
+		value: value,
+	}
+}
+
+func (model Response) Value() Value {
+	return model.value
+}
+
+func (model Response) IsError() bool {
+	return model.value.Kind() == ErrorValueKind
+}
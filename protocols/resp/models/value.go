@@ -0,0 +1,133 @@
+package respProtocolModels
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/samber/mo"
+)
+
+type ValueKind string
+
+const (
+	SimpleStringValueKind ValueKind = "simple string"
+	ErrorValueKind        ValueKind = "error"
+	IntegerValueKind      ValueKind = "integer"
+	BulkStringValueKind   ValueKind = "bulk string"
+	ArrayValueKind        ValueKind = "array"
+	MapValueKind          ValueKind = "map"
+	NullValueKind         ValueKind = "null"
+)
+
+type MapEntry struct {
+	Key   Value
+	Value Value
+}
+
+type Value struct {
+	kind     ValueKind
+	text     []byte
+	integer  int64
+	elements []Value
+	entries  []MapEntry
+}
+
+func NewSimpleString(text []byte) (Value, error) {
+	if bytes.ContainsAny(text, "\r\n") {
+		return Value{}, errors.New("simple string cannot contain CR or LF")
+	}
+
+	value := Value{
+		kind: SimpleStringValueKind,
+		text: text,
+	}
+	return value, nil
+}
+
+func NewError(text []byte) (Value, error) {
+	if bytes.ContainsAny(text, "\r\n") {
+		return Value{}, errors.New("error cannot contain CR or LF")
+	}
+
+	value := Value{
+		kind: ErrorValueKind,
+		text: text,
+	}
+	return value, nil
+}
+
+func NewInteger(integer int64) Value {
+	return Value{
+		kind:    IntegerValueKind,
+		integer: integer,
+	}
+}
+
+func NewBulkString(text []byte) Value {
+	return Value{
+		kind: BulkStringValueKind,
+		text: text,
+	}
+}
+
+func NewArray(elements []Value) Value {
+	return Value{
+		kind:     ArrayValueKind,
+		elements: elements,
+	}
+}
+
+func NewMap(entries []MapEntry) Value {
+	return Value{
+		kind:    MapValueKind,
+		entries: entries,
+	}
+}
+
+func NewNull() Value {
+	return Value{
+		kind: NullValueKind,
+	}
+}
+
+func (value Value) Kind() ValueKind {
+	return value.kind
+}
+
+func (value Value) IsNull() bool {
+	return value.kind == NullValueKind
+}
+
+// Text is present for simple strings, errors and bulk strings
+func (value Value) Text() mo.Option[[]byte] {
+	switch value.kind {
+	case SimpleStringValueKind, ErrorValueKind, BulkStringValueKind:
+		return mo.Some(value.text)
+	default:
+		return mo.None[[]byte]()
+	}
+}
+
+func (value Value) Integer() mo.Option[int64] {
+	if value.kind != IntegerValueKind {
+		return mo.None[int64]()
+	}
+
+	return mo.Some(value.integer)
+}
+
+func (value Value) Elements() mo.Option[[]Value] {
+	if value.kind != ArrayValueKind {
+		return mo.None[[]Value]()
+	}
+
+	return mo.Some(value.elements)
+}
+
+func (value Value) Entries() mo.Option[[]MapEntry] {
+	if value.kind != MapValueKind {
+		return mo.None[[]MapEntry]()
+	}
+
+	return mo.Some(value.entries)
+}
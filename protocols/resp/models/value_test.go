@@ -0,0 +1,158 @@
+package respProtocolModels
+
+import (
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSimpleString(test *testing.T) {
+	type args struct {
+		text []byte
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    Value
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				text: []byte("OK"),
+			},
+			want: Value{
+				kind: SimpleStringValueKind,
+				text: []byte("OK"),
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			args: args{
+				text: []byte("one\r\ntwo"),
+			},
+			want:    Value{},
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := NewSimpleString(data.args.text)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestNewError(test *testing.T) {
+	type args struct {
+		text []byte
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    Value
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				text: []byte("ERR unknown command"),
+			},
+			want: Value{
+				kind: ErrorValueKind,
+				text: []byte("ERR unknown command"),
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			args: args{
+				text: []byte("ERR\nunknown command"),
+			},
+			want:    Value{},
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := NewError(data.args.text)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestValue_accessors(test *testing.T) {
+	for _, data := range []struct {
+		name         string
+		value        Value
+		wantIsNull   bool
+		wantText     mo.Option[[]byte]
+		wantInteger  mo.Option[int64]
+		wantElements mo.Option[[]Value]
+		wantEntries  mo.Option[[]MapEntry]
+	}{
+		{
+			name:         "success/integer",
+			value:        NewInteger(23),
+			wantIsNull:   false,
+			wantText:     mo.None[[]byte](),
+			wantInteger:  mo.Some[int64](23),
+			wantElements: mo.None[[]Value](),
+			wantEntries:  mo.None[[]MapEntry](),
+		},
+		{
+			name:         "success/bulk string",
+			value:        NewBulkString([]byte("dummy")),
+			wantIsNull:   false,
+			wantText:     mo.Some([]byte("dummy")),
+			wantInteger:  mo.None[int64](),
+			wantElements: mo.None[[]Value](),
+			wantEntries:  mo.None[[]MapEntry](),
+		},
+		{
+			name:         "success/array",
+			value:        NewArray([]Value{NewInteger(23)}),
+			wantIsNull:   false,
+			wantText:     mo.None[[]byte](),
+			wantInteger:  mo.None[int64](),
+			wantElements: mo.Some([]Value{NewInteger(23)}),
+			wantEntries:  mo.None[[]MapEntry](),
+		},
+		{
+			name: "success/map",
+			value: NewMap([]MapEntry{
+				{Key: NewBulkString([]byte("key")), Value: NewInteger(23)},
+			}),
+			wantIsNull:   false,
+			wantText:     mo.None[[]byte](),
+			wantInteger:  mo.None[int64](),
+			wantElements: mo.None[[]Value](),
+			wantEntries: mo.Some([]MapEntry{
+				{Key: NewBulkString([]byte("key")), Value: NewInteger(23)},
+			}),
+		},
+		{
+			name:         "success/null",
+			value:        NewNull(),
+			wantIsNull:   true,
+			wantText:     mo.None[[]byte](),
+			wantInteger:  mo.None[int64](),
+			wantElements: mo.None[[]Value](),
+			wantEntries:  mo.None[[]MapEntry](),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			assert.Equal(test, data.wantIsNull, data.value.IsNull())
+			assert.Equal(test, data.wantText, data.value.Text())
+			assert.Equal(test, data.wantInteger, data.value.Integer())
+			assert.Equal(test, data.wantElements, data.value.Elements())
+			assert.Equal(test, data.wantEntries, data.value.Entries())
+		})
+	}
+}
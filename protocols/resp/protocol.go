@@ -0,0 +1,186 @@
+package respProtocol
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/samber/mo"
+	respProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/resp/models"
+)
+
+type ProtocolOptions struct {
+	// Version affects only the marshalling, the parsing accepts both versions
+	Version      mo.Option[Version]
+	MaxTokenSize mo.Option[int]
+}
+
+type Protocol struct {
+	options ProtocolOptions
+}
+
+func NewProtocol(options ProtocolOptions) Protocol {
+	return Protocol{
+		options: options,
+	}
+}
+
+func (protocol Protocol) InitialScannerBufferSize() int {
+	return 4 * 1024 // based on the default values in package `bufio`
+}
+
+func (protocol Protocol) MaxTokenSize() int {
+	// based on the default values in package `bufio`
+	return protocol.options.MaxTokenSize.OrElse(64 * 1024)
+}
+
+// ExtractToken extracts either a complete value or an inline command,
+// i.e. a line of space-separated arguments
+func (protocol Protocol) ExtractToken(
+	data []byte,
+	isLatestData bool,
+) (offsetToNextToken int, token []byte, err error) {
+	if len(data) == 0 {
+		return 0, nil, nil // request more data or stop on the latest data
+	}
+
+	if !isValuePrefix(data[0]) {
+		line, lineSize, isPresent := scanInlineCommand(data)
+		if !isPresent {
+			if !isLatestData {
+				return 0, nil, nil // request more data
+			}
+
+			return 0, data, bufio.ErrFinalToken
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			return lineSize, nil, nil // skip empty inline commands
+		}
+
+		return lineSize, data[:lineSize], nil
+	}
+
+	size, isComplete, err := ScanValue(data)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to scan the value: %w", err)
+	}
+	if !isComplete {
+		if !isLatestData {
+			return 0, nil, nil // request more data
+		}
+
+		return 0, nil, fmt.Errorf(
+			"unable to scan the value: %w",
+			io.ErrUnexpectedEOF,
+		)
+	}
+
+	return size, data[:size], nil
+}
+
+func (protocol Protocol) ParseRequest(
+	data []byte,
+) (respProtocolModels.Request, error) {
+	if len(data) != 0 && !isValuePrefix(data[0]) {
+		arguments := bytes.Fields(data)
+		if len(arguments) == 0 {
+			return respProtocolModels.Request{},
+				errors.New("inline command cannot be empty")
+		}
+
+		request, err := respProtocolModels.NewRequest(arguments[0], arguments[1:])
+		if err != nil {
+			return respProtocolModels.Request{}, fmt.Errorf(
+				"unable to construct the request: %w",
+				err,
+			)
+		}
+
+		return request, nil
+	}
+
+	value, err := ParseValue(data)
+	if err != nil {
+		return respProtocolModels.Request{}, fmt.Errorf(
+			"unable to parse the value: %w",
+			err,
+		)
+	}
+
+	request, err := respProtocolModels.NewRequestFromValue(value)
+	if err != nil {
+		return respProtocolModels.Request{}, fmt.Errorf(
+			"unable to construct the request: %w",
+			err,
+		)
+	}
+
+	return request, nil
+}
+
+func (protocol Protocol) ParseResponse(
+	data []byte,
+) (respProtocolModels.Response, error) {
+	value, err := ParseValue(data)
+	if err != nil {
+		return respProtocolModels.Response{}, fmt.Errorf(
+			"unable to parse the value: %w",
+			err,
+		)
+	}
+
+	return respProtocolModels.NewResponse(value), nil
+}
+
+func (protocol Protocol) MarshalRequest(
+	request respProtocolModels.Request,
+) ([]byte, error) {
+	marshalledValue, err := MarshalValue(request.ToValue(), protocol.version())
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal the value: %w", err)
+	}
+
+	return marshalledValue, nil
+}
+
+func (protocol Protocol) MarshalResponse(
+	response respProtocolModels.Response,
+) ([]byte, error) {
+	marshalledValue, err := MarshalValue(response.Value(), protocol.version())
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal the value: %w", err)
+	}
+
+	return marshalledValue, nil
+}
+
+func (protocol Protocol) version() Version {
+	return protocol.options.Version.OrElse(Version2)
+}
+
+func isValuePrefix(symbol byte) bool {
+	switch symbol {
+	case simpleStringPrefix,
+		errorPrefix,
+		integerPrefix,
+		bulkStringPrefix,
+		arrayPrefix,
+		mapPrefix,
+		nullPrefix:
+		return true
+	default:
+		return false
+	}
+}
+
+// inline commands may be terminated by a single LF
+func scanInlineCommand(data []byte) (line []byte, size int, isPresent bool) {
+	separatorIndex := bytes.IndexByte(data, '\n')
+	if separatorIndex == -1 {
+		return nil, 0, false
+	}
+
+	return data[:separatorIndex], separatorIndex + 1, true
+}
@@ -0,0 +1,230 @@
+package respProtocol
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+	respProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/resp/models"
+)
+
+func TestProtocol_interface(test *testing.T) {
+	assert.Implements(
+		test,
+		(*tcpServer.ServerProtocol[
+			respProtocolModels.Request,
+			respProtocolModels.Response,
+		])(nil),
+		Protocol{},
+	)
+	assert.Implements(
+		test,
+		(*tcpServer.ClientProtocol[
+			respProtocolModels.Request,
+			respProtocolModels.Response,
+		])(nil),
+		Protocol{},
+	)
+}
+
+func TestProtocol_ExtractToken(test *testing.T) {
+	type args struct {
+		chunks [][]byte
+	}
+
+	for _, data := range []struct {
+		name       string
+		args       args
+		wantTokens [][]byte
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/values in several chunks",
+			args: args{
+				chunks: [][]byte{
+					[]byte("*2\r\n$3\r\nGE"),
+					[]byte("T\r\n*1\r\n"),
+					[]byte(":1\r\n+OK\r\n"),
+				},
+			},
+			wantTokens: [][]byte{
+				[]byte("*2\r\n$3\r\nGET\r\n*1\r\n:1\r\n"),
+				[]byte("+OK\r\n"),
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/inline commands",
+			args: args{
+				chunks: [][]byte{
+					[]byte("PING\r\n\r\nSET key "),
+					[]byte("value\nQUIT"),
+				},
+			},
+			wantTokens: [][]byte{
+				[]byte("PING\r\n"),
+				[]byte("SET key value\n"),
+				[]byte("QUIT"),
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/incomplete value",
+			args: args{
+				chunks: [][]byte{[]byte("*2\r\n:1\r\n")},
+			},
+			wantTokens: nil,
+			wantErr:    assert.Error,
+		},
+		{
+			name: "error/invalid value",
+			args: args{
+				chunks: [][]byte{[]byte("*two\r\n")},
+			},
+			wantTokens: nil,
+			wantErr:    assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			scanner := tcpServer.InitializeScanner(
+				tcpServer.InitializeScannerParams[
+					respProtocolModels.Request,
+					respProtocolModels.Response,
+				]{
+					Reader:       &chunkedReader{chunks: data.args.chunks},
+					BaseProtocol: NewProtocol(ProtocolOptions{}),
+				},
+			)
+
+			var gotTokens [][]byte
+			for scanner.Scan() {
+				gotTokens = append(gotTokens, bytes.Clone(scanner.Bytes()))
+			}
+
+			assert.Equal(test, data.wantTokens, gotTokens)
+			data.wantErr(test, scanner.Err())
+		})
+	}
+}
+
+func TestProtocol_requests(test *testing.T) {
+	for _, data := range []struct {
+		name string
+		data []byte
+		want respProtocolModels.Request
+	}{
+		{
+			name: "success/array",
+			data: []byte("*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$5\r\nvalue\r\n"),
+			want: func() respProtocolModels.Request {
+				request, err := respProtocolModels.NewRequest(
+					[]byte("SET"),
+					[][]byte{[]byte("key"), []byte("value")},
+				)
+				require.NoError(test, err)
+
+				return request
+			}(),
+		},
+		{
+			name: "success/inline command",
+			data: []byte("SET  key value\r\n"),
+			want: func() respProtocolModels.Request {
+				request, err := respProtocolModels.NewRequest(
+					[]byte("SET"),
+					[][]byte{[]byte("key"), []byte("value")},
+				)
+				require.NoError(test, err)
+
+				return request
+			}(),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			protocol := NewProtocol(ProtocolOptions{})
+
+			got, err := protocol.ParseRequest(data.data)
+			require.NoError(test, err)
+			assert.Equal(test, data.want, got)
+
+			marshalledRequest, err := protocol.MarshalRequest(got)
+			require.NoError(test, err)
+
+			gotAgain, err := protocol.ParseRequest(marshalledRequest)
+			require.NoError(test, err)
+			assert.Equal(test, data.want, gotAgain)
+		})
+	}
+}
+
+func TestProtocol_responses(test *testing.T) {
+	response := respProtocolModels.NewResponse(
+		respProtocolModels.NewMap([]respProtocolModels.MapEntry{
+			{
+				Key:   respProtocolModels.NewBulkString([]byte("key")),
+				Value: respProtocolModels.NewNull(),
+			},
+		}),
+	)
+
+	for _, data := range []struct {
+		name       string
+		options    ProtocolOptions
+		want       []byte
+		wantParsed respProtocolModels.Response
+	}{
+		{
+			name:    "success/RESP2 by default",
+			options: ProtocolOptions{},
+			want:    []byte("*2\r\n$3\r\nkey\r\n$-1\r\n"),
+			wantParsed: respProtocolModels.NewResponse(
+				respProtocolModels.NewArray([]respProtocolModels.Value{
+					respProtocolModels.NewBulkString([]byte("key")),
+					respProtocolModels.NewNull(),
+				}),
+			),
+		},
+		{
+			name: "success/RESP3",
+			options: ProtocolOptions{
+				Version: mo.Some(Version3),
+			},
+			want:       []byte("%1\r\n$3\r\nkey\r\n_\r\n"),
+			wantParsed: response,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			protocol := NewProtocol(data.options)
+
+			got, err := protocol.MarshalResponse(response)
+			require.NoError(test, err)
+			assert.Equal(test, data.want, got)
+
+			gotParsed, err := protocol.ParseResponse(got)
+			require.NoError(test, err)
+			assert.Equal(test, data.wantParsed, gotParsed)
+		})
+	}
+}
+
+type chunkedReader struct {
+	chunks [][]byte
+}
+
+func (reader *chunkedReader) Read(buffer []byte) (int, error) {
+	if len(reader.chunks) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(buffer, reader.chunks[0])
+	reader.chunks[0] = reader.chunks[0][n:]
+	if len(reader.chunks[0]) == 0 {
+		reader.chunks = reader.chunks[1:]
+	}
+
+	return n, nil
+}
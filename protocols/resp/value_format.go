@@ -0,0 +1,335 @@
+package respProtocol
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	respProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/resp/models"
+)
+
+// ScanValue returns the size of the first complete value in the data;
+// it uses an explicit counter of the pending values instead of recursion,
+// so it handles the nested arrays and maps of any depth, and returns
+// `false` if the data is incomplete yet
+func ScanValue(data []byte) (size int, isComplete bool, err error) {
+	pendingValueCount := 1
+	for pendingValueCount > 0 {
+		line, lineSize, isPresent := scanLine(data[size:])
+		if !isPresent {
+			return 0, false, nil
+		}
+		if len(line) == 0 {
+			return 0, false, errors.New("value line cannot be empty")
+		}
+
+		size += lineSize
+		pendingValueCount--
+
+		switch prefix, rawValue := line[0], line[1:]; prefix {
+		case simpleStringPrefix, errorPrefix, nullPrefix:
+		case integerPrefix:
+			if _, err := parseInteger(rawValue); err != nil {
+				return 0, false, err
+			}
+		case bulkStringPrefix:
+			length, err := parseLength(rawValue)
+			if err != nil {
+				return 0, false, err
+			}
+			if length == -1 {
+				continue
+			}
+
+			if len(data)-size-len(lineSeparator) < length {
+				return 0, false, nil
+			}
+			if !bytes.HasPrefix(data[size+length:], lineSeparator) {
+				return 0, false, errors.New("bulk string has no line separator")
+			}
+
+			size += length + len(lineSeparator)
+		case arrayPrefix, mapPrefix:
+			length, err := parseLength(rawValue)
+			if err != nil {
+				return 0, false, err
+			}
+			if length == -1 {
+				continue
+			}
+
+			if prefix == mapPrefix {
+				length *= 2
+			}
+
+			pendingValueCount += length
+		default:
+			return 0, false, fmt.Errorf("unknown value type %q", prefix)
+		}
+	}
+
+	return size, true, nil
+}
+
+func ParseValue(data []byte) (respProtocolModels.Value, error) {
+	value, size, err := parseValue(data)
+	if err != nil {
+		return respProtocolModels.Value{}, err
+	}
+	if size != len(data) {
+		return respProtocolModels.Value{}, fmt.Errorf(
+			"value has %d extra bytes",
+			len(data)-size,
+		)
+	}
+
+	return value, nil
+}
+
+// MarshalValue marshals maps as flat arrays of keys and values
+// and nulls as null bulk strings in RESP2
+func MarshalValue(
+	value respProtocolModels.Value,
+	version Version,
+) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := marshalValue(&buffer, value, version); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func parseValue(
+	data []byte,
+) (value respProtocolModels.Value, size int, err error) {
+	line, size, isPresent := scanLine(data)
+	if !isPresent {
+		return respProtocolModels.Value{}, 0, errors.New("value is incomplete")
+	}
+	if len(line) == 0 {
+		return respProtocolModels.Value{}, 0,
+			errors.New("value line cannot be empty")
+	}
+
+	switch prefix, rawValue := line[0], line[1:]; prefix {
+	case simpleStringPrefix:
+		value, err := respProtocolModels.NewSimpleString(rawValue)
+		if err != nil {
+			return respProtocolModels.Value{}, 0, fmt.Errorf(
+				"unable to construct the simple string: %w",
+				err,
+			)
+		}
+
+		return value, size, nil
+	case errorPrefix:
+		value, err := respProtocolModels.NewError(rawValue)
+		if err != nil {
+			return respProtocolModels.Value{}, 0, fmt.Errorf(
+				"unable to construct the error: %w",
+				err,
+			)
+		}
+
+		return value, size, nil
+	case integerPrefix:
+		integer, err := parseInteger(rawValue)
+		if err != nil {
+			return respProtocolModels.Value{}, 0, err
+		}
+
+		return respProtocolModels.NewInteger(integer), size, nil
+	case nullPrefix:
+		if len(rawValue) != 0 {
+			return respProtocolModels.Value{}, 0, errors.New("null cannot have data")
+		}
+
+		return respProtocolModels.NewNull(), size, nil
+	case bulkStringPrefix:
+		length, err := parseLength(rawValue)
+		if err != nil {
+			return respProtocolModels.Value{}, 0, err
+		}
+		if length == -1 {
+			return respProtocolModels.NewNull(), size, nil
+		}
+
+		rest := data[size:]
+		if len(rest)-len(lineSeparator) < length ||
+			!bytes.HasPrefix(rest[length:], lineSeparator) {
+			return respProtocolModels.Value{}, 0,
+				errors.New("bulk string is incomplete")
+		}
+
+		text := rest[:length]
+		size += length + len(lineSeparator)
+
+		return respProtocolModels.NewBulkString(text), size, nil
+	case arrayPrefix, mapPrefix:
+		length, err := parseLength(rawValue)
+		if err != nil {
+			return respProtocolModels.Value{}, 0, err
+		}
+		if length == -1 {
+			return respProtocolModels.NewNull(), size, nil
+		}
+
+		itemCount := length
+		if prefix == mapPrefix {
+			itemCount *= 2
+		}
+
+		// each item takes at least three bytes
+		items := make(
+			[]respProtocolModels.Value,
+			0,
+			min(itemCount, (len(data)-size)/3),
+		)
+		for itemIndex := range itemCount {
+			item, itemSize, err := parseValue(data[size:])
+			if err != nil {
+				return respProtocolModels.Value{}, 0, fmt.Errorf(
+					"unable to parse the item #%d: %w",
+					itemIndex,
+					err,
+				)
+			}
+
+			items = append(items, item)
+			size += itemSize
+		}
+
+		if prefix == arrayPrefix {
+			return respProtocolModels.NewArray(items), size, nil
+		}
+
+		entries := make([]respProtocolModels.MapEntry, 0, length)
+		for entryIndex := range length {
+			entries = append(entries, respProtocolModels.MapEntry{
+				Key:   items[2*entryIndex],
+				Value: items[2*entryIndex+1],
+			})
+		}
+
+		return respProtocolModels.NewMap(entries), size, nil
+	default:
+		return respProtocolModels.Value{}, 0,
+			fmt.Errorf("unknown value type %q", prefix)
+	}
+}
+
+func marshalValue(
+	buffer *bytes.Buffer,
+	value respProtocolModels.Value,
+	version Version,
+) error {
+	switch value.Kind() {
+	case respProtocolModels.SimpleStringValueKind:
+		writeLine(buffer, simpleStringPrefix, value.Text().MustGet())
+	case respProtocolModels.ErrorValueKind:
+		writeLine(buffer, errorPrefix, value.Text().MustGet())
+	case respProtocolModels.IntegerValueKind:
+		writeLine(
+			buffer,
+			integerPrefix,
+			strconv.AppendInt(nil, value.Integer().MustGet(), 10),
+		)
+	case respProtocolModels.BulkStringValueKind:
+		text := value.Text().MustGet()
+		writeLength(buffer, bulkStringPrefix, len(text))
+		buffer.Write(text)
+		buffer.Write(lineSeparator)
+	case respProtocolModels.ArrayValueKind:
+		elements := value.Elements().MustGet()
+		writeLength(buffer, arrayPrefix, len(elements))
+
+		for elementIndex, element := range elements {
+			if err := marshalValue(buffer, element, version); err != nil {
+				return fmt.Errorf(
+					"unable to marshal the element #%d: %w",
+					elementIndex,
+					err,
+				)
+			}
+		}
+	case respProtocolModels.MapValueKind:
+		entries := value.Entries().MustGet()
+		if version == Version2 {
+			writeLength(buffer, arrayPrefix, 2*len(entries))
+		} else {
+			writeLength(buffer, mapPrefix, len(entries))
+		}
+
+		for entryIndex, entry := range entries {
+			if err := marshalValue(buffer, entry.Key, version); err != nil {
+				return fmt.Errorf(
+					"unable to marshal the key of the entry #%d: %w",
+					entryIndex,
+					err,
+				)
+			}
+
+			if err := marshalValue(buffer, entry.Value, version); err != nil {
+				return fmt.Errorf(
+					"unable to marshal the value of the entry #%d: %w",
+					entryIndex,
+					err,
+				)
+			}
+		}
+	case respProtocolModels.NullValueKind:
+		if version == Version2 {
+			writeLine(buffer, bulkStringPrefix, []byte("-1"))
+		} else {
+			writeLine(buffer, nullPrefix, nil)
+		}
+	default:
+		return fmt.Errorf("unknown value kind %q", value.Kind())
+	}
+
+	return nil
+}
+
+func scanLine(data []byte) (line []byte, size int, isPresent bool) {
+	separatorIndex := bytes.Index(data, lineSeparator)
+	if separatorIndex == -1 {
+		return nil, 0, false
+	}
+
+	return data[:separatorIndex], separatorIndex + len(lineSeparator), true
+}
+
+func writeLine(buffer *bytes.Buffer, prefix byte, line []byte) {
+	buffer.WriteByte(prefix)
+	buffer.Write(line)
+	buffer.Write(lineSeparator)
+}
+
+func writeLength(buffer *bytes.Buffer, prefix byte, length int) {
+	writeLine(buffer, prefix, strconv.AppendInt(nil, int64(length), 10))
+}
+
+func parseInteger(data []byte) (int64, error) {
+	integer, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse the integer: %w", err)
+	}
+
+	return integer, nil
+}
+
+// parseLength returns -1 for the nulls of RESP2
+func parseLength(data []byte) (int, error) {
+	length, err := strconv.Atoi(string(data))
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse the length: %w", err)
+	}
+	if length < -1 || length > math.MaxInt32 {
+		return 0, fmt.Errorf("invalid length: %d", length)
+	}
+
+	return length, nil
+}
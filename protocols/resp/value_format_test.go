@@ -0,0 +1,258 @@
+package respProtocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	respProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/resp/models"
+)
+
+func TestScanValue(test *testing.T) {
+	type args struct {
+		data []byte
+	}
+
+	for _, data := range []struct {
+		name           string
+		args           args
+		wantSize       int
+		wantIsComplete bool
+		wantErr        assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/simple string",
+			args: args{
+				data: []byte("+OK\r\n+OK\r\n"),
+			},
+			wantSize:       5,
+			wantIsComplete: true,
+			wantErr:        assert.NoError,
+		},
+		{
+			name: "success/nested arrays",
+			args: args{
+				data: []byte("*2\r\n*2\r\n:1\r\n$3\r\none\r\n%1\r\n+key\r\n_\r\nrest"),
+			},
+			wantSize:       34,
+			wantIsComplete: true,
+			wantErr:        assert.NoError,
+		},
+		{
+			name: "success/null array and null bulk string",
+			args: args{
+				data: []byte("*2\r\n*-1\r\n$-1\r\n"),
+			},
+			wantSize:       14,
+			wantIsComplete: true,
+			wantErr:        assert.NoError,
+		},
+		{
+			name: "success/incomplete line",
+			args: args{
+				data: []byte("*2\r\n:1\r\n:2"),
+			},
+			wantSize:       0,
+			wantIsComplete: false,
+			wantErr:        assert.NoError,
+		},
+		{
+			name: "success/incomplete bulk string",
+			args: args{
+				data: []byte("$5\r\ndum"),
+			},
+			wantSize:       0,
+			wantIsComplete: false,
+			wantErr:        assert.NoError,
+		},
+		{
+			name: "error/unknown value type",
+			args: args{
+				data: []byte("?dummy\r\n"),
+			},
+			wantSize:       0,
+			wantIsComplete: false,
+			wantErr:        assert.Error,
+		},
+		{
+			name: "error/invalid length",
+			args: args{
+				data: []byte("*-2\r\n"),
+			},
+			wantSize:       0,
+			wantIsComplete: false,
+			wantErr:        assert.Error,
+		},
+		{
+			name: "error/bulk string without a line separator",
+			args: args{
+				data: []byte("$3\r\ndummy\r\n"),
+			},
+			wantSize:       0,
+			wantIsComplete: false,
+			wantErr:        assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotSize, gotIsComplete, err := ScanValue(data.args.data)
+
+			assert.Equal(test, data.wantSize, gotSize)
+			assert.Equal(test, data.wantIsComplete, gotIsComplete)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestParseValue(test *testing.T) {
+	type args struct {
+		data []byte
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    respProtocolModels.Value
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/scalars",
+			args: args{
+				data: []byte("*5\r\n+OK\r\n-ERR\r\n:-23\r\n$5\r\ndummy\r\n_\r\n"),
+			},
+			want: respProtocolModels.NewArray([]respProtocolModels.Value{
+				func() respProtocolModels.Value {
+					value, err := respProtocolModels.NewSimpleString([]byte("OK"))
+					require.NoError(test, err)
+
+					return value
+				}(),
+				func() respProtocolModels.Value {
+					value, err := respProtocolModels.NewError([]byte("ERR"))
+					require.NoError(test, err)
+
+					return value
+				}(),
+				respProtocolModels.NewInteger(-23),
+				respProtocolModels.NewBulkString([]byte("dummy")),
+				respProtocolModels.NewNull(),
+			}),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/map",
+			args: args{
+				data: []byte("%1\r\n$3\r\nkey\r\n*1\r\n:1\r\n"),
+			},
+			want: respProtocolModels.NewMap([]respProtocolModels.MapEntry{
+				{
+					Key: respProtocolModels.NewBulkString([]byte("key")),
+					Value: respProtocolModels.NewArray([]respProtocolModels.Value{
+						respProtocolModels.NewInteger(1),
+					}),
+				},
+			}),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/null bulk string",
+			args: args{
+				data: []byte("$-1\r\n"),
+			},
+			want:    respProtocolModels.NewNull(),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/extra bytes",
+			args: args{
+				data: []byte(":1\r\n:2\r\n"),
+			},
+			want:    respProtocolModels.Value{},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/incomplete array",
+			args: args{
+				data: []byte("*2\r\n:1\r\n"),
+			},
+			want:    respProtocolModels.Value{},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/invalid integer",
+			args: args{
+				data: []byte(":dummy\r\n"),
+			},
+			want:    respProtocolModels.Value{},
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := ParseValue(data.args.data)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestMarshalValue(test *testing.T) {
+	value := respProtocolModels.NewArray([]respProtocolModels.Value{
+		respProtocolModels.NewInteger(23),
+		respProtocolModels.NewBulkString([]byte("dummy")),
+		respProtocolModels.NewMap([]respProtocolModels.MapEntry{
+			{
+				Key:   respProtocolModels.NewBulkString([]byte("key")),
+				Value: respProtocolModels.NewNull(),
+			},
+		}),
+	})
+
+	type args struct {
+		value   respProtocolModels.Value
+		version Version
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    []byte
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/RESP2",
+			args: args{
+				value:   value,
+				version: Version2,
+			},
+			want:    []byte("*3\r\n:23\r\n$5\r\ndummy\r\n*2\r\n$3\r\nkey\r\n$-1\r\n"),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/RESP3",
+			args: args{
+				value:   value,
+				version: Version3,
+			},
+			want:    []byte("*3\r\n:23\r\n$5\r\ndummy\r\n%1\r\n$3\r\nkey\r\n_\r\n"),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			args: args{
+				value: respProtocolModels.NewArray([]respProtocolModels.Value{
+					{},
+				}),
+				version: Version2,
+			},
+			want:    nil,
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := MarshalValue(data.args.value, data.args.version)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
@@ -7,9 +7,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	tcpServer "github.com/thewizardplusplus/go-tcp-server"
 	defaultProtocol "github.com/thewizardplusplus/go-tcp-server/protocols/default"
 	defaultProtocolModels "github.com/thewizardplusplus/go-tcp-server/protocols/default/models"
+	defaultProtocolModelValueTypes "github.com/thewizardplusplus/go-tcp-server/protocols/default/models/value-types"
 )
 
 func TestProtocol_interface(test *testing.T) {
@@ -187,3 +189,65 @@ func TestProtocol_ExtractToken(test *testing.T) {
 		})
 	}
 }
+
+func TestProtocol_withChunkedTransfer(test *testing.T) {
+	protocol := NewProtocol(SeparationParams{
+		MessageSeparator:        []byte("\n"),
+		MessagePartSeparator:    []byte("|"),
+		HeaderSeparator:         []byte("&"),
+		HeaderKeyValueSeparator: []byte("="),
+	})
+
+	action, err := defaultProtocolModelValueTypes.NewAction([]byte("dummy"))
+	require.NoError(test, err)
+
+	request, err := defaultProtocolModels.NewRequestBuilder().
+		SetAction(action).
+		Build()
+	require.NoError(test, err)
+
+	// each byte of the body is escaped to three bytes
+	body := bytes.Repeat([]byte("%"), 3*defaultProtocol.DefaultChunkSize)
+
+	stream := &testMarshallingClientStream{
+		protocol: protocol,
+	}
+	err = defaultProtocol.WriteChunkedRequest(
+		stream,
+		request,
+		bytes.NewReader(body),
+		defaultProtocol.ChunkedTransferOptions{},
+	)
+	require.NoError(test, err)
+
+	assert.Equal(test, body, stream.body)
+}
+
+type testMarshallingClientStream struct {
+	protocol Protocol
+	body     []byte
+}
+
+func (stream *testMarshallingClientStream) WriteRequest(
+	request defaultProtocolModels.Request,
+) error {
+	marshalledRequest, err := stream.protocol.MarshalRequest(request)
+	if err != nil {
+		return err
+	}
+
+	parsedRequest, err := stream.protocol.ParseRequest(marshalledRequest)
+	if err != nil {
+		return err
+	}
+
+	stream.body = append(stream.body, parsedRequest.Body().OrEmpty().ToBytes()...)
+	return nil
+}
+
+func (stream *testMarshallingClientStream) ReadResponse() (
+	defaultProtocolModels.Response,
+	error,
+) {
+	return defaultProtocolModels.Response{}, nil
+}
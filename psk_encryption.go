@@ -0,0 +1,431 @@
+package tcpServer
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/samber/mo"
+)
+
+const (
+	DefaultMaxEncryptedFrameSize = 16 * 1024
+	DefaultPSKHandshakeTimeout   = 10 * time.Second
+
+	pskHandshakeNonceSize    = 32
+	encryptedFrameHeaderSize = 4
+)
+
+var (
+	ErrPreSharedKeyIsEmpty      = errors.New("pre-shared key is empty")
+	ErrPreSharedKeyIsMismatched = errors.New("pre-shared key is mismatched")
+	// ErrEncryptedFrameIsNotAuthentic is returned for the tampered frames,
+	// and also for the replayed and reordered ones, because their nonces
+	// don't match the expected sequence numbers
+	ErrEncryptedFrameIsNotAuthentic = errors.New(
+		"encrypted frame is not authentic",
+	)
+	ErrEncryptedFrameIsTooLarge  = errors.New("encrypted frame is too large")
+	ErrSequenceNumberIsExhausted = errors.New("sequence number is exhausted")
+)
+
+type PSKEncryptionOptions struct {
+	Key []byte
+	// NewAEAD constructs the cipher from the derived 32-byte key
+	// (e.g. `chacha20poly1305.New`); AES-256-GCM is used by default
+	NewAEAD mo.Option[func(key []byte) (cipher.AEAD, error)]
+	// HandshakeTimeout is `DefaultPSKHandshakeTimeout` by default,
+	// so the silent peer can't hold the connection before the handshake
+	HandshakeTimeout mo.Option[time.Duration]
+	// MaxFrameSize limits the plaintext size of each frame;
+	// the larger writes are split into several frames
+	MaxFrameSize mo.Option[int]
+}
+
+func NewPSKEncryptionConnectionMiddleware(
+	options PSKEncryptionOptions,
+) ConnectionMiddleware {
+	return func(handler ConnectionHandler) ConnectionHandler {
+		return ConnectionHandlerFunc(func(
+			ctx context.Context,
+			connection net.Conn,
+		) error {
+			encryptedConnection, err :=
+				NewPSKEncryptedServerConnection(connection, options)
+			if err != nil {
+				return err
+			}
+
+			return handler.HandleConnection(ctx, encryptedConnection)
+		})
+	}
+}
+
+func NewAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// PSKEncryptedConnection seals each frame with the session keys derived
+// from the pre-shared key and the random nonces of both peers;
+// the nonce of each frame is its sequence number in the direction;
+// a read interrupted in the middle of a frame (e.g. by the read timeout)
+// loses the frame boundary, so the next frames fail the authentication
+type PSKEncryptedConnection struct {
+	net.Conn
+
+	maxFrameSize    int
+	readingLock     sync.Mutex
+	readingCipher   cipher.AEAD
+	readingSequence uint64
+	readingBuffer   []byte
+	writingLock     sync.Mutex
+	writingCipher   cipher.AEAD
+	writingSequence uint64
+}
+
+// NewPSKEncryptedServerConnection performs the server side of the handshake:
+// it waits for the client nonce, replies with its own nonce and the proof
+// of the key knowledge, and then verifies the client proof
+func NewPSKEncryptedServerConnection(
+	connection net.Conn,
+	options PSKEncryptionOptions,
+) (*PSKEncryptedConnection, error) {
+	return newPSKEncryptedConnection(
+		connection,
+		options,
+		func(handshake pskHandshake) (pskSessionKeys, error) {
+			clientNonce, err := handshake.readNonce()
+			if err != nil {
+				return pskSessionKeys{}, err
+			}
+
+			serverNonce, err := handshake.writeNonce()
+			if err != nil {
+				return pskSessionKeys{}, err
+			}
+
+			keys := derivePSKSessionKeys(options.Key, clientNonce, serverNonce)
+			if err := handshake.writeProof(keys.serverProof); err != nil {
+				return pskSessionKeys{}, err
+			}
+			if err := handshake.readProof(keys.clientProof); err != nil {
+				return pskSessionKeys{}, err
+			}
+
+			return pskSessionKeys{
+				readingKey: keys.clientKey,
+				writingKey: keys.serverKey,
+			}, nil
+		},
+	)
+}
+
+// NewPSKEncryptedClientConnection performs the client side of the handshake
+func NewPSKEncryptedClientConnection(
+	connection net.Conn,
+	options PSKEncryptionOptions,
+) (*PSKEncryptedConnection, error) {
+	return newPSKEncryptedConnection(
+		connection,
+		options,
+		func(handshake pskHandshake) (pskSessionKeys, error) {
+			clientNonce, err := handshake.writeNonce()
+			if err != nil {
+				return pskSessionKeys{}, err
+			}
+
+			serverNonce, err := handshake.readNonce()
+			if err != nil {
+				return pskSessionKeys{}, err
+			}
+
+			keys := derivePSKSessionKeys(options.Key, clientNonce, serverNonce)
+			if err := handshake.readProof(keys.serverProof); err != nil {
+				return pskSessionKeys{}, err
+			}
+			if err := handshake.writeProof(keys.clientProof); err != nil {
+				return pskSessionKeys{}, err
+			}
+
+			return pskSessionKeys{
+				readingKey: keys.serverKey,
+				writingKey: keys.clientKey,
+			}, nil
+		},
+	)
+}
+
+func (connection *PSKEncryptedConnection) Read(buffer []byte) (int, error) {
+	connection.readingLock.Lock()
+	defer connection.readingLock.Unlock()
+
+	if len(connection.readingBuffer) == 0 {
+		plaintext, err := connection.readFrame()
+		if err != nil {
+			return 0, err
+		}
+
+		connection.readingBuffer = plaintext
+	}
+
+	n := copy(buffer, connection.readingBuffer)
+	connection.readingBuffer = connection.readingBuffer[n:]
+
+	return n, nil
+}
+
+func (connection *PSKEncryptedConnection) Write(buffer []byte) (int, error) {
+	connection.writingLock.Lock()
+	defer connection.writingLock.Unlock()
+
+	var writtenByteCount int
+	for len(buffer) != 0 {
+		frameSize := min(len(buffer), connection.maxFrameSize)
+		if err := connection.writeFrame(buffer[:frameSize]); err != nil {
+			return writtenByteCount, err
+		}
+
+		writtenByteCount += frameSize
+		buffer = buffer[frameSize:]
+	}
+
+	return writtenByteCount, nil
+}
+
+func (connection *PSKEncryptedConnection) readFrame() ([]byte, error) {
+	header := make([]byte, encryptedFrameHeaderSize)
+	if _, err := io.ReadFull(connection.Conn, header); err != nil {
+		return nil, err
+	}
+
+	ciphertextSize := int(binary.BigEndian.Uint32(header))
+	maxCiphertextSize :=
+		connection.maxFrameSize + connection.readingCipher.Overhead()
+	if ciphertextSize > maxCiphertextSize {
+		return nil, fmt.Errorf(
+			"%w: %d > %d",
+			ErrEncryptedFrameIsTooLarge,
+			ciphertextSize,
+			maxCiphertextSize,
+		)
+	}
+
+	ciphertext := make([]byte, ciphertextSize)
+	if _, err := io.ReadFull(connection.Conn, ciphertext); err != nil {
+		return nil, fmt.Errorf("unable to read the frame: %w", err)
+	}
+
+	nonce, err := makeFrameNonce(
+		connection.readingCipher,
+		connection.readingSequence,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err :=
+		connection.readingCipher.Open(ciphertext[:0], nonce, ciphertext, header)
+	if err != nil {
+		return nil, ErrEncryptedFrameIsNotAuthentic
+	}
+
+	connection.readingSequence++
+	return plaintext, nil
+}
+
+func (connection *PSKEncryptedConnection) writeFrame(plaintext []byte) error {
+	nonce, err := makeFrameNonce(
+		connection.writingCipher,
+		connection.writingSequence,
+	)
+	if err != nil {
+		return err
+	}
+
+	ciphertextSize := len(plaintext) + connection.writingCipher.Overhead()
+	header := binary.BigEndian.AppendUint32(nil, uint32(ciphertextSize))
+	frame := append(make([]byte, 0, len(header)+ciphertextSize), header...)
+	frame = connection.writingCipher.Seal(frame, nonce, plaintext, header)
+
+	if _, err := connection.Conn.Write(frame); err != nil {
+		return fmt.Errorf("unable to write the frame: %w", err)
+	}
+
+	connection.writingSequence++
+	return nil
+}
+
+type pskSessionKeys struct {
+	readingKey []byte
+	writingKey []byte
+}
+
+func newPSKEncryptedConnection(
+	connection net.Conn,
+	options PSKEncryptionOptions,
+	performHandshake func(handshake pskHandshake) (pskSessionKeys, error),
+) (*PSKEncryptedConnection, error) {
+	if len(options.Key) == 0 {
+		return nil, ErrPreSharedKeyIsEmpty
+	}
+
+	if err := validateMaxFrameSize(options.MaxFrameSize); err != nil {
+		return nil, err
+	}
+
+	handshakeTimeout := options.HandshakeTimeout.OrElse(DefaultPSKHandshakeTimeout)
+	handshake := pskHandshake{connection: connection}
+	if err := handshake.setDeadline(mo.Some(handshakeTimeout)); err != nil {
+		return nil, err
+	}
+
+	keys, err := performHandshake(handshake)
+	if err != nil {
+		return nil, fmt.Errorf("unable to perform the PSK handshake: %w", err)
+	}
+
+	if err := handshake.setDeadline(mo.None[time.Duration]()); err != nil {
+		return nil, err
+	}
+
+	newAEAD := options.NewAEAD.OrElse(NewAESGCM)
+	readingCipher, err := newAEAD(keys.readingKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct the reading cipher: %w", err)
+	}
+
+	writingCipher, err := newAEAD(keys.writingKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct the writing cipher: %w", err)
+	}
+
+	return &PSKEncryptedConnection{
+		Conn:          connection,
+		maxFrameSize:  options.MaxFrameSize.OrElse(DefaultMaxEncryptedFrameSize),
+		readingCipher: readingCipher,
+		writingCipher: writingCipher,
+	}, nil
+}
+
+type pskHandshake struct {
+	connection net.Conn
+}
+
+func (handshake pskHandshake) setDeadline(
+	timeout mo.Option[time.Duration],
+) error {
+	var deadline time.Time
+	if timeout, isPresent := timeout.Get(); isPresent {
+		deadline = time.Now().Add(timeout)
+	}
+
+	if err := handshake.connection.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("unable to set the deadline: %w", err)
+	}
+
+	return nil
+}
+
+func (handshake pskHandshake) writeNonce() ([]byte, error) {
+	nonce := make([]byte, pskHandshakeNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("unable to generate the nonce: %w", err)
+	}
+
+	if _, err := handshake.connection.Write(nonce); err != nil {
+		return nil, fmt.Errorf("unable to write the nonce: %w", err)
+	}
+
+	return nonce, nil
+}
+
+func (handshake pskHandshake) readNonce() ([]byte, error) {
+	nonce := make([]byte, pskHandshakeNonceSize)
+	if _, err := io.ReadFull(handshake.connection, nonce); err != nil {
+		return nil, fmt.Errorf("unable to read the nonce: %w", err)
+	}
+
+	return nonce, nil
+}
+
+func (handshake pskHandshake) writeProof(proof []byte) error {
+	if _, err := handshake.connection.Write(proof); err != nil {
+		return fmt.Errorf("unable to write the proof: %w", err)
+	}
+
+	return nil
+}
+
+func (handshake pskHandshake) readProof(expectedProof []byte) error {
+	proof := make([]byte, len(expectedProof))
+	if _, err := io.ReadFull(handshake.connection, proof); err != nil {
+		return fmt.Errorf("unable to read the proof: %w", err)
+	}
+
+	if !hmac.Equal(proof, expectedProof) {
+		return ErrPreSharedKeyIsMismatched
+	}
+
+	return nil
+}
+
+type pskDerivedKeys struct {
+	clientKey   []byte
+	serverKey   []byte
+	clientProof []byte
+	serverProof []byte
+}
+
+func derivePSKSessionKeys(
+	key []byte,
+	clientNonce []byte,
+	serverNonce []byte,
+) pskDerivedKeys {
+	// HKDF-SHA256 (RFC 5869) with the single-block output
+	extractor := hmac.New(sha256.New, slices.Concat(clientNonce, serverNonce))
+	extractor.Write(key)
+	pseudorandomKey := extractor.Sum(nil)
+
+	expand := func(label string) []byte {
+		expander := hmac.New(sha256.New, pseudorandomKey)
+		expander.Write([]byte(label))
+		expander.Write([]byte{1})
+
+		return expander.Sum(nil)
+	}
+
+	return pskDerivedKeys{
+		clientKey:   expand("client key"),
+		serverKey:   expand("server key"),
+		clientProof: expand("client proof"),
+		serverProof: expand("server proof"),
+	}
+}
+
+func makeFrameNonce(aead cipher.AEAD, sequence uint64) ([]byte, error) {
+	if sequence == math.MaxUint64 {
+		return nil, ErrSequenceNumberIsExhausted
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], sequence)
+
+	return nonce, nil
+}
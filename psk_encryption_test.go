@@ -0,0 +1,393 @@
+package tcpServer_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestNewPSKEncryptionConnectionMiddleware(test *testing.T) {
+	type args struct {
+		serverOptions tcpServer.PSKEncryptionOptions
+		clientOptions tcpServer.PSKEncryptionOptions
+	}
+
+	for _, data := range []struct {
+		name            string
+		args            args
+		wantClientErr   assert.ErrorAssertionFunc
+		wantHandlingErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/with the default cipher",
+			args: args{
+				serverOptions: tcpServer.PSKEncryptionOptions{
+					Key:          []byte("key"),
+					MaxFrameSize: mo.Some(16),
+				},
+				clientOptions: tcpServer.PSKEncryptionOptions{
+					Key:          []byte("key"),
+					MaxFrameSize: mo.Some(16),
+				},
+			},
+			wantClientErr:   assert.NoError,
+			wantHandlingErr: assert.NoError,
+		},
+		{
+			name: "success/with the custom cipher",
+			args: args{
+				serverOptions: tcpServer.PSKEncryptionOptions{
+					Key:     []byte("key"),
+					NewAEAD: mo.Some(newTestAES128GCM),
+				},
+				clientOptions: tcpServer.PSKEncryptionOptions{
+					Key:     []byte("key"),
+					NewAEAD: mo.Some(newTestAES128GCM),
+				},
+			},
+			wantClientErr:   assert.NoError,
+			wantHandlingErr: assert.NoError,
+		},
+		{
+			name: "error/with the mismatched key",
+			args: args{
+				serverOptions: tcpServer.PSKEncryptionOptions{
+					Key: []byte("key"),
+				},
+				clientOptions: tcpServer.PSKEncryptionOptions{
+					Key: []byte("another key"),
+				},
+			},
+			wantClientErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(test, err, tcpServer.ErrPreSharedKeyIsMismatched)
+			},
+			wantHandlingErr: assert.Error,
+		},
+		{
+			name: "error/with the empty key",
+			args: args{
+				serverOptions: tcpServer.PSKEncryptionOptions{
+					Key: []byte("key"),
+				},
+				clientOptions: tcpServer.PSKEncryptionOptions{},
+			},
+			wantClientErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(test, err, tcpServer.ErrPreSharedKeyIsEmpty)
+			},
+			wantHandlingErr: assert.Error,
+		},
+		{
+			name: "error/with the zero max frame size",
+			args: args{
+				serverOptions: tcpServer.PSKEncryptionOptions{
+					Key: []byte("key"),
+				},
+				clientOptions: tcpServer.PSKEncryptionOptions{
+					Key:          []byte("key"),
+					MaxFrameSize: mo.Some(0),
+				},
+			},
+			wantClientErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(test, err, tcpServer.ErrInvalidMaxFrameSize)
+			},
+			wantHandlingErr: assert.Error,
+		},
+		{
+			name: "error/with the negative max frame size",
+			args: args{
+				serverOptions: tcpServer.PSKEncryptionOptions{
+					Key: []byte("key"),
+				},
+				clientOptions: tcpServer.PSKEncryptionOptions{
+					Key:          []byte("key"),
+					MaxFrameSize: mo.Some(-5),
+				},
+			},
+			wantClientErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(test, err, tcpServer.ErrInvalidMaxFrameSize)
+			},
+			wantHandlingErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			listener, err := net.Listen(tcpServer.TCPServerNetwork, "localhost:0")
+			require.NoError(test, err)
+			defer listener.Close()
+
+			handler := tcpServer.NewPSKEncryptionConnectionMiddleware(
+				data.args.serverOptions,
+			)(
+				tcpServer.NewDefaultConnectionHandler(
+					tcpServer.DefaultConnectionHandlerOptions[string, string]{
+						ServerProtocol: testLineProtocol{},
+						RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+							ctx context.Context,
+							request string,
+						) (string, error) {
+							return "response to " + request, nil
+						}),
+					},
+				),
+			)
+
+			var receivedData bytes.Buffer
+			handlingErr := make(chan error, 1)
+			go func() {
+				connection, err := listener.Accept()
+				if err != nil {
+					handlingErr <- err
+					return
+				}
+				defer connection.Close()
+
+				handlingErr <- handler.HandleConnection(
+					context.Background(),
+					testRecordingConnection{Conn: connection, data: &receivedData},
+				)
+			}()
+
+			client, err := tcpServer.NewTCPClient(
+				context.Background(),
+				listener.Addr().String(),
+				tcpServer.TCPClientOptions[string, string]{
+					PSKEncryption:  mo.Some(data.args.clientOptions),
+					ClientProtocol: testLineProtocol{},
+				},
+			)
+			data.wantClientErr(test, err)
+			if err != nil {
+				data.wantHandlingErr(test, <-handlingErr)
+				return
+			}
+
+			request := strings.Repeat("request ", 10)
+			for range 3 {
+				response, err := client.SendRequest(request)
+				require.NoError(test, err)
+
+				assert.Equal(test, "response to "+request, response)
+			}
+
+			require.NoError(test, client.Close())
+			data.wantHandlingErr(test, <-handlingErr)
+
+			assert.NotContains(test, receivedData.String(), "request")
+		})
+	}
+}
+
+func TestPSKEncryptedConnection_withManipulatedFrames(test *testing.T) {
+	for _, data := range []struct {
+		name           string
+		manipulate     func(frames [][]byte) [][]byte
+		wantMessages   []string
+		wantReadingErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			manipulate: func(frames [][]byte) [][]byte {
+				return frames
+			},
+			wantMessages:   []string{"one", "two"},
+			wantReadingErr: assert.NoError,
+		},
+		{
+			name: "error/with the tampered frame",
+			manipulate: func(frames [][]byte) [][]byte {
+				frames[1][len(frames[1])-1] ^= 0xff
+				return frames
+			},
+			wantMessages: []string{"one"},
+			wantReadingErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(
+					test,
+					err,
+					tcpServer.ErrEncryptedFrameIsNotAuthentic,
+				)
+			},
+		},
+		{
+			name: "error/with the replayed frame",
+			manipulate: func(frames [][]byte) [][]byte {
+				return [][]byte{frames[0], frames[0]}
+			},
+			wantMessages: []string{"one"},
+			wantReadingErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(
+					test,
+					err,
+					tcpServer.ErrEncryptedFrameIsNotAuthentic,
+				)
+			},
+		},
+		{
+			name: "error/with the reordered frames",
+			manipulate: func(frames [][]byte) [][]byte {
+				return [][]byte{frames[1], frames[0]}
+			},
+			wantMessages: nil,
+			wantReadingErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(
+					test,
+					err,
+					tcpServer.ErrEncryptedFrameIsNotAuthentic,
+				)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			clientConnection, clientProxyConnection := net.Pipe()
+			defer clientConnection.Close()
+			defer clientProxyConnection.Close()
+
+			serverConnection, serverProxyConnection := net.Pipe()
+			defer serverConnection.Close()
+			defer serverProxyConnection.Close()
+
+			options := tcpServer.PSKEncryptionOptions{Key: []byte("key")}
+			encryptedServerConnection :=
+				make(chan *tcpServer.PSKEncryptedConnection, 1)
+			go func() {
+				connection, err :=
+					tcpServer.NewPSKEncryptedServerConnection(serverConnection, options)
+				assert.NoError(test, err)
+
+				encryptedServerConnection <- connection
+			}()
+
+			encryptedClientConnection := make(chan *tcpServer.PSKEncryptedConnection, 1)
+			go func() {
+				connection, err :=
+					tcpServer.NewPSKEncryptedClientConnection(clientConnection, options)
+				assert.NoError(test, err)
+
+				encryptedClientConnection <- connection
+			}()
+
+			// the client nonce, the server nonce with its proof, the client proof
+			for _, step := range []struct {
+				destination net.Conn
+				source      net.Conn
+				size        int64
+			}{
+				{serverProxyConnection, clientProxyConnection, 32},
+				{clientProxyConnection, serverProxyConnection, 64},
+				{serverProxyConnection, clientProxyConnection, 32},
+			} {
+				_, err := io.CopyN(step.destination, step.source, step.size)
+				require.NoError(test, err)
+			}
+
+			go func() {
+				connection := <-encryptedClientConnection
+				for _, message := range []string{"one", "two"} {
+					_, err := connection.Write([]byte(message))
+					assert.NoError(test, err)
+				}
+			}()
+
+			var frames [][]byte
+			for range 2 {
+				frames = append(frames, readTestEncryptedFrame(test, clientProxyConnection))
+			}
+
+			go func() {
+				for _, frame := range data.manipulate(frames) {
+					serverProxyConnection.Write(frame) //nolint:errcheck
+				}
+			}()
+
+			connection := <-encryptedServerConnection
+			var gotMessages []string
+			var readingErr error
+			for range 2 {
+				buffer := make([]byte, 16)
+				n, err := connection.Read(buffer)
+				if err != nil {
+					readingErr = err
+					break
+				}
+
+				gotMessages = append(gotMessages, string(buffer[:n]))
+			}
+
+			assert.Equal(test, data.wantMessages, gotMessages)
+			data.wantReadingErr(test, readingErr)
+		})
+	}
+}
+
+type testRecordingConnection struct {
+	net.Conn
+
+	data *bytes.Buffer
+}
+
+func (connection testRecordingConnection) Read(buffer []byte) (int, error) {
+	n, err := connection.Conn.Read(buffer)
+	connection.data.Write(buffer[:n])
+
+	return n, err
+}
+
+func newTestAES128GCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key[:16])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func readTestEncryptedFrame(test *testing.T, connection net.Conn) []byte {
+	header := make([]byte, 4)
+	_, err := io.ReadFull(connection, header)
+	require.NoError(test, err)
+
+	frame := make([]byte, 4+binary.BigEndian.Uint32(header))
+	copy(frame, header)
+
+	_, err = io.ReadFull(connection, frame[4:])
+	require.NoError(test, err)
+
+	return frame
+}
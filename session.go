@@ -0,0 +1,355 @@
+package tcpServer
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samber/mo"
+)
+
+var (
+	ErrSessionIsClosed            = errors.New("session is closed")
+	ErrSessionProtocolIsMissed    = errors.New("session protocol is missed")
+	ErrUnexpectedRequestInTheCall = errors.New("unexpected request in the call")
+)
+
+var (
+	lastSessionID atomic.Uint64
+)
+
+// SessionProtocol distinguishes the messages initiated by the server
+// from the regular request-response exchange;
+// the server sends the pushes and the calls as responses,
+// and the client replies to the calls with requests
+type SessionProtocol[Req Request, Resp Response] interface {
+	MarkAsPush(message Resp) (Resp, error)
+	IsPush(message Resp) bool
+	MarkAsCall(message Resp, callID uint64) (Resp, error)
+	CallID(message Resp) mo.Option[uint64]
+	MarkAsCallReply(reply Req, callID uint64) (Req, error)
+	CallReplyID(request Req) mo.Option[uint64]
+}
+
+// Session is a handle of the connection that allows the server
+// to push messages to the client and to call it
+// and keeps the values across the requests of this connection;
+// it's available from the handler context and remains valid
+// after the handling, until the connection is closed
+type Session[Req Request, Resp Response] struct {
+	id              uint64
+	startTime       time.Time
+	storage         *SessionStorage
+	handler         DefaultConnectionHandler[Req, Resp]
+	baseConnection  net.Conn
+	connection      net.Conn
+	scanner         *bufio.Scanner
+	writingLock     sync.Mutex
+	pendingCallLock sync.Mutex
+	pendingCalls    map[uint64]chan Req
+	lastCallID      atomic.Uint64
+	principal       atomic.Pointer[Principal]
+	hijackHandler   atomic.Pointer[HijackHandler]
+	rawConnection   net.Conn
+	timeoutOptions  mo.Option[timeoutConnectionOptions]
+	splitFunc       mo.Option[bufio.SplitFunc]
+	bufferedData    []byte
+	tlsConnection   atomic.Pointer[tls.Conn]
+	authentication  serverAuthentication
+	closingLock     sync.Mutex
+	closeCallbacks  []func()
+	closed          chan struct{}
+	closingOnce     sync.Once
+}
+
+type sessionContextKey struct{}
+
+func SessionFromContext[Req Request, Resp Response](
+	ctx context.Context,
+) (*Session[Req, Resp], bool) {
+	session, isPresent :=
+		ctx.Value(sessionContextKey{}).(*Session[Req, Resp])
+	return session, isPresent
+}
+
+func newSession[Req Request, Resp Response](
+	handler DefaultConnectionHandler[Req, Resp],
+	connection net.Conn,
+	scanner *bufio.Scanner,
+) *Session[Req, Resp] {
+	return &Session[Req, Resp]{
+		id:             lastSessionID.Add(1),
+		startTime:      time.Now(),
+		storage:        newSessionStorage(),
+		handler:        handler,
+		baseConnection: connection,
+		connection:     connection,
+		scanner:        scanner,
+		pendingCalls:   make(map[uint64]chan Req),
+		closed:         make(chan struct{}),
+	}
+}
+
+// ID is unique among the connections of the process
+func (session *Session[Req, Resp]) ID() uint64 {
+	return session.id
+}
+
+func (session *Session[Req, Resp]) RemoteAddress() net.Addr {
+	return session.baseConnection.RemoteAddr()
+}
+
+func (session *Session[Req, Resp]) StartTime() time.Time {
+	return session.startTime
+}
+
+func (session *Session[Req, Resp]) Storage() *SessionStorage {
+	return session.storage
+}
+
+// OnClose registers the callback called when the handling of the connection
+// is finished; the callbacks are called in the reverse order,
+// and the callback registered after that is called immediately
+func (session *Session[Req, Resp]) OnClose(callback func()) {
+	session.closingLock.Lock()
+	select {
+	case <-session.closed:
+		session.closingLock.Unlock()
+
+		callback()
+		return
+
+	default:
+	}
+
+	session.closeCallbacks = append(session.closeCallbacks, callback)
+	session.closingLock.Unlock()
+}
+
+// TLSConnectionState returns the state of the connection
+// only after its upgrade to TLS
+func (session *Session[Req, Resp]) TLSConnectionState() (
+	tls.ConnectionState,
+	bool,
+) {
+	tlsConnection := session.tlsConnection.Load()
+	if tlsConnection == nil {
+		return tls.ConnectionState{}, false
+	}
+
+	return tlsConnection.ConnectionState(), true
+}
+
+// Done is closed when the handling of the connection is finished
+func (session *Session[Req, Resp]) Done() <-chan struct{} {
+	return session.closed
+}
+
+// Principal returns the principal, if the peer is authenticated
+func (session *Session[Req, Resp]) Principal() (Principal, bool) {
+	principal := session.principal.Load()
+	if principal == nil {
+		return Principal{}, false
+	}
+
+	return *principal, true
+}
+
+// Close closes the connection, so its handling is finished as well
+func (session *Session[Req, Resp]) Close() error {
+	if err := session.baseConnection.Close(); err != nil {
+		return fmt.Errorf("unable to close the connection: %w", err)
+	}
+
+	return nil
+}
+
+// IsPushingAvailable reports whether the session protocol is configured,
+// so `Push()` and `Call()` can be used
+func (session *Session[Req, Resp]) IsPushingAvailable() bool {
+	return session.handler.options.SessionProtocol.IsPresent()
+}
+
+func (session *Session[Req, Resp]) Push(message Resp) error {
+	protocol, isPresent := session.handler.options.SessionProtocol.Get()
+	if !isPresent {
+		return ErrSessionProtocolIsMissed
+	}
+
+	pushMessage, err := protocol.MarkAsPush(message)
+	if err != nil {
+		return fmt.Errorf("unable to mark the message as the push: %w", err)
+	}
+
+	if err := session.writeResponse(pushMessage); err != nil {
+		return fmt.Errorf("unable to push the message: %w", err)
+	}
+
+	return nil
+}
+
+// Call sends the message to the client and waits for its reply;
+// outside the handling of the same connection, the replies are received
+// only while the connection handler waits for the next request
+func (session *Session[Req, Resp]) Call(
+	ctx context.Context,
+	message Resp,
+) (Req, error) {
+	var zeroReply Req
+
+	protocol, isPresent := session.handler.options.SessionProtocol.Get()
+	if !isPresent {
+		return zeroReply, ErrSessionProtocolIsMissed
+	}
+
+	callID := session.lastCallID.Add(1)
+	replies := make(chan Req, 1)
+	session.pendingCallLock.Lock()
+	session.pendingCalls[callID] = replies
+	session.pendingCallLock.Unlock()
+
+	defer func() {
+		session.pendingCallLock.Lock()
+		delete(session.pendingCalls, callID)
+		session.pendingCallLock.Unlock()
+	}()
+
+	callMessage, err := protocol.MarkAsCall(message, callID)
+	if err != nil {
+		return zeroReply, fmt.Errorf(
+			"unable to mark the message as the call: %w",
+			err,
+		)
+	}
+
+	if err := session.writeResponse(callMessage); err != nil {
+		return zeroReply, fmt.Errorf("unable to send the call: %w", err)
+	}
+
+	// within the handling, nobody else reads the requests of this connection
+	stream, isPresent :=
+		ctx.Value(serverStreamContextKey{}).(defaultServerStream[Req, Resp])
+	if isPresent && stream.session == session {
+		return session.waitForReplyWithinHandling(ctx, protocol, replies)
+	}
+
+	select {
+	case reply := <-replies:
+		return reply, nil
+
+	case <-session.closed:
+		return zeroReply, ErrSessionIsClosed
+
+	case <-ctx.Done():
+		return zeroReply, fmt.Errorf("context is done: %w", ctx.Err())
+	}
+}
+
+func (session *Session[Req, Resp]) waitForReplyWithinHandling(
+	ctx context.Context,
+	protocol SessionProtocol[Req, Resp],
+	replies chan Req,
+) (Req, error) {
+	var zeroReply Req
+
+	for {
+		select {
+		case reply := <-replies:
+			return reply, nil
+
+		case <-ctx.Done():
+			return zeroReply, fmt.Errorf("context is done: %w", ctx.Err())
+
+		default:
+		}
+
+		request, err := session.handler.readRequest(
+			session.connection,
+			session.scanner,
+			session.handler.options.ReadTimeout,
+		)
+		if err != nil {
+			return zeroReply, fmt.Errorf("unable to read the reply: %w", err)
+		}
+
+		callID, isPresent := protocol.CallReplyID(request).Get()
+		if !isPresent {
+			return zeroReply, ErrUnexpectedRequestInTheCall
+		}
+
+		session.deliverReply(callID, request)
+	}
+}
+
+func (session *Session[Req, Resp]) readRequest(
+	readTimeout mo.Option[time.Duration],
+) (Req, error) {
+	for {
+		request, err := session.handler.readRequest(
+			session.connection,
+			session.scanner,
+			readTimeout,
+		)
+		if err != nil {
+			return request, err
+		}
+
+		if protocol, isPresent :=
+			session.handler.options.SessionProtocol.Get(); isPresent {
+			if callID, isPresent := protocol.CallReplyID(request).Get(); isPresent {
+				session.deliverReply(callID, request)
+				continue
+			}
+		}
+
+		return request, nil
+	}
+}
+
+func (session *Session[Req, Resp]) deliverReply(callID uint64, reply Req) {
+	session.pendingCallLock.Lock()
+	defer session.pendingCallLock.Unlock()
+
+	// the late replies to the abandoned calls are ignored
+	if replies, isPresent := session.pendingCalls[callID]; isPresent {
+		select {
+		case replies <- reply:
+		default:
+		}
+	}
+}
+
+func (session *Session[Req, Resp]) writeResponse(response Resp) error {
+	session.writingLock.Lock()
+	defer session.writingLock.Unlock()
+
+	return session.handler.writeResponse(session.connection, response)
+}
+
+func (session *Session[Req, Resp]) markAsClosed() {
+	session.closingOnce.Do(func() {
+		session.closingLock.Lock()
+		close(session.closed)
+		closeCallbacks := session.closeCallbacks
+		session.closeCallbacks = nil
+		session.closingLock.Unlock()
+
+		for index := len(closeCallbacks) - 1; index >= 0; index-- {
+			closeCallbacks[index]()
+		}
+	})
+}
+
+func contextWithSession[Req Request, Resp Response](
+	ctx context.Context,
+	session *Session[Req, Resp],
+) context.Context {
+	ctx = contextWithSessionStorage(ctx, session.storage)
+	return context.WithValue(ctx, sessionContextKey{}, session)
+}
@@ -0,0 +1,94 @@
+package tcpServer
+
+import (
+	"context"
+	"sync"
+)
+
+// SessionStorage keeps the values across the requests of the same connection;
+// use `SessionKey` for the typed access; the zero value is ready to use
+type SessionStorage struct {
+	lock   sync.RWMutex
+	values map[any]any
+}
+
+type sessionStorageContextKey struct{}
+
+// SessionStorageFromContext doesn't require the types of the messages
+// unlike `SessionFromContext()`
+func SessionStorageFromContext(ctx context.Context) (*SessionStorage, bool) {
+	storage, isPresent :=
+		ctx.Value(sessionStorageContextKey{}).(*SessionStorage)
+	return storage, isPresent
+}
+
+func newSessionStorage() *SessionStorage {
+	return &SessionStorage{}
+}
+
+func (storage *SessionStorage) Get(key any) (any, bool) {
+	storage.lock.RLock()
+	defer storage.lock.RUnlock()
+
+	value, isPresent := storage.values[key]
+	return value, isPresent
+}
+
+func (storage *SessionStorage) Set(key any, value any) {
+	storage.lock.Lock()
+	defer storage.lock.Unlock()
+
+	if storage.values == nil {
+		storage.values = make(map[any]any)
+	}
+
+	storage.values[key] = value
+}
+
+func (storage *SessionStorage) Delete(key any) {
+	storage.lock.Lock()
+	defer storage.lock.Unlock()
+
+	delete(storage.values, key)
+}
+
+// SessionKey is distinguished by both its name and its type,
+// so the keys of different packages don't collide
+// unless they share the same type
+type SessionKey[T any] struct {
+	name string
+}
+
+func NewSessionKey[T any](name string) SessionKey[T] {
+	return SessionKey[T]{name: name}
+}
+
+func (key SessionKey[T]) Name() string {
+	return key.name
+}
+
+func (key SessionKey[T]) Get(storage *SessionStorage) (T, bool) {
+	value, isPresent := storage.Get(key)
+	if !isPresent {
+		var zeroValue T
+		return zeroValue, false
+	}
+
+	typedValue, isTyped := value.(T)
+	return typedValue, isTyped
+}
+
+func (key SessionKey[T]) Set(storage *SessionStorage, value T) {
+	storage.Set(key, value)
+}
+
+func (key SessionKey[T]) Delete(storage *SessionStorage) {
+	storage.Delete(key)
+}
+
+func contextWithSessionStorage(
+	ctx context.Context,
+	storage *SessionStorage,
+) context.Context {
+	return context.WithValue(ctx, sessionStorageContextKey{}, storage)
+}
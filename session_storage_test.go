@@ -0,0 +1,67 @@
+package tcpServer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestSessionStorageFromContext(test *testing.T) {
+	_, isPresent := tcpServer.SessionStorageFromContext(context.Background())
+
+	assert.False(test, isPresent)
+}
+
+func TestSessionKey(test *testing.T) {
+	for _, data := range []struct {
+		name          string
+		prepare       func(storage *tcpServer.SessionStorage)
+		wantValue     int
+		wantIsPresent bool
+	}{
+		{
+			name: "success",
+			prepare: func(storage *tcpServer.SessionStorage) {
+				tcpServer.NewSessionKey[int]("key").Set(storage, 23)
+			},
+			wantValue:     23,
+			wantIsPresent: true,
+		},
+		{
+			name:          "error/without the value",
+			prepare:       func(storage *tcpServer.SessionStorage) {},
+			wantValue:     0,
+			wantIsPresent: false,
+		},
+		{
+			name: "error/with the deleted value",
+			prepare: func(storage *tcpServer.SessionStorage) {
+				key := tcpServer.NewSessionKey[int]("key")
+				key.Set(storage, 23)
+				key.Delete(storage)
+			},
+			wantValue:     0,
+			wantIsPresent: false,
+		},
+		{
+			name: "error/with the value of another type",
+			prepare: func(storage *tcpServer.SessionStorage) {
+				tcpServer.NewSessionKey[string]("key").Set(storage, "value")
+			},
+			wantValue:     0,
+			wantIsPresent: false,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var storage tcpServer.SessionStorage
+			data.prepare(&storage)
+
+			value, isPresent := tcpServer.NewSessionKey[int]("key").Get(&storage)
+
+			assert.Equal(test, data.wantValue, value)
+			assert.Equal(test, data.wantIsPresent, isPresent)
+		})
+	}
+}
@@ -0,0 +1,394 @@
+package tcpServer_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestSessionFromContext(test *testing.T) {
+	_, isPresent := tcpServer.SessionFromContext[string, string](
+		context.Background(),
+	)
+
+	assert.False(test, isPresent)
+}
+
+func TestSession_withinHandling(test *testing.T) {
+	for _, data := range []struct {
+		name            string
+		request         string
+		sessionProtocol mo.Option[tcpServer.SessionProtocol[string, string]]
+		wantResponse    string
+		wantPushes      []string
+	}{
+		{
+			name:    "success/push",
+			request: "push",
+			sessionProtocol: mo.Some[tcpServer.SessionProtocol[string, string]](
+				testSessionProtocol{},
+			),
+			wantResponse: "pushed",
+			wantPushes:   []string{"push:one", "push:two"},
+		},
+		{
+			name:    "success/call",
+			request: "call",
+			sessionProtocol: mo.Some[tcpServer.SessionProtocol[string, string]](
+				testSessionProtocol{},
+			),
+			wantResponse: "reply:1:answer to call:1:question",
+			wantPushes:   nil,
+		},
+		{
+			name:    "success/check/with the session protocol",
+			request: "check",
+			sessionProtocol: mo.Some[tcpServer.SessionProtocol[string, string]](
+				testSessionProtocol{},
+			),
+			wantResponse: "true",
+			wantPushes:   nil,
+		},
+		{
+			name:            "success/check/without the session protocol",
+			request:         "check",
+			sessionProtocol: mo.None[tcpServer.SessionProtocol[string, string]](),
+			wantResponse:    "false",
+			wantPushes:      nil,
+		},
+		{
+			name:            "error/push without the session protocol",
+			request:         "push",
+			sessionProtocol: mo.None[tcpServer.SessionProtocol[string, string]](),
+			wantResponse:    tcpServer.ErrSessionProtocolIsMissed.Error(),
+			wantPushes:      nil,
+		},
+		{
+			name:            "error/call without the session protocol",
+			request:         "call",
+			sessionProtocol: mo.None[tcpServer.SessionProtocol[string, string]](),
+			wantResponse:    tcpServer.ErrSessionProtocolIsMissed.Error(),
+			wantPushes:      nil,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			serverConnection, clientConnection := net.Pipe()
+			defer serverConnection.Close()
+
+			handler := tcpServer.NewDefaultConnectionHandler(
+				tcpServer.DefaultConnectionHandlerOptions[string, string]{
+					SessionProtocol: data.sessionProtocol,
+					ServerProtocol:  testLineProtocol{},
+					RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+						ctx context.Context,
+						request string,
+					) (string, error) {
+						session, isPresent :=
+							tcpServer.SessionFromContext[string, string](ctx)
+						require.True(test, isPresent)
+
+						switch request {
+						case "push":
+							for _, message := range []string{"one", "two"} {
+								if err := session.Push(message); err != nil {
+									return err.Error(), nil
+								}
+							}
+
+							return "pushed", nil
+
+						case "call":
+							reply, err := session.Call(ctx, "question")
+							if err != nil {
+								return err.Error(), nil
+							}
+
+							return reply, nil
+
+						case "check":
+							return strconv.FormatBool(session.IsPushingAvailable()), nil
+						}
+
+						return "unknown", nil
+					}),
+				},
+			)
+			go handler.HandleConnection( //nolint:errcheck
+				context.Background(),
+				serverConnection,
+			)
+
+			var gotPushes []string
+			client := tcpServer.NewTCPClientFromConnection(
+				clientConnection,
+				tcpServer.TCPClientOptions[string, string]{
+					Session: mo.Some(tcpServer.ClientSessionOptions[string, string]{
+						Protocol: testSessionProtocol{},
+						PushHandler: mo.Some[tcpServer.PushHandler[string]](
+							func(message string) {
+								gotPushes = append(gotPushes, message)
+							},
+						),
+						CallHandler: mo.Some[tcpServer.CallHandler[string, string]](
+							func(message string) string {
+								return "answer to " + message
+							},
+						),
+					}),
+					ClientProtocol: testLineProtocol{},
+				},
+			)
+			defer client.Close()
+
+			gotResponse, err := client.SendRequest(data.request)
+			require.NoError(test, err)
+
+			assert.Equal(test, data.wantResponse, gotResponse)
+			assert.Equal(test, data.wantPushes, gotPushes)
+		})
+	}
+}
+
+func TestSession_outsideHandling(test *testing.T) {
+	serverConnection, clientConnection := net.Pipe()
+
+	sessions := make(chan *tcpServer.Session[string, string], 1)
+	handler := tcpServer.NewDefaultConnectionHandler(
+		tcpServer.DefaultConnectionHandlerOptions[string, string]{
+			SessionProtocol: mo.Some[tcpServer.SessionProtocol[string, string]](
+				testSessionProtocol{},
+			),
+			ServerProtocol: testLineProtocol{},
+			RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+				ctx context.Context,
+				request string,
+			) (string, error) {
+				session, isPresent := tcpServer.SessionFromContext[string, string](ctx)
+				require.True(test, isPresent)
+
+				sessions <- session
+				return "subscribed", nil
+			}),
+		},
+	)
+
+	handlingDone := make(chan struct{})
+	go func() {
+		defer close(handlingDone)
+		defer serverConnection.Close()
+
+		handler.HandleConnection( //nolint:errcheck
+			context.Background(),
+			serverConnection,
+		)
+	}()
+
+	var pushLock sync.Mutex
+	var gotPushes []string
+	client := tcpServer.NewTCPClientFromConnection(
+		clientConnection,
+		tcpServer.TCPClientOptions[string, string]{
+			Session: mo.Some(tcpServer.ClientSessionOptions[string, string]{
+				Protocol: testSessionProtocol{},
+				PushHandler: mo.Some[tcpServer.PushHandler[string]](
+					func(message string) {
+						pushLock.Lock()
+						defer pushLock.Unlock()
+
+						gotPushes = append(gotPushes, message)
+					},
+				),
+				CallHandler: mo.Some[tcpServer.CallHandler[string, string]](
+					func(message string) string {
+						return "answer to " + message
+					},
+				),
+			}),
+			ClientProtocol: testLineProtocol{},
+		},
+	)
+
+	response, err := client.SendRequest("subscribe")
+	require.NoError(test, err)
+	require.Equal(test, "subscribed", response)
+
+	session := <-sessions
+	for index := range 3 {
+		reply, err := session.Call(
+			context.Background(),
+			fmt.Sprintf("question #%d", index+1),
+		)
+		require.NoError(test, err)
+
+		assert.Equal(
+			test,
+			fmt.Sprintf("reply:%d:answer to call:%d:question #%d", index+1, index+1, index+1), //nolint:lll
+			reply,
+		)
+	}
+
+	require.NoError(test, session.Push("news"))
+
+	// the regular exchange is still correlated
+	response, err = client.SendRequest("subscribe")
+	require.NoError(test, err)
+	assert.Equal(test, "subscribed", response)
+	<-sessions
+
+	require.NoError(test, client.Close())
+	<-handlingDone
+
+	_, err = session.Call(context.Background(), "question")
+	assert.Error(test, err)
+
+	pushLock.Lock()
+	defer pushLock.Unlock()
+
+	assert.Equal(test, []string{"push:news"}, gotPushes)
+}
+
+func TestSession_storage(test *testing.T) {
+	serverConnection, clientConnection := net.Pipe()
+
+	requestCountKey := tcpServer.NewSessionKey[int]("request-count")
+	sessions := make(chan *tcpServer.Session[string, string], 1)
+	var closingLock sync.Mutex
+	var closingOrder []string
+	handler := tcpServer.NewDefaultConnectionHandler(
+		tcpServer.DefaultConnectionHandlerOptions[string, string]{
+			ServerProtocol: testLineProtocol{},
+			RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+				ctx context.Context,
+				request string,
+			) (string, error) {
+				storage, isPresent := tcpServer.SessionStorageFromContext(ctx)
+				require.True(test, isPresent)
+
+				requestCount, isPresent := requestCountKey.Get(storage)
+				if !isPresent {
+					session, isPresent :=
+						tcpServer.SessionFromContext[string, string](ctx)
+					require.True(test, isPresent)
+
+					for _, name := range []string{"first", "second"} {
+						session.OnClose(func() {
+							closingLock.Lock()
+							defer closingLock.Unlock()
+
+							closingOrder = append(closingOrder, name)
+						})
+					}
+
+					sessions <- session
+				}
+
+				requestCountKey.Set(storage, requestCount+1)
+				return request + ":" + strconv.Itoa(requestCount+1), nil
+			}),
+		},
+	)
+
+	handlingDone := make(chan struct{})
+	go func() {
+		defer close(handlingDone)
+		defer serverConnection.Close()
+
+		handler.HandleConnection( //nolint:errcheck
+			context.Background(),
+			serverConnection,
+		)
+	}()
+
+	client := tcpServer.NewTCPClientFromConnection(
+		clientConnection,
+		tcpServer.TCPClientOptions[string, string]{
+			ClientProtocol: testLineProtocol{},
+		},
+	)
+
+	startTime := time.Now()
+	for index := range 3 {
+		response, err := client.SendRequest("request")
+		require.NoError(test, err)
+
+		assert.Equal(test, "request:"+strconv.Itoa(index+1), response)
+	}
+
+	session := <-sessions
+	assert.NotZero(test, session.ID())
+	assert.Equal(test, serverConnection.RemoteAddr(), session.RemoteAddress())
+	assert.WithinDuration(test, startTime, session.StartTime(), time.Second)
+
+	requestCount, isPresent := requestCountKey.Get(session.Storage())
+	assert.True(test, isPresent)
+	assert.Equal(test, 3, requestCount)
+
+	require.NoError(test, client.Close())
+	<-handlingDone
+
+	isCalled := false
+	session.OnClose(func() { isCalled = true })
+	assert.True(test, isCalled)
+
+	closingLock.Lock()
+	defer closingLock.Unlock()
+
+	assert.Equal(test, []string{"second", "first"}, closingOrder)
+}
+
+type testSessionProtocol struct{}
+
+func (testSessionProtocol) MarkAsPush(message string) (string, error) {
+	return "push:" + message, nil
+}
+
+func (testSessionProtocol) IsPush(message string) bool {
+	return strings.HasPrefix(message, "push:")
+}
+
+func (testSessionProtocol) MarkAsCall(
+	message string,
+	callID uint64,
+) (string, error) {
+	return fmt.Sprintf("call:%d:%s", callID, message), nil
+}
+
+func (testSessionProtocol) CallID(message string) mo.Option[uint64] {
+	return parseTestCallID(message, "call:")
+}
+
+func (testSessionProtocol) MarkAsCallReply(
+	reply string,
+	callID uint64,
+) (string, error) {
+	return fmt.Sprintf("reply:%d:%s", callID, reply), nil
+}
+
+func (testSessionProtocol) CallReplyID(request string) mo.Option[uint64] {
+	return parseTestCallID(request, "reply:")
+}
+
+func parseTestCallID(message string, prefix string) mo.Option[uint64] {
+	rest, isFound := strings.CutPrefix(message, prefix)
+	if !isFound {
+		return mo.None[uint64]()
+	}
+
+	rawCallID, _, _ := strings.Cut(rest, ":")
+	callID, err := strconv.ParseUint(rawCallID, 10, 64)
+	if err != nil {
+		return mo.None[uint64]()
+	}
+
+	return mo.Some(callID)
+}
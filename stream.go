@@ -0,0 +1,37 @@
+package tcpServer
+
+import (
+	"context"
+)
+
+// ServerStream allows the request handler to exchange extra messages
+// with the peer while handling a single request,
+// e.g. to receive the continuation of a request that was split into frames
+type ServerStream[Req Request, Resp Response] interface {
+	ReadRequest() (Req, error)
+	WriteResponse(response Resp) error
+}
+
+// ClientStream allows the client to perform an arbitrary exchange
+// with the server, e.g. to send a request that was split into frames
+type ClientStream[Req Request, Resp Response] interface {
+	WriteRequest(request Req) error
+	ReadResponse() (Resp, error)
+}
+
+type serverStreamContextKey struct{}
+
+func ServerStreamFromContext[Req Request, Resp Response](
+	ctx context.Context,
+) (ServerStream[Req, Resp], bool) {
+	stream, isPresent :=
+		ctx.Value(serverStreamContextKey{}).(ServerStream[Req, Resp])
+	return stream, isPresent
+}
+
+func ContextWithServerStream[Req Request, Resp Response](
+	ctx context.Context,
+	stream ServerStream[Req, Resp],
+) context.Context {
+	return context.WithValue(ctx, serverStreamContextKey{}, stream)
+}
@@ -0,0 +1,138 @@
+package tcpServer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+var (
+	ErrCompressionCodecIsNotFlushable = errors.New(
+		"compression codec is not flushable",
+	)
+)
+
+type StreamCompressionOptions struct {
+	// Codec should provide the writer with the `Flush() error` method,
+	// as both the gzip and deflate codecs do; note that they compress
+	// the small flushed writes effectively only at the high levels (7-9)
+	Codec CompressionCodec
+}
+
+func NewStreamCompressionConnectionMiddleware(
+	options StreamCompressionOptions,
+) ConnectionMiddleware {
+	return func(handler ConnectionHandler) ConnectionHandler {
+		return ConnectionHandlerFunc(func(
+			ctx context.Context,
+			connection net.Conn,
+		) error {
+			return handler.HandleConnection(
+				ctx,
+				NewCompressedConnection(connection, options),
+			)
+		})
+	}
+}
+
+type flushableWriter interface {
+	io.WriteCloser
+
+	Flush() error
+}
+
+// CompressedConnection compresses the whole stream of the connection,
+// so the repeating parts of the different messages are compressed as well;
+// each write is flushed, so the peer receives the message immediately;
+// the decompressor keeps its state between the reads and fails permanently
+// on an interrupted one (e.g. by the read timeout), so close the connection
+// after any reading error
+type CompressedConnection struct {
+	net.Conn
+
+	options     StreamCompressionOptions
+	readingLock sync.Mutex
+	reader      io.ReadCloser
+	writingLock sync.Mutex
+	writer      flushableWriter
+}
+
+func NewCompressedConnection(
+	connection net.Conn,
+	options StreamCompressionOptions,
+) *CompressedConnection {
+	return &CompressedConnection{
+		Conn:    connection,
+		options: options,
+	}
+}
+
+func (connection *CompressedConnection) Read(buffer []byte) (int, error) {
+	connection.readingLock.Lock()
+	defer connection.readingLock.Unlock()
+
+	// some decompressors (e.g. the gzip one) read the header on construction,
+	// so the construction is delayed until the peer sends something
+	if connection.reader == nil {
+		reader, err := connection.options.Codec.NewReader(connection.Conn)
+		if err != nil {
+			return 0, fmt.Errorf("unable to construct the reader: %w", err)
+		}
+
+		connection.reader = reader
+	}
+
+	return connection.reader.Read(buffer)
+}
+
+func (connection *CompressedConnection) Write(buffer []byte) (int, error) {
+	connection.writingLock.Lock()
+	defer connection.writingLock.Unlock()
+
+	if connection.writer == nil {
+		writer, err := connection.options.Codec.NewWriter(connection.Conn)
+		if err != nil {
+			return 0, fmt.Errorf("unable to construct the writer: %w", err)
+		}
+
+		flushableWriter, isFlushable := writer.(flushableWriter)
+		if !isFlushable {
+			return 0, ErrCompressionCodecIsNotFlushable
+		}
+
+		connection.writer = flushableWriter
+	}
+
+	n, err := connection.writer.Write(buffer)
+	if err != nil {
+		return n, fmt.Errorf("unable to write the data: %w", err)
+	}
+
+	if err := connection.writer.Flush(); err != nil {
+		return n, fmt.Errorf("unable to flush the data: %w", err)
+	}
+
+	return n, nil
+}
+
+// Close finishes the compressed stream before closing the connection,
+// unless the writing is in progress, because it can block the closing
+func (connection *CompressedConnection) Close() error {
+	var writerClosingErr error
+	if connection.writingLock.TryLock() {
+		if connection.writer != nil {
+			writerClosingErr = connection.writer.Close()
+		}
+
+		connection.writingLock.Unlock()
+	}
+
+	if err := connection.Conn.Close(); err != nil {
+		return err
+	}
+
+	return writerClosingErr
+}
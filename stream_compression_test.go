@@ -0,0 +1,160 @@
+package tcpServer_test
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestNewStreamCompressionConnectionMiddleware(test *testing.T) {
+	for _, data := range []struct {
+		name  string
+		codec tcpServer.CompressionCodec
+	}{
+		{
+			name: "gzip",
+			codec: tcpServer.NewGzipCodec(tcpServer.CompressionCodecOptions{
+				Level: mo.Some(gzip.BestCompression),
+			}),
+		},
+		{
+			name: "deflate",
+			codec: tcpServer.NewDeflateCodec(tcpServer.CompressionCodecOptions{
+				Level: mo.Some(flate.BestCompression),
+			}),
+		},
+		{
+			name: "zlib",
+			codec: tcpServer.NewZlibCodec(tcpServer.CompressionCodecOptions{
+				Level: mo.Some(zlib.BestCompression),
+			}),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			listener, err := net.Listen(tcpServer.TCPServerNetwork, "localhost:0")
+			require.NoError(test, err)
+			defer listener.Close()
+
+			var readByteCount atomic.Int64
+			options := tcpServer.StreamCompressionOptions{Codec: data.codec}
+			handler := tcpServer.NewStreamCompressionConnectionMiddleware(options)(
+				tcpServer.NewDefaultConnectionHandler(
+					tcpServer.DefaultConnectionHandlerOptions[string, string]{
+						ServerProtocol: testLineProtocol{},
+						RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+							ctx context.Context,
+							request string,
+						) (string, error) {
+							return "response to " + request, nil
+						}),
+					},
+				),
+			)
+
+			handlingErr := make(chan error, 1)
+			go func() {
+				connection, err := listener.Accept()
+				if err != nil {
+					handlingErr <- err
+					return
+				}
+				defer connection.Close()
+
+				handlingErr <- handler.HandleConnection(
+					context.Background(),
+					testCountingConnection{Conn: connection, count: &readByteCount},
+				)
+			}()
+
+			client, err := tcpServer.NewTCPClient(
+				context.Background(),
+				listener.Addr().String(),
+				tcpServer.TCPClientOptions[string, string]{
+					StreamCompression: mo.Some(options),
+					ClientProtocol:    testLineProtocol{},
+				},
+			)
+			require.NoError(test, err)
+
+			request := strings.Repeat("request ", 10)
+			for range 10 {
+				response, err := client.SendRequest(request)
+				require.NoError(test, err)
+
+				assert.Equal(test, "response to "+request, response)
+			}
+
+			require.NoError(test, client.Close())
+			assert.NoError(test, <-handlingErr)
+
+			// the repeating requests are compressed across the messages,
+			// so all ten of them take less than two uncompressed ones
+			assert.Less(test, readByteCount.Load(), int64(2*len(request)))
+		})
+	}
+}
+
+func TestCompressedConnection_withNotFlushableCodec(test *testing.T) {
+	serverConnection, clientConnection := net.Pipe()
+	defer serverConnection.Close()
+	defer clientConnection.Close()
+
+	connection := tcpServer.NewCompressedConnection(
+		clientConnection,
+		tcpServer.StreamCompressionOptions{Codec: testNotFlushableCodec{}},
+	)
+
+	_, err := connection.Write([]byte("data"))
+
+	assert.ErrorIs(test, err, tcpServer.ErrCompressionCodecIsNotFlushable)
+}
+
+type testCountingConnection struct {
+	net.Conn
+
+	count *atomic.Int64
+}
+
+func (connection testCountingConnection) Read(buffer []byte) (int, error) {
+	n, err := connection.Conn.Read(buffer)
+	connection.count.Add(int64(n))
+
+	return n, err
+}
+
+type testNotFlushableCodec struct{}
+
+func (testNotFlushableCodec) Name() string {
+	return "not-flushable"
+}
+
+func (testNotFlushableCodec) NewWriter(
+	writer io.Writer,
+) (io.WriteCloser, error) {
+	return testNopWriteCloser{Writer: writer}, nil
+}
+
+func (testNotFlushableCodec) NewReader(
+	reader io.Reader,
+) (io.ReadCloser, error) {
+	return io.NopCloser(reader), nil
+}
+
+type testNopWriteCloser struct {
+	io.Writer
+}
+
+func (testNopWriteCloser) Close() error {
+	return nil
+}
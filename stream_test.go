@@ -0,0 +1,104 @@
+package tcpServer_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestServerStreamFromContext(test *testing.T) {
+	_, isPresent := tcpServer.ServerStreamFromContext[string, string](
+		context.Background(),
+	)
+
+	assert.False(test, isPresent)
+}
+
+func TestTCPClient_Exchange(test *testing.T) {
+	serverConnection, clientConnection := net.Pipe()
+	defer serverConnection.Close()
+
+	handler := tcpServer.NewDefaultConnectionHandler(
+		tcpServer.DefaultConnectionHandlerOptions[string, string]{
+			ServerProtocol: testLineProtocol{},
+			RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+				ctx context.Context,
+				request string,
+			) (string, error) {
+				stream, isPresent :=
+					tcpServer.ServerStreamFromContext[string, string](ctx)
+				require.True(test, isPresent)
+
+				parts := []string{request}
+				for {
+					part, err := stream.ReadRequest()
+					require.NoError(test, err)
+
+					if part == "end" {
+						break
+					}
+
+					parts = append(parts, part)
+					require.NoError(test, stream.WriteResponse("ack:"+part))
+				}
+
+				return strings.Join(parts, ","), nil
+			}),
+		},
+	)
+
+	handlingErr := make(chan error, 1)
+	go func() {
+		handlingErr <- handler.HandleRequest(
+			context.Background(),
+			serverConnection,
+			bufio.NewScanner(serverConnection),
+		)
+	}()
+
+	client := tcpServer.NewTCPClientFromConnection(
+		clientConnection,
+		tcpServer.TCPClientOptions[string, string]{
+			ClientProtocol: testLineProtocol{},
+		},
+	)
+	defer client.Close()
+
+	var gotResponses []string
+	err := client.Exchange(func(
+		stream tcpServer.ClientStream[string, string],
+	) error {
+		if err := stream.WriteRequest("one"); err != nil {
+			return err
+		}
+
+		for _, request := range []string{"two", "three", "end"} {
+			if err := stream.WriteRequest(request); err != nil {
+				return err
+			}
+
+			response, err := stream.ReadResponse()
+			if err != nil {
+				return err
+			}
+
+			gotResponses = append(gotResponses, response)
+		}
+
+		return nil
+	})
+	require.NoError(test, err)
+
+	assert.Equal(
+		test,
+		[]string{"ack:two", "ack:three", "one,two,three"},
+		gotResponses,
+	)
+	assert.NoError(test, <-handlingErr)
+}
@@ -0,0 +1,12 @@
+package tcpServer
+
+type StreamingProtocol[Req Request, Resp Response] interface {
+	IsStreamingRequest(request Req) bool
+	NewEndOfStreamResponse() (Resp, error)
+	IsEndOfStreamResponse(response Resp) bool
+}
+
+type StreamingOptions[Req Request, Resp Response] struct {
+	Protocol       StreamingProtocol[Req, Resp]
+	RequestHandler StreamingRequestHandler[Req, Resp]
+}
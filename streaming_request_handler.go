@@ -0,0 +1,39 @@
+package tcpServer
+
+import (
+	"context"
+)
+
+type ResponseSender[Resp Response] interface {
+	SendResponse(response Resp) error
+}
+
+type ResponseSenderFunc[Resp Response] func(response Resp) error
+
+func (f ResponseSenderFunc[Resp]) SendResponse(response Resp) error {
+	return f(response)
+}
+
+// StreamingRequestHandler sends any number of responses to a single request;
+// the end of the stream is marked after the handler returns
+type StreamingRequestHandler[Req Request, Resp Response] interface {
+	HandleStreamingRequest(
+		ctx context.Context,
+		request Req,
+		sender ResponseSender[Resp],
+	) error
+}
+
+type StreamingRequestHandlerFunc[Req Request, Resp Response] func(
+	ctx context.Context,
+	request Req,
+	sender ResponseSender[Resp],
+) error
+
+func (f StreamingRequestHandlerFunc[Req, Resp]) HandleStreamingRequest(
+	ctx context.Context,
+	request Req,
+	sender ResponseSender[Resp],
+) error {
+	return f(ctx, request, sender)
+}
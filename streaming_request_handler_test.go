@@ -0,0 +1,108 @@
+package tcpServer_test
+
+import (
+	"context"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestResponseSenderFunc_interface(test *testing.T) {
+	type response string
+
+	assert.Implements(
+		test,
+		(*tcpServer.ResponseSender[response])(nil),
+		tcpServer.ResponseSenderFunc[response](nil),
+	)
+}
+
+func TestStreamingRequestHandlerFunc_interface(test *testing.T) {
+	type request string
+	type response string
+
+	assert.Implements(
+		test,
+		(*tcpServer.StreamingRequestHandler[request, response])(nil),
+		tcpServer.StreamingRequestHandlerFunc[request, response](nil),
+	)
+}
+
+func TestStreamingRequestHandlerFunc_HandleStreamingRequest(
+	test *testing.T,
+) {
+	type request string
+	type response string
+	type args struct {
+		ctx     context.Context
+		request request
+	}
+
+	for _, data := range []struct {
+		name          string
+		f             tcpServer.StreamingRequestHandlerFunc[request, response]
+		args          args
+		wantResponses []response
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			f: func(
+				ctx context.Context,
+				request request,
+				sender tcpServer.ResponseSender[response],
+			) error {
+				for _, suffix := range []string{"one", "two"} {
+					if err := sender.SendResponse(
+						response(string(request) + "/" + suffix),
+					); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			},
+			args: args{
+				ctx:     context.Background(),
+				request: "request",
+			},
+			wantResponses: []response{"request/one", "request/two"},
+			wantErr:       assert.NoError,
+		},
+		{
+			name: "error",
+			f: func(
+				ctx context.Context,
+				request request,
+				sender tcpServer.ResponseSender[response],
+			) error {
+				return iotest.ErrTimeout
+			},
+			args: args{
+				ctx:     context.Background(),
+				request: "request",
+			},
+			wantResponses: nil,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var gotResponses []response
+			err := data.f.HandleStreamingRequest(
+				data.args.ctx,
+				data.args.request,
+				tcpServer.ResponseSenderFunc[response](func(response response) error {
+					gotResponses = append(gotResponses, response)
+					return nil
+				}),
+			)
+
+			assert.Equal(test, data.wantResponses, gotResponses)
+			data.wantErr(test, err)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package tcpServer_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestTCPClient_SendStreamingRequest(test *testing.T) {
+	for _, data := range []struct {
+		name             string
+		request          string
+		maxResponseCount int
+		wantResponses    []string
+		wantErr          assert.ErrorAssertionFunc
+		wantNextResponse string
+	}{
+		{
+			name:             "success/with all the responses",
+			request:          "stream:3",
+			maxResponseCount: 10,
+			wantResponses:    []string{"response #1", "response #2", "response #3"},
+			wantErr:          assert.NoError,
+			wantNextResponse: "plain:next",
+		},
+		{
+			name:             "success/without responses",
+			request:          "stream:0",
+			maxResponseCount: 10,
+			wantResponses:    nil,
+			wantErr:          assert.NoError,
+			wantNextResponse: "plain:next",
+		},
+		{
+			name:             "success/with the stopped iteration",
+			request:          "stream:3",
+			maxResponseCount: 1,
+			wantResponses:    []string{"response #1"},
+			wantErr:          assert.NoError,
+			wantNextResponse: "plain:next",
+		},
+		{
+			name:             "error/with the failed handling",
+			request:          "stream:invalid",
+			maxResponseCount: 10,
+			wantResponses:    nil,
+			wantErr:          assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			serverConnection, clientConnection := net.Pipe()
+			defer serverConnection.Close()
+
+			handler := tcpServer.NewDefaultConnectionHandler(
+				tcpServer.DefaultConnectionHandlerOptions[string, string]{
+					Streaming: mo.Some(tcpServer.StreamingOptions[string, string]{
+						Protocol: testStreamingProtocol{},
+						RequestHandler: tcpServer.StreamingRequestHandlerFunc[
+							string,
+							string,
+						](func(
+							ctx context.Context,
+							request string,
+							sender tcpServer.ResponseSender[string],
+						) error {
+							responseCount, err :=
+								strconv.Atoi(strings.TrimPrefix(request, "stream:"))
+							if err != nil {
+								return err
+							}
+
+							for index := range responseCount {
+								response := fmt.Sprintf("response #%d", index+1)
+								if err := sender.SendResponse(response); err != nil {
+									return err
+								}
+							}
+
+							return nil
+						}),
+					}),
+					ServerProtocol: testLineProtocol{},
+					RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+						ctx context.Context,
+						request string,
+					) (string, error) {
+						return "plain:" + request, nil
+					}),
+				},
+			)
+			go func() {
+				defer serverConnection.Close()
+
+				handler.HandleConnection( //nolint:errcheck
+					context.Background(),
+					serverConnection,
+				)
+			}()
+
+			client := tcpServer.NewTCPClientFromConnection(
+				clientConnection,
+				tcpServer.TCPClientOptions[string, string]{
+					StreamingProtocol: mo.Some[tcpServer.StreamingProtocol[
+						string,
+						string,
+					]](testStreamingProtocol{}),
+					ClientProtocol: testLineProtocol{},
+				},
+			)
+			defer client.Close()
+
+			var gotResponses []string
+			var gotErr error
+			for response, err := range client.SendStreamingRequest(data.request) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+
+				gotResponses = append(gotResponses, response)
+				if len(gotResponses) == data.maxResponseCount {
+					break
+				}
+			}
+
+			assert.Equal(test, data.wantResponses, gotResponses)
+			data.wantErr(test, gotErr)
+
+			if gotErr != nil {
+				return
+			}
+
+			nextResponse, err := client.SendRequest("next")
+			require.NoError(test, err)
+			assert.Equal(test, data.wantNextResponse, nextResponse)
+		})
+	}
+}
+
+type testStreamingProtocol struct{}
+
+func (testStreamingProtocol) IsStreamingRequest(request string) bool {
+	return strings.HasPrefix(request, "stream:")
+}
+
+func (testStreamingProtocol) NewEndOfStreamResponse() (string, error) {
+	return "end", nil
+}
+
+func (testStreamingProtocol) IsEndOfStreamResponse(response string) bool {
+	return response == "end"
+}
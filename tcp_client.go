@@ -5,8 +5,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"net"
 	"slices"
+	"sync"
 	"time"
 
 	"github.com/samber/mo"
@@ -20,15 +22,39 @@ type ClientProtocol[Req Request, Resp Response] interface {
 }
 
 type TCPClientOptions[Req Request, Resp Response] struct {
-	ReadTimeout    mo.Option[time.Duration]
-	WriteTimeout   mo.Option[time.Duration]
+	ReadTimeout       mo.Option[time.Duration]
+	WriteTimeout      mo.Option[time.Duration]
+	KeepAlive         mo.Option[net.KeepAliveConfig]
+	Heartbeat         mo.Option[HeartbeatOptions[Req, Resp]]
+	StreamingProtocol mo.Option[StreamingProtocol[Req, Resp]]
+	Session           mo.Option[ClientSessionOptions[Req, Resp]]
+	Handshake         mo.Option[ClientHandshakeOptions[Req, Resp]]
+	// PSKEncryption is applied only by `NewTCPClient()`,
+	// otherwise wrap the connection via `NewPSKEncryptedClientConnection()`
+	PSKEncryption mo.Option[PSKEncryptionOptions]
+	// StreamCompression is applied only by `NewTCPClient()`,
+	// otherwise wrap the connection via `NewCompressedConnection()`
+	StreamCompression mo.Option[StreamCompressionOptions]
+	// TLSUpgrade is applied only by `NewTCPClient()`
+	// and `NewTCPClientWithHandshake()`
+	TLSUpgrade mo.Option[TLSUpgradeOptions[Req, Resp]]
+	// Authentication is applied only by `NewTCPClient()`,
+	// otherwise call `TCPClient.Authenticate()`
+	Authentication mo.Option[ClientAuthenticationOptions[Req, Resp]]
 	ClientProtocol ClientProtocol[Req, Resp]
 }
 
 type TCPClient[Req Request, Resp Response] struct {
-	options    TCPClientOptions[Req, Resp]
-	connection net.Conn
-	scanner    *bufio.Scanner
+	options         TCPClientOptions[Req, Resp]
+	connection      net.Conn
+	scanner         *bufio.Scanner
+	exchangeLock    *sync.Mutex
+	writingLock     *sync.Mutex
+	reading         mo.Option[*tcpClientReading[Resp]]
+	pongs           chan struct{}
+	handshakeResult mo.Option[HandshakeResult]
+	closed          chan struct{}
+	closingOnce     *sync.Once
 }
 
 func NewTCPClient[Req Request, Resp Response](
@@ -37,6 +63,10 @@ func NewTCPClient[Req Request, Resp Response](
 	options TCPClientOptions[Req, Resp],
 ) (TCPClient[Req, Resp], error) {
 	var dialer net.Dialer
+	if keepAlive, isPresent := options.KeepAlive.Get(); isPresent {
+		dialer.KeepAliveConfig = keepAlive
+	}
+
 	connection, err := dialer.DialContext(ctx, TCPServerNetwork, address)
 	if err != nil {
 		return TCPClient[Req, Resp]{}, fmt.Errorf(
@@ -46,49 +76,312 @@ func NewTCPClient[Req Request, Resp Response](
 		)
 	}
 
-	return NewTCPClientFromConnection(connection, options), nil
+	if pskEncryption, isPresent := options.PSKEncryption.Get(); isPresent {
+		encryptedConnection, err :=
+			NewPSKEncryptedClientConnection(connection, pskEncryption)
+		if err != nil {
+			connection.Close() //nolint:errcheck
+			return TCPClient[Req, Resp]{}, err
+		}
+
+		connection = encryptedConnection
+	}
+
+	if streamCompression, isPresent :=
+		options.StreamCompression.Get(); isPresent {
+		connection = NewCompressedConnection(connection, streamCompression)
+	}
+
+	client, err := NewTCPClientWithHandshake(connection, options)
+	if err != nil {
+		connection.Close() //nolint:errcheck
+		return TCPClient[Req, Resp]{}, err
+	}
+
+	if authentication, isPresent := options.Authentication.Get(); isPresent {
+		if err := client.Authenticate(authentication); err != nil {
+			client.Close() //nolint:errcheck
+			return TCPClient[Req, Resp]{}, err
+		}
+	}
+
+	return client, nil
+}
+
+// NewTCPClientWithHandshake performs the handshake and then the TLS upgrade
+// before the construction of the client, if they're specified in the options;
+// unlike it, `NewTCPClientFromConnection()` ignores these options
+func NewTCPClientWithHandshake[Req Request, Resp Response](
+	connection net.Conn,
+	options TCPClientOptions[Req, Resp],
+) (TCPClient[Req, Resp], error) {
+	var handshakeResult mo.Option[HandshakeResult]
+	if handshake, isPresent := options.Handshake.Get(); isPresent {
+		result, clientProtocol, handshakenConnection, err :=
+			performClientHandshake(connection, handshake)
+		if err != nil {
+			return TCPClient[Req, Resp]{}, fmt.Errorf(
+				"unable to perform the handshake: %w",
+				err,
+			)
+		}
+
+		options.ClientProtocol = clientProtocol
+		if !result.Streaming {
+			options.StreamingProtocol = mo.None[StreamingProtocol[Req, Resp]]()
+		}
+
+		connection = handshakenConnection
+		handshakeResult = mo.Some(result)
+	}
+
+	if tlsUpgrade, isPresent := options.TLSUpgrade.Get(); isPresent {
+		upgradedConnection, err :=
+			performClientTLSUpgrade(connection, options, tlsUpgrade)
+		if err != nil {
+			return TCPClient[Req, Resp]{}, fmt.Errorf(
+				"unable to upgrade the connection to TLS: %w",
+				err,
+			)
+		}
+
+		connection = upgradedConnection
+	}
+
+	client := NewTCPClientFromConnection(connection, options)
+	client.handshakeResult = handshakeResult
+
+	return client, nil
 }
 
 func NewTCPClientFromConnection[Req Request, Resp Response](
 	connection net.Conn,
 	options TCPClientOptions[Req, Resp],
 ) TCPClient[Req, Resp] {
-	return TCPClient[Req, Resp]{
+	client := TCPClient[Req, Resp]{
 		options:    options,
 		connection: connection,
 		scanner: InitializeScanner(InitializeScannerParams[Req, Resp]{
 			Reader:       connection,
 			BaseProtocol: options.ClientProtocol,
 		}),
+		exchangeLock: &sync.Mutex{},
+		writingLock:  &sync.Mutex{},
+		pongs:        make(chan struct{}, 1),
+		closed:       make(chan struct{}),
+		closingOnce:  &sync.Once{},
+	}
+	if options.Session.IsPresent() || options.Heartbeat.IsPresent() {
+		// the server can send the messages at any time
+		// and the pongs can arrive during the other exchanges,
+		// so they are read continuously
+		client.reading = mo.Some(newTCPClientReading[Resp]())
+		go client.runReading()
 	}
+	if heartbeat, isPresent := options.Heartbeat.Get(); isPresent {
+		go client.runHeartbeat(heartbeat)
+	}
+
+	return client
+}
+
+func (client TCPClient[Req, Resp]) HandshakeResult() mo.Option[HandshakeResult] { //nolint:lll
+	return client.handshakeResult
 }
 
 func (client TCPClient[Req, Resp]) SendRequest(request Req) (Resp, error) {
-	var zeroResponse Resp
+	client.exchangeLock.Lock()
+	defer client.exchangeLock.Unlock()
+
+	return client.sendRequest(request, client.options.ReadTimeout)
+}
+
+// SendStreamingRequest yields the responses until the end of the stream;
+// if the iteration is stopped early, the rest of the stream is skipped,
+// otherwise it would be taken for the responses to the next requests
+func (client TCPClient[Req, Resp]) SendStreamingRequest(
+	request Req,
+) iter.Seq2[Resp, error] {
+	return func(yield func(Resp, error) bool) {
+		var zeroResponse Resp
+
+		streamingProtocol, isPresent := client.options.StreamingProtocol.Get()
+		if !isPresent {
+			yield(zeroResponse, errors.New("streaming protocol is not specified"))
+			return
+		}
+
+		client.exchangeLock.Lock()
+		defer client.exchangeLock.Unlock()
+
+		if err := client.writeRequest(request); err != nil {
+			yield(zeroResponse, err)
+			return
+		}
+
+		isYielding := true
+		for {
+			response, err := client.readResponse(client.options.ReadTimeout)
+			if err != nil {
+				if isYielding {
+					yield(zeroResponse, err)
+				}
+
+				return
+			}
+			if streamingProtocol.IsEndOfStreamResponse(response) {
+				return
+			}
+
+			if isYielding {
+				isYielding = yield(response, nil)
+			}
+		}
+	}
+}
+
+// Exchange gives the exclusive access to the connection
+// for an arbitrary exchange with the server;
+// the stream must not be used after the exchange is finished
+func (client TCPClient[Req, Resp]) Exchange(
+	exchange func(stream ClientStream[Req, Resp]) error,
+) error {
+	client.exchangeLock.Lock()
+	defer client.exchangeLock.Unlock()
+
+	return exchange(tcpClientStream[Req, Resp]{client: client})
+}
+
+func (client TCPClient[Req, Resp]) Close() error {
+	client.closingOnce.Do(func() { close(client.closed) })
+
+	if err := client.connection.Close(); err != nil {
+		return fmt.Errorf("unable to close the connection: %w", err)
+	}
+
+	return nil
+}
+
+// runHeartbeat sends a ping per interval without waiting for the pong;
+// the pongs are taken by the reading and passed via the channel
+func (client TCPClient[Req, Resp]) runHeartbeat(
+	heartbeat HeartbeatOptions[Req, Resp],
+) {
+	ticker := time.NewTicker(heartbeat.Interval)
+	defer ticker.Stop()
+
+	var isPongPending bool
+	var missedPongCount int
+	for {
+		select {
+		case <-client.closed:
+			return
+
+		case <-client.pongs:
+			isPongPending = false
+			missedPongCount = 0
+			continue
+
+		case <-ticker.C:
+		}
+
+		// the current exchange is limited by its own timeouts
+		// and the server may answer the ping only after it,
+		// so the pongs aren't missed during the exchange
+		if !client.exchangeLock.TryLock() {
+			continue
+		}
+		client.exchangeLock.Unlock()
+
+		if isPongPending {
+			missedPongCount++
+			if missedPongCount >= max(heartbeat.MaxMissedPongs, 1) {
+				// the server is considered dead, so fail all the next requests fast
+				client.Close() //nolint:errcheck
+				return
+			}
+		}
+
+		if err := client.sendPing(heartbeat); err != nil {
+			client.Close() //nolint:errcheck
+			return
+		}
+		isPongPending = true
+	}
+}
+
+func (client TCPClient[Req, Resp]) sendPing(
+	heartbeat HeartbeatOptions[Req, Resp],
+) error {
+	pingRequest, err := heartbeat.Protocol.NewPingRequest()
+	if err != nil {
+		return fmt.Errorf("unable to construct the ping request: %w", err)
+	}
+
+	if err := client.writeRequest(pingRequest); err != nil {
+		return fmt.Errorf("unable to send the ping request: %w", err)
+	}
+
+	return nil
+}
+
+func (client TCPClient[Req, Resp]) sendRequest(
+	request Req,
+	readTimeout mo.Option[time.Duration],
+) (Resp, error) {
+	if err := client.writeRequest(request); err != nil {
+		var zeroResponse Resp
+		return zeroResponse, err
+	}
+
+	return client.readResponse(readTimeout)
+}
+
+func (client TCPClient[Req, Resp]) writeRequest(request Req) error {
+	client.writingLock.Lock()
+	defer client.writingLock.Unlock()
 
 	marshalledRequest, err := client.options.ClientProtocol.MarshalRequest(request)
 	if err != nil {
-		return zeroResponse, fmt.Errorf("unable to marshal the request: %w", err)
+		return fmt.Errorf("unable to marshal the request: %w", err)
 	}
 
 	if writeTimeout, isPresent := client.options.WriteTimeout.Get(); isPresent {
 		writeDeadline := time.Now().Add(writeTimeout)
 		if err := client.connection.SetWriteDeadline(writeDeadline); err != nil {
-			return zeroResponse, fmt.Errorf("unable to set the write deadline: %w", err)
+			return fmt.Errorf("unable to set the write deadline: %w", err)
 		}
 	}
 
 	if _, err := client.connection.Write(marshalledRequest); err != nil {
-		return zeroResponse, fmt.Errorf("unable to write the request: %w", err)
+		return fmt.Errorf("unable to write the request: %w", err)
+	}
+
+	return nil
+}
+
+func (client TCPClient[Req, Resp]) readResponse(
+	readTimeout mo.Option[time.Duration],
+) (Resp, error) {
+	var zeroResponse Resp
+
+	if reading, isPresent := client.reading.Get(); isPresent {
+		return client.waitForResponse(reading, readTimeout)
 	}
 
-	if readTimeout, isPresent := client.options.ReadTimeout.Get(); isPresent {
+	if readTimeout, isPresent := readTimeout.Get(); isPresent {
 		readDeadline := time.Now().Add(readTimeout)
 		if err := client.connection.SetReadDeadline(readDeadline); err != nil {
 			return zeroResponse, fmt.Errorf("unable to set the read deadline: %w", err)
 		}
 	}
 
+	return client.scanResponse()
+}
+
+func (client TCPClient[Req, Resp]) scanResponse() (Resp, error) {
+	var zeroResponse Resp
+
 	if isPossibleToContinue := client.scanner.Scan(); !isPossibleToContinue {
 		if err := client.scanner.Err(); err != nil {
 			return zeroResponse, fmt.Errorf("unable to read the response: %w", err)
@@ -110,10 +403,14 @@ func (client TCPClient[Req, Resp]) SendRequest(request Req) (Resp, error) {
 	return response, nil
 }
 
-func (client TCPClient[Req, Resp]) Close() error {
-	if err := client.connection.Close(); err != nil {
-		return fmt.Errorf("unable to close the connection: %w", err)
-	}
+type tcpClientStream[Req Request, Resp Response] struct {
+	client TCPClient[Req, Resp]
+}
 
-	return nil
+func (stream tcpClientStream[Req, Resp]) WriteRequest(request Req) error {
+	return stream.client.writeRequest(request)
+}
+
+func (stream tcpClientStream[Req, Resp]) ReadResponse() (Resp, error) {
+	return stream.client.readResponse(stream.client.options.ReadTimeout)
 }
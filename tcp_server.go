@@ -2,10 +2,13 @@ package tcpServer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"sync"
 	"sync/atomic"
+
+	"github.com/samber/mo"
 )
 
 const (
@@ -16,6 +19,7 @@ type ErrorHandler func(err error)
 
 type TCPServerOptions struct {
 	Address           string
+	KeepAlive         mo.Option[net.KeepAliveConfig]
 	ConnectionHandler ConnectionHandler
 	ErrorHandler      ErrorHandler
 }
@@ -31,6 +35,10 @@ func NewTCPServer(
 	options TCPServerOptions,
 ) (*TCPServer, error) {
 	var listenConfig net.ListenConfig
+	if keepAlive, isPresent := options.KeepAlive.Get(); isPresent {
+		listenConfig.KeepAliveConfig = keepAlive
+	}
+
 	listener, err := listenConfig.Listen(ctx, TCPServerNetwork, options.Address)
 	if err != nil {
 		return nil, fmt.Errorf(
@@ -68,12 +76,15 @@ func (server *TCPServer) Run(ctx context.Context) {
 
 		go func() {
 			defer waitGroup.Done()
-			defer connection.Close()
 
-			if err := server.options.ConnectionHandler.HandleConnection(
-				ctx,
-				connection,
-			); err != nil {
+			err := server.options.ConnectionHandler.HandleConnection(ctx, connection)
+			if errors.Is(err, ErrConnectionIsHijacked) {
+				// the hijack handler is responsible for closing the connection
+				return
+			}
+
+			connection.Close() //nolint:errcheck
+			if err != nil {
 				server.options.ErrorHandler(fmt.Errorf(
 					"unable to handle the connection: %w",
 					err,
@@ -0,0 +1,173 @@
+package tcpServer
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/samber/mo"
+)
+
+type ThrottlerOptions struct {
+	ConnectionReadLimit  mo.Option[BandwidthLimit]
+	ConnectionWriteLimit mo.Option[BandwidthLimit]
+	GlobalReadLimit      mo.Option[BandwidthLimit]
+	GlobalWriteLimit     mo.Option[BandwidthLimit]
+}
+
+type ThrottlingMetrics struct {
+	ReadThrottlingCount     int64
+	ReadThrottlingDuration  time.Duration
+	WriteThrottlingCount    int64
+	WriteThrottlingDuration time.Duration
+}
+
+// Throttler limits the bandwidth of each connection and of all
+// the connections handled via its middleware together
+type Throttler struct {
+	connectionReadLimit  bandwidthLimitHolder
+	connectionWriteLimit bandwidthLimitHolder
+	globalReadLimit      bandwidthLimitHolder
+	globalWriteLimit     bandwidthLimitHolder
+	globalReadBucket     *tokenBucket
+	globalWriteBucket    *tokenBucket
+	readMetrics          throttlingMetrics
+	writeMetrics         throttlingMetrics
+}
+
+func NewThrottler(options ThrottlerOptions) *Throttler {
+	throttler := &Throttler{}
+	throttler.globalReadBucket = newTokenBucket(&throttler.globalReadLimit)
+	throttler.globalWriteBucket = newTokenBucket(&throttler.globalWriteLimit)
+	throttler.SetLimits(options)
+
+	return throttler
+}
+
+func (throttler *Throttler) SetLimits(options ThrottlerOptions) {
+	throttler.connectionReadLimit.set(options.ConnectionReadLimit.Get())
+	throttler.connectionWriteLimit.set(options.ConnectionWriteLimit.Get())
+	throttler.globalReadLimit.set(options.GlobalReadLimit.Get())
+	throttler.globalWriteLimit.set(options.GlobalWriteLimit.Get())
+}
+
+func (throttler *Throttler) Metrics() ThrottlingMetrics {
+	return ThrottlingMetrics{
+		ReadThrottlingCount: throttler.readMetrics.count.Load(),
+		ReadThrottlingDuration: time.Duration(
+			throttler.readMetrics.duration.Load(),
+		),
+		WriteThrottlingCount: throttler.writeMetrics.count.Load(),
+		WriteThrottlingDuration: time.Duration(
+			throttler.writeMetrics.duration.Load(),
+		),
+	}
+}
+
+func (throttler *Throttler) Middleware() ConnectionMiddleware {
+	return func(handler ConnectionHandler) ConnectionHandler {
+		return ConnectionHandlerFunc(func(
+			ctx context.Context,
+			connection net.Conn,
+		) error {
+			throttledConnection := &throttledConnection{
+				Conn: connection,
+
+				reader: throttledDirection{
+					buckets: []*tokenBucket{
+						newTokenBucket(&throttler.connectionReadLimit),
+						throttler.globalReadBucket,
+					},
+					metrics: &throttler.readMetrics,
+				},
+				writer: throttledDirection{
+					buckets: []*tokenBucket{
+						newTokenBucket(&throttler.connectionWriteLimit),
+						throttler.globalWriteBucket,
+					},
+					metrics: &throttler.writeMetrics,
+				},
+			}
+			return handler.HandleConnection(ctx, throttledConnection)
+		})
+	}
+}
+
+type throttlingMetrics struct {
+	count    atomic.Int64
+	duration atomic.Int64
+}
+
+type throttledDirection struct {
+	buckets []*tokenBucket
+	metrics *throttlingMetrics
+}
+
+func (direction throttledDirection) maxChunkSize(size int) int {
+	for _, bucket := range direction.buckets {
+		if maxChunkSize, isPresent := bucket.maxChunkSize(); isPresent {
+			size = min(size, maxChunkSize)
+		}
+	}
+
+	return size
+}
+
+func (direction throttledDirection) wait(transferredByteCount int) {
+	var waitingDuration time.Duration
+	for _, bucket := range direction.buckets {
+		waitingDuration =
+			max(waitingDuration, bucket.reserve(transferredByteCount))
+	}
+	if waitingDuration == 0 {
+		return
+	}
+
+	direction.metrics.count.Add(1)
+	direction.metrics.duration.Add(int64(waitingDuration))
+
+	time.Sleep(waitingDuration)
+}
+
+type throttledConnection struct {
+	net.Conn
+
+	reader throttledDirection
+	writer throttledDirection
+}
+
+// Read throttles after the reading, because the count of the read bytes
+// is unknown in advance
+func (connection *throttledConnection) Read(buffer []byte) (int, error) {
+	if len(buffer) == 0 {
+		return connection.Conn.Read(buffer)
+	}
+
+	buffer = buffer[:connection.reader.maxChunkSize(len(buffer))]
+
+	n, err := connection.Conn.Read(buffer)
+	if n > 0 {
+		connection.reader.wait(n)
+	}
+
+	return n, err
+}
+
+func (connection *throttledConnection) Write(buffer []byte) (int, error) {
+	var totalN int
+	for len(buffer) > 0 {
+		chunkSize := connection.writer.maxChunkSize(len(buffer))
+		connection.writer.wait(chunkSize)
+
+		n, err := connection.Conn.Write(buffer[:chunkSize])
+		totalN += n
+		if err != nil {
+			return totalN, err
+		}
+
+		buffer = buffer[chunkSize:]
+	}
+
+	return totalN, nil
+}
@@ -0,0 +1,106 @@
+package tcpServer
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottler_Middleware(test *testing.T) {
+	for _, data := range []struct {
+		name            string
+		options         ThrottlerOptions
+		updatedOptions  mo.Option[ThrottlerOptions]
+		wantMinDuration time.Duration
+		wantThrottling  bool
+	}{
+		{
+			name:            "success/without limits",
+			options:         ThrottlerOptions{},
+			wantMinDuration: 0,
+			wantThrottling:  false,
+		},
+		{
+			name: "success/with a connection limit",
+			options: ThrottlerOptions{
+				ConnectionReadLimit:  mo.Some(BandwidthLimit{Rate: 1000, Burst: 100}),
+				ConnectionWriteLimit: mo.Some(BandwidthLimit{Rate: 1000, Burst: 100}),
+			},
+			wantMinDuration: 150 * time.Millisecond,
+			wantThrottling:  true,
+		},
+		{
+			name: "success/with a global limit",
+			options: ThrottlerOptions{
+				GlobalReadLimit:  mo.Some(BandwidthLimit{Rate: 1000, Burst: 100}),
+				GlobalWriteLimit: mo.Some(BandwidthLimit{Rate: 1000, Burst: 100}),
+			},
+			wantMinDuration: 150 * time.Millisecond,
+			wantThrottling:  true,
+		},
+		{
+			name: "success/with removed limits",
+			options: ThrottlerOptions{
+				GlobalReadLimit:  mo.Some(BandwidthLimit{Rate: 10, Burst: 10}),
+				GlobalWriteLimit: mo.Some(BandwidthLimit{Rate: 10, Burst: 10}),
+			},
+			updatedOptions:  mo.Some(ThrottlerOptions{}),
+			wantMinDuration: 0,
+			wantThrottling:  false,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			serverConnection, clientConnection := net.Pipe()
+			defer serverConnection.Close()
+			defer clientConnection.Close()
+
+			payload := strings.Repeat("d", 300)
+			go func() {
+				clientConnection.Write([]byte(payload)) //nolint:errcheck
+				io.Copy(io.Discard, clientConnection)   //nolint:errcheck
+			}()
+
+			throttler := NewThrottler(data.options)
+			if updatedOptions, isPresent := data.updatedOptions.Get(); isPresent {
+				throttler.SetLimits(updatedOptions)
+			}
+
+			handler := throttler.Middleware()(ConnectionHandlerFunc(func(
+				ctx context.Context,
+				connection net.Conn,
+			) error {
+				buffer := make([]byte, len(payload))
+				if _, err := io.ReadFull(connection, buffer); err != nil {
+					return err
+				}
+
+				_, err := connection.Write(buffer)
+				return err
+			}))
+
+			startTime := time.Now()
+			err := handler.HandleConnection(context.Background(), serverConnection)
+			elapsedTime := time.Since(startTime)
+
+			require.NoError(test, err)
+			assert.GreaterOrEqual(test, elapsedTime, data.wantMinDuration)
+
+			metrics := throttler.Metrics()
+			if data.wantThrottling {
+				assert.Positive(test, metrics.ReadThrottlingCount)
+				assert.Positive(test, metrics.ReadThrottlingDuration)
+				assert.Positive(test, metrics.WriteThrottlingCount)
+				assert.Positive(test, metrics.WriteThrottlingDuration)
+			} else {
+				assert.Equal(test, ThrottlingMetrics{}, metrics)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package tcpServer
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"os"
+	"time"
+
+	"github.com/samber/mo"
+)
+
+var (
+	ErrIdleTimeout                = errors.New("idle timeout")
+	ErrRequestReadTimeout         = errors.New("request read timeout")
+	ErrConnectionLifetimeExceeded = errors.New("connection lifetime exceeded")
+)
+
+type timeoutConnectionOptions struct {
+	idleTimeout        mo.Option[time.Duration]
+	requestReadTimeout mo.Option[time.Duration]
+	lifetimeDeadline   mo.Option[time.Time]
+}
+
+// timeoutConnection distinguishes the waiting for the first byte
+// of a request (the idle phase) from the reading of the rest of it
+// (the request phase); the phases are switched by the scanner's split
+// function, so it should be wrapped by `wrapSplitFunc()`
+type timeoutConnection struct {
+	net.Conn
+
+	options              timeoutConnectionOptions
+	isIdle               bool
+	isPhaseStartPending  bool
+	phaseStartTime       time.Time
+	externalReadDeadline time.Time
+}
+
+func newTimeoutConnection(
+	connection net.Conn,
+	options timeoutConnectionOptions,
+) *timeoutConnection {
+	return &timeoutConnection{
+		Conn: connection,
+
+		options:        options,
+		isIdle:         true,
+		phaseStartTime: time.Now(),
+	}
+}
+
+func (connection *timeoutConnection) Read(buffer []byte) (int, error) {
+	// the handling and the response writing don't count towards the phase
+	if connection.isPhaseStartPending {
+		connection.isPhaseStartPending = false
+		connection.phaseStartTime = time.Now()
+	}
+
+	deadline, deadlineErr := connection.readDeadline()
+	if err := connection.Conn.SetReadDeadline(deadline); err != nil {
+		return 0, err
+	}
+
+	n, err := connection.Conn.Read(buffer)
+	if n > 0 && connection.isIdle {
+		connection.isIdle = false
+		connection.phaseStartTime = time.Now()
+	}
+	if err != nil && deadlineErr != nil && errors.Is(err, os.ErrDeadlineExceeded) {
+		err = errors.Join(err, deadlineErr)
+	}
+
+	return n, err
+}
+
+func (connection *timeoutConnection) Write(buffer []byte) (int, error) {
+	n, err := connection.Conn.Write(buffer)
+	if err != nil && errors.Is(err, os.ErrDeadlineExceeded) &&
+		connection.isLifetimeExceeded() {
+		err = errors.Join(err, ErrConnectionLifetimeExceeded)
+	}
+
+	return n, err
+}
+
+func (connection *timeoutConnection) SetDeadline(deadline time.Time) error {
+	if err := connection.SetReadDeadline(deadline); err != nil {
+		return err
+	}
+
+	return connection.SetWriteDeadline(deadline)
+}
+
+// SetReadDeadline only stores the deadline,
+// because it's combined with the phase deadlines before each reading
+func (connection *timeoutConnection) SetReadDeadline(deadline time.Time) error {
+	connection.externalReadDeadline = deadline
+	return nil
+}
+
+func (connection *timeoutConnection) SetWriteDeadline(
+	deadline time.Time,
+) error {
+	if lifetimeDeadline, isPresent :=
+		connection.options.lifetimeDeadline.Get(); isPresent {
+		deadline = earliestDeadline(deadline, lifetimeDeadline)
+	}
+
+	return connection.Conn.SetWriteDeadline(deadline)
+}
+
+func (connection *timeoutConnection) wrapSplitFunc(
+	splitFunc bufio.SplitFunc,
+) bufio.SplitFunc {
+	return func(
+		data []byte,
+		isLatestData bool,
+	) (offsetToNextToken int, token []byte, err error) {
+		offsetToNextToken, token, err = splitFunc(data, isLatestData)
+		if token != nil {
+			// the buffered rest of the data means that the next request
+			// has already started
+			connection.isIdle = offsetToNextToken == len(data)
+			connection.isPhaseStartPending = true
+		}
+
+		return offsetToNextToken, token, err
+	}
+}
+
+func (connection *timeoutConnection) isLifetimeExceeded() bool {
+	lifetimeDeadline, isPresent := connection.options.lifetimeDeadline.Get()
+	return isPresent && !time.Now().Before(lifetimeDeadline)
+}
+
+func (connection *timeoutConnection) readDeadline() (time.Time, error) {
+	var deadline time.Time
+	var deadlineErr error
+	updateDeadline := func(candidate time.Time, candidateErr error) {
+		if deadline.IsZero() || candidate.Before(deadline) {
+			deadline = candidate
+			deadlineErr = candidateErr
+		}
+	}
+
+	if !connection.externalReadDeadline.IsZero() {
+		// don't classify the external deadline,
+		// it's the responsibility of its setter
+		updateDeadline(connection.externalReadDeadline, nil)
+	}
+
+	if connection.isIdle {
+		if idleTimeout, isPresent := connection.options.idleTimeout.Get(); isPresent {
+			updateDeadline(connection.phaseStartTime.Add(idleTimeout), ErrIdleTimeout)
+		}
+	} else {
+		if requestReadTimeout, isPresent :=
+			connection.options.requestReadTimeout.Get(); isPresent {
+			updateDeadline(
+				connection.phaseStartTime.Add(requestReadTimeout),
+				ErrRequestReadTimeout,
+			)
+		}
+	}
+
+	if lifetimeDeadline, isPresent :=
+		connection.options.lifetimeDeadline.Get(); isPresent {
+		updateDeadline(lifetimeDeadline, ErrConnectionLifetimeExceeded)
+	}
+
+	return deadline, deadlineErr
+}
+
+func earliestDeadline(deadline time.Time, otherDeadline time.Time) time.Time {
+	if deadline.IsZero() || otherDeadline.Before(deadline) {
+		return otherDeadline
+	}
+
+	return deadline
+}
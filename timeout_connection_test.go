@@ -0,0 +1,131 @@
+package tcpServer
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutConnection(test *testing.T) {
+	for _, data := range []struct {
+		name             string
+		options          func() timeoutConnectionOptions
+		writeInput       func(connection net.Conn)
+		handlingDuration time.Duration
+		wantTokens       []string
+		wantErr          error
+	}{
+		{
+			name: "success/request in several segments",
+			options: func() timeoutConnectionOptions {
+				return timeoutConnectionOptions{
+					idleTimeout:        mo.Some(time.Second),
+					requestReadTimeout: mo.Some(time.Second),
+				}
+			},
+			writeInput: func(connection net.Conn) {
+				for _, segment := range []string{"dum", "my #0\ndummy", " #1\n"} {
+					connection.Write([]byte(segment)) //nolint:errcheck
+
+					time.Sleep(10 * time.Millisecond)
+				}
+
+				connection.Close()
+			},
+			wantTokens: []string{"dummy #0", "dummy #1"},
+			wantErr:    nil,
+		},
+		{
+			name: "error/idle timeout after the long handling",
+			options: func() timeoutConnectionOptions {
+				return timeoutConnectionOptions{
+					idleTimeout:        mo.Some(50 * time.Millisecond),
+					requestReadTimeout: mo.Some(50 * time.Millisecond),
+				}
+			},
+			writeInput: func(connection net.Conn) {
+				connection.Write([]byte("dummy #0\n"))    //nolint:errcheck
+				connection.Write([]byte("dummy #1\ndum")) //nolint:errcheck
+				connection.Write([]byte("my #2\n"))       //nolint:errcheck
+			},
+			// the handling and the response writing don't count
+			// towards the timeouts
+			handlingDuration: 100 * time.Millisecond,
+			wantTokens:       []string{"dummy #0", "dummy #1", "dummy #2"},
+			wantErr:          ErrIdleTimeout,
+		},
+		{
+			name: "error/idle timeout",
+			options: func() timeoutConnectionOptions {
+				return timeoutConnectionOptions{
+					idleTimeout:        mo.Some(10 * time.Millisecond),
+					requestReadTimeout: mo.Some(time.Second),
+				}
+			},
+			writeInput: func(connection net.Conn) {
+				connection.Write([]byte("dummy #0\n")) //nolint:errcheck
+			},
+			wantTokens: []string{"dummy #0"},
+			wantErr:    ErrIdleTimeout,
+		},
+		{
+			name: "error/request read timeout",
+			options: func() timeoutConnectionOptions {
+				return timeoutConnectionOptions{
+					idleTimeout:        mo.Some(time.Second),
+					requestReadTimeout: mo.Some(10 * time.Millisecond),
+				}
+			},
+			writeInput: func(connection net.Conn) {
+				connection.Write([]byte("dummy #0\ndum")) //nolint:errcheck
+			},
+			wantTokens: []string{"dummy #0", "dum"},
+			wantErr:    ErrRequestReadTimeout,
+		},
+		{
+			name: "error/connection lifetime exceeded",
+			options: func() timeoutConnectionOptions {
+				return timeoutConnectionOptions{
+					idleTimeout:        mo.Some(time.Second),
+					requestReadTimeout: mo.Some(time.Second),
+					lifetimeDeadline:   mo.Some(time.Now().Add(50 * time.Millisecond)),
+				}
+			},
+			writeInput: func(connection net.Conn) {
+				connection.Write([]byte("dummy #0\n")) //nolint:errcheck
+			},
+			wantTokens: []string{"dummy #0"},
+			wantErr:    ErrConnectionLifetimeExceeded,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			serverConnection, clientConnection := net.Pipe()
+			defer serverConnection.Close()
+			defer clientConnection.Close()
+
+			go data.writeInput(clientConnection)
+
+			connection := newTimeoutConnection(serverConnection, data.options())
+			scanner := bufio.NewScanner(connection)
+			scanner.Split(connection.wrapSplitFunc(bufio.ScanLines))
+
+			var gotTokens []string
+			for scanner.Scan() {
+				gotTokens = append(gotTokens, scanner.Text())
+
+				time.Sleep(data.handlingDuration)
+			}
+
+			assert.Equal(test, data.wantTokens, gotTokens)
+			if data.wantErr != nil {
+				assert.ErrorIs(test, scanner.Err(), data.wantErr)
+			} else {
+				assert.NoError(test, scanner.Err())
+			}
+		})
+	}
+}
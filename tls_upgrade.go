@@ -0,0 +1,170 @@
+package tcpServer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/samber/mo"
+)
+
+var (
+	ErrTLSUpgradeIsRejected    = errors.New("TLS upgrade is rejected")
+	ErrTLSUpgradeIsUnavailable = errors.New("TLS upgrade is unavailable")
+	ErrAlreadyUpgradedToTLS    = errors.New("already upgraded to TLS")
+)
+
+// TLSUpgradeProtocol describes the reserved upgrade request and the replies
+// to it; after the acceptance is written, both peers switch to TLS
+type TLSUpgradeProtocol[Req Request, Resp Response] interface {
+	NewUpgradeRequest() (Req, error)
+	IsUpgradeRequest(request Req) bool
+	NewUpgradeAcceptanceResponse() (Resp, error)
+	NewUpgradeRejectionResponse(reason string) (Resp, error)
+	IsUpgradeAcceptanceResponse(response Resp) bool
+}
+
+type TLSUpgradeOptions[Req Request, Resp Response] struct {
+	Protocol         TLSUpgradeProtocol[Req, Resp]
+	Config           *tls.Config
+	HandshakeTimeout mo.Option[time.Duration]
+}
+
+// upgradeToTLS replies to the upgrade request and performs the TLS handshake;
+// the data buffered after the upgrade request and the authentication state
+// are discarded, because they were sent in plaintext and could be injected
+func (handler DefaultConnectionHandler[Req, Resp]) upgradeToTLS(
+	ctx context.Context,
+	session *Session[Req, Resp],
+	options TLSUpgradeOptions[Req, Resp],
+) error {
+	splitFunc, isPresent := session.splitFunc.Get()
+	if !isPresent {
+		return handler.rejectTLSUpgrade(session, options, ErrTLSUpgradeIsUnavailable)
+	}
+	if _, isUpgraded := session.TLSConnectionState(); isUpgraded {
+		return handler.rejectTLSUpgrade(session, options, ErrAlreadyUpgradedToTLS)
+	}
+
+	response, err := options.Protocol.NewUpgradeAcceptanceResponse()
+	if err != nil {
+		return fmt.Errorf(
+			"unable to construct the TLS upgrade acceptance response: %w",
+			err,
+		)
+	}
+
+	// nothing else can be written until the TLS handshake is finished
+	session.writingLock.Lock()
+	defer session.writingLock.Unlock()
+
+	if err := handler.writeResponse(session.connection, response); err != nil {
+		return err
+	}
+
+	// the timeouts are applied over the TLS connection
+	tlsConnection := tls.Server(session.rawConnection, options.Config)
+	if err := performTLSHandshake(
+		ctx,
+		tlsConnection,
+		options.HandshakeTimeout,
+	); err != nil {
+		return err
+	}
+
+	session.setHijackableConnection(tlsConnection, splitFunc)
+	session.tlsConnection.Store(tlsConnection)
+
+	// the authentication performed in plaintext could be tampered with,
+	// so it's discarded too and the peer should authenticate again
+	// (see RFC 3207, section 4.2)
+	session.principal.Store(nil)
+	session.authentication = serverAuthentication{}
+
+	return nil
+}
+
+func (handler DefaultConnectionHandler[Req, Resp]) rejectTLSUpgrade(
+	session *Session[Req, Resp],
+	options TLSUpgradeOptions[Req, Resp],
+	reason error,
+) error {
+	response, err := options.Protocol.NewUpgradeRejectionResponse(reason.Error())
+	if err != nil {
+		return fmt.Errorf(
+			"unable to construct the TLS upgrade rejection response: %w",
+			err,
+		)
+	}
+
+	return session.writeResponse(response)
+}
+
+// performClientTLSUpgrade reads the reply to the upgrade request
+// by the separate scanner, so the data buffered after the reply
+// is discarded along with it
+func performClientTLSUpgrade[Req Request, Resp Response](
+	connection net.Conn,
+	clientOptions TCPClientOptions[Req, Resp],
+	options TLSUpgradeOptions[Req, Resp],
+) (net.Conn, error) {
+	request, err := options.Protocol.NewUpgradeRequest()
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct the TLS upgrade request: %w", err)
+	}
+
+	client := NewTCPClientFromConnection(
+		connection,
+		TCPClientOptions[Req, Resp]{
+			ReadTimeout:    clientOptions.ReadTimeout,
+			WriteTimeout:   clientOptions.WriteTimeout,
+			ClientProtocol: clientOptions.ClientProtocol,
+		},
+	)
+	response, err := client.SendRequest(request)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send the TLS upgrade request: %w", err)
+	}
+	if !options.Protocol.IsUpgradeAcceptanceResponse(response) {
+		return nil, ErrTLSUpgradeIsRejected
+	}
+
+	tlsConnection := tls.Client(connection, options.Config)
+	if err := performTLSHandshake(
+		context.Background(),
+		tlsConnection,
+		options.HandshakeTimeout,
+	); err != nil {
+		return nil, err
+	}
+
+	return tlsConnection, nil
+}
+
+func performTLSHandshake(
+	ctx context.Context,
+	connection *tls.Conn,
+	handshakeTimeout mo.Option[time.Duration],
+) error {
+	// the deadlines set before the upgrade are replaced
+	var handshakeDeadline time.Time
+	if handshakeTimeout, isPresent := handshakeTimeout.Get(); isPresent {
+		handshakeDeadline = time.Now().Add(handshakeTimeout)
+	}
+	if err := connection.SetDeadline(handshakeDeadline); err != nil {
+		return fmt.Errorf("unable to set the handshake deadline: %w", err)
+	}
+
+	if err := connection.HandshakeContext(ctx); err != nil {
+		return fmt.Errorf("unable to perform the TLS handshake: %w", err)
+	}
+
+	if err := connection.SetDeadline(time.Time{}); err != nil {
+		return fmt.Errorf("unable to reset the deadlines: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,328 @@
+package tcpServer_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcpServer "github.com/thewizardplusplus/go-tcp-server"
+)
+
+func TestTLSUpgrade(test *testing.T) {
+	serverTLSConfig, clientTLSConfig := newTestTLSConfigs(test)
+
+	for _, data := range []struct {
+		name             string
+		serverTLSUpgrade mo.Option[tcpServer.TLSUpgradeOptions[string, string]]
+		wantUpgradeErr   assert.ErrorAssertionFunc
+		wantResponses    []string
+	}{
+		{
+			name: "success",
+			serverTLSUpgrade: mo.Some(tcpServer.TLSUpgradeOptions[string, string]{
+				Protocol: testTLSUpgradeProtocol{},
+				Config:   serverTLSConfig,
+			}),
+			wantUpgradeErr: assert.NoError,
+			wantResponses: []string{
+				"tls:request",
+				"tls-rejected:" + tcpServer.ErrAlreadyUpgradedToTLS.Error(),
+			},
+		},
+		{
+			name:             "error/without the server support",
+			serverTLSUpgrade: mo.None[tcpServer.TLSUpgradeOptions[string, string]](),
+			wantUpgradeErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...any,
+			) bool {
+				return assert.ErrorIs(test, err, tcpServer.ErrTLSUpgradeIsRejected)
+			},
+			wantResponses: nil,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			serverConnection, clientConnection := net.Pipe()
+			defer clientConnection.Close()
+
+			handler := newTestTLSUpgradeHandler(test, data.serverTLSUpgrade)
+			go func() {
+				defer serverConnection.Close()
+
+				handler.HandleConnection( //nolint:errcheck
+					context.Background(),
+					serverConnection,
+				)
+			}()
+
+			client, err := tcpServer.NewTCPClientWithHandshake(
+				clientConnection,
+				tcpServer.TCPClientOptions[string, string]{
+					TLSUpgrade: mo.Some(tcpServer.TLSUpgradeOptions[string, string]{
+						Protocol:         testTLSUpgradeProtocol{},
+						Config:           clientTLSConfig,
+						HandshakeTimeout: mo.Some(time.Second),
+					}),
+					ClientProtocol: testLineProtocol{},
+				},
+			)
+			data.wantUpgradeErr(test, err)
+			if err != nil {
+				return
+			}
+			defer client.Close()
+
+			var gotResponses []string
+			for _, request := range []string{"request", "starttls"} {
+				response, err := client.SendRequest(request)
+				require.NoError(test, err)
+
+				gotResponses = append(gotResponses, response)
+			}
+
+			assert.Equal(test, data.wantResponses, gotResponses)
+		})
+	}
+}
+
+func TestTLSUpgrade_withInjectedData(test *testing.T) {
+	serverTLSConfig, clientTLSConfig := newTestTLSConfigs(test)
+
+	serverConnection, clientConnection := net.Pipe()
+	defer clientConnection.Close()
+
+	handler := newTestTLSUpgradeHandler(
+		test,
+		mo.Some(tcpServer.TLSUpgradeOptions[string, string]{
+			Protocol: testTLSUpgradeProtocol{},
+			Config:   serverTLSConfig,
+		}),
+	)
+	go func() {
+		defer serverConnection.Close()
+
+		handler.HandleConnection( //nolint:errcheck
+			context.Background(),
+			serverConnection,
+		)
+	}()
+
+	// the request injected in plaintext is sent along with the upgrade request,
+	// so the server has it buffered by the scanner
+	_, err := clientConnection.Write([]byte("starttls\ninjected\n"))
+	require.NoError(test, err)
+
+	response, err := bufio.NewReader(clientConnection).ReadString('\n')
+	require.NoError(test, err)
+	require.Equal(test, "tls-accepted\n", response)
+
+	tlsConnection := tls.Client(clientConnection, clientTLSConfig)
+	require.NoError(test, tlsConnection.Handshake())
+
+	client := tcpServer.NewTCPClientFromConnection(
+		tlsConnection,
+		tcpServer.TCPClientOptions[string, string]{
+			ClientProtocol: testLineProtocol{},
+		},
+	)
+	defer client.Close()
+
+	response, err = client.SendRequest("request")
+	require.NoError(test, err)
+
+	assert.Equal(test, "tls:request", response)
+}
+
+func TestTLSUpgrade_withAuthentication(test *testing.T) {
+	serverTLSConfig, clientTLSConfig := newTestTLSConfigs(test)
+
+	serverConnection, clientConnection := net.Pipe()
+	defer clientConnection.Close()
+
+	handler := tcpServer.NewDefaultConnectionHandler(
+		tcpServer.DefaultConnectionHandlerOptions[string, string]{
+			TLSUpgrade: mo.Some(tcpServer.TLSUpgradeOptions[string, string]{
+				Protocol: testTLSUpgradeProtocol{},
+				Config:   serverTLSConfig,
+			}),
+			Authentication: mo.Some(tcpServer.AuthenticationOptions[string, string]{
+				Protocol: testAuthenticationProtocol{},
+				Mechanisms: []tcpServer.ServerAuthenticationMechanism{
+					testServerAuthenticationMechanism{},
+				},
+			}),
+			ServerProtocol: testLineProtocol{},
+			RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+				ctx context.Context,
+				request string,
+			) (string, error) {
+				principal, isPresent := tcpServer.PrincipalFromContext(ctx)
+				require.True(test, isPresent)
+
+				return principal.Name + ":" + request, nil
+			}),
+		},
+	)
+	go func() {
+		defer serverConnection.Close()
+
+		handler.HandleConnection( //nolint:errcheck
+			context.Background(),
+			serverConnection,
+		)
+	}()
+
+	authenticationOptions := tcpServer.ClientAuthenticationOptions[string, string]{
+		Protocol: testAuthenticationProtocol{},
+		Mechanism: testClientAuthenticationMechanism{
+			name:   "TEST",
+			answer: "secret",
+		},
+	}
+
+	plainClient := tcpServer.NewTCPClientFromConnection(
+		clientConnection,
+		tcpServer.TCPClientOptions[string, string]{
+			ClientProtocol: testLineProtocol{},
+		},
+	)
+	require.NoError(test, plainClient.Authenticate(authenticationOptions))
+
+	response, err := plainClient.SendRequest("starttls")
+	require.NoError(test, err)
+	require.Equal(test, "tls-accepted", response)
+
+	tlsConnection := tls.Client(clientConnection, clientTLSConfig)
+	require.NoError(test, tlsConnection.Handshake())
+
+	client := tcpServer.NewTCPClientFromConnection(
+		tlsConnection,
+		tcpServer.TCPClientOptions[string, string]{
+			ClientProtocol: testLineProtocol{},
+		},
+	)
+	defer client.Close()
+
+	// the plaintext authentication is discarded by the upgrade
+	response, err = client.SendRequest("request")
+	require.NoError(test, err)
+	assert.Equal(
+		test,
+		"failed:"+tcpServer.ErrAuthenticationIsRequired.Error(),
+		response,
+	)
+
+	require.NoError(test, client.Authenticate(authenticationOptions))
+
+	response, err = client.SendRequest("request")
+	require.NoError(test, err)
+	assert.Equal(test, "user:request", response)
+}
+
+func newTestTLSUpgradeHandler(
+	test *testing.T,
+	tlsUpgrade mo.Option[tcpServer.TLSUpgradeOptions[string, string]],
+) tcpServer.DefaultConnectionHandler[string, string] {
+	return tcpServer.NewDefaultConnectionHandler(
+		tcpServer.DefaultConnectionHandlerOptions[string, string]{
+			TLSUpgrade:     tlsUpgrade,
+			ServerProtocol: testLineProtocol{},
+			RequestHandler: tcpServer.RequestHandlerFunc[string, string](func(
+				ctx context.Context,
+				request string,
+			) (string, error) {
+				session, isPresent := tcpServer.SessionFromContext[string, string](ctx)
+				require.True(test, isPresent)
+
+				if _, isUpgraded := session.TLSConnectionState(); !isUpgraded {
+					return "plain:" + request, nil
+				}
+
+				return "tls:" + request, nil
+			}),
+		},
+	)
+}
+
+func newTestTLSConfigs(test *testing.T) (
+	serverTLSConfig *tls.Config,
+	clientTLSConfig *tls.Config,
+) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(test, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	certificateData, err := x509.CreateCertificate(
+		rand.Reader,
+		template,
+		template,
+		&privateKey.PublicKey,
+		privateKey,
+	)
+	require.NoError(test, err)
+
+	certificate, err := x509.ParseCertificate(certificateData)
+	require.NoError(test, err)
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(certificate)
+
+	serverTLSConfig = &tls.Config{
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{certificateData},
+			PrivateKey:  privateKey,
+		}},
+	}
+	clientTLSConfig = &tls.Config{
+		RootCAs:    rootCAs,
+		ServerName: "localhost",
+	}
+	return serverTLSConfig, clientTLSConfig
+}
+
+type testTLSUpgradeProtocol struct{}
+
+func (testTLSUpgradeProtocol) NewUpgradeRequest() (string, error) {
+	return "starttls", nil
+}
+
+func (testTLSUpgradeProtocol) IsUpgradeRequest(request string) bool {
+	return request == "starttls"
+}
+
+func (testTLSUpgradeProtocol) NewUpgradeAcceptanceResponse() (string, error) {
+	return "tls-accepted", nil
+}
+
+func (testTLSUpgradeProtocol) NewUpgradeRejectionResponse(
+	reason string,
+) (string, error) {
+	return "tls-rejected:" + reason, nil
+}
+
+func (testTLSUpgradeProtocol) IsUpgradeAcceptanceResponse(
+	response string,
+) bool {
+	return response == "tls-accepted"
+}
@@ -0,0 +1,101 @@
+package tcpServer
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type BandwidthLimit struct {
+	// Rate is measured in bytes per second
+	Rate float64
+	// Burst defaults to the one-second rate
+	Burst int
+}
+
+func (limit BandwidthLimit) burst() int {
+	if limit.Burst > 0 {
+		return limit.Burst
+	}
+
+	return max(int(limit.Rate), 1)
+}
+
+type bandwidthLimitHolder struct {
+	limit atomic.Pointer[BandwidthLimit]
+}
+
+func (holder *bandwidthLimitHolder) get() (BandwidthLimit, bool) {
+	limit := holder.limit.Load()
+	if limit == nil {
+		return BandwidthLimit{}, false
+	}
+
+	return *limit, true
+}
+
+func (holder *bandwidthLimitHolder) set(limit BandwidthLimit, isPresent bool) {
+	if !isPresent {
+		holder.limit.Store(nil)
+		return
+	}
+
+	holder.limit.Store(&limit)
+}
+
+// tokenBucket reads its limit on every reservation,
+// so the limit changes are applied immediately
+type tokenBucket struct {
+	limitHolder *bandwidthLimitHolder
+
+	lock           sync.Mutex
+	tokens         float64
+	lastRefillTime time.Time
+	isInitialized  bool
+}
+
+func newTokenBucket(limitHolder *bandwidthLimitHolder) *tokenBucket {
+	return &tokenBucket{
+		limitHolder: limitHolder,
+	}
+}
+
+func (bucket *tokenBucket) maxChunkSize() (int, bool) {
+	limit, isPresent := bucket.limitHolder.get()
+	if !isPresent {
+		return 0, false
+	}
+
+	return limit.burst(), true
+}
+
+// reserve takes the tokens, possibly going into debt,
+// and returns the time to wait before the debt is paid off
+func (bucket *tokenBucket) reserve(tokenCount int) time.Duration {
+	limit, isPresent := bucket.limitHolder.get()
+	if !isPresent || limit.Rate <= 0 {
+		return 0
+	}
+
+	bucket.lock.Lock()
+	defer bucket.lock.Unlock()
+
+	now := time.Now()
+	burst := float64(limit.burst())
+	if !bucket.isInitialized {
+		bucket.tokens = burst
+		bucket.isInitialized = true
+	} else {
+		elapsedTime := now.Sub(bucket.lastRefillTime)
+		bucket.tokens =
+			min(bucket.tokens+elapsedTime.Seconds()*limit.Rate, burst)
+	}
+	bucket.lastRefillTime = now
+
+	bucket.tokens -= float64(tokenCount)
+	if bucket.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-bucket.tokens / limit.Rate * float64(time.Second))
+}
@@ -0,0 +1,61 @@
+package tcpServer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket_reserve(test *testing.T) {
+	for _, data := range []struct {
+		name         string
+		limit        BandwidthLimit
+		isPresent    bool
+		tokenCounts  []int
+		wantDuration func(test *testing.T, duration time.Duration)
+	}{
+		{
+			name:        "success/without a limit",
+			isPresent:   false,
+			tokenCounts: []int{1000, 1000},
+			wantDuration: func(test *testing.T, duration time.Duration) {
+				assert.Zero(test, duration)
+			},
+		},
+		{
+			name:        "success/within the burst",
+			limit:       BandwidthLimit{Rate: 100, Burst: 200},
+			isPresent:   true,
+			tokenCounts: []int{100, 100},
+			wantDuration: func(test *testing.T, duration time.Duration) {
+				assert.Zero(test, duration)
+			},
+		},
+		{
+			name:        "success/beyond the burst",
+			limit:       BandwidthLimit{Rate: 100},
+			isPresent:   true,
+			tokenCounts: []int{100, 50},
+			wantDuration: func(test *testing.T, duration time.Duration) {
+				assert.InDelta(test, 500*time.Millisecond, duration, float64(
+					10*time.Millisecond,
+				))
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var limitHolder bandwidthLimitHolder
+			limitHolder.set(data.limit, data.isPresent)
+
+			bucket := newTokenBucket(&limitHolder)
+
+			var got time.Duration
+			for _, tokenCount := range data.tokenCounts {
+				got = bucket.reserve(tokenCount)
+			}
+
+			data.wantDuration(test, got)
+		})
+	}
+}